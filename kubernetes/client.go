@@ -6,10 +6,14 @@
 package kubernetes
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
+	"sync"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/connrotation"
@@ -20,6 +24,41 @@ type Client struct {
 	*kubernetes.Clientset
 
 	dialer *connrotation.Dialer
+
+	stopHealthCheck context.CancelFunc
+	healthCheckWg   sync.WaitGroup
+}
+
+// ClientOptions configures the Client.
+type ClientOptions struct {
+	// HealthCheckInterval enables the connection health checker if set.
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout is the timeout of a single health check request, defaults to HealthCheckInterval.
+	HealthCheckTimeout time.Duration
+	// OnStaleConnections is called (if set) when the health check fails and the connections are closed.
+	OnStaleConnections func(err error)
+}
+
+// ClientOption modifies ClientOptions.
+type ClientOption func(*ClientOptions)
+
+// WithHealthCheck enables the background connection health checker.
+//
+// The health checker requests kube-apiserver /livez every interval, and if the request fails
+// without getting any response (e.g. it times out on a half-open connection after a control plane
+// endpoint failover), all connections are closed, so that the next requests establish new ones.
+func WithHealthCheck(interval, timeout time.Duration) ClientOption {
+	return func(o *ClientOptions) {
+		o.HealthCheckInterval = interval
+		o.HealthCheckTimeout = timeout
+	}
+}
+
+// WithStaleConnectionsHandler sets the function called when the health checker closes the connections.
+func WithStaleConnectionsHandler(handler func(err error)) ClientOption {
+	return func(o *ClientOptions) {
+		o.OnStaleConnections = handler
+	}
 }
 
 // NewDialer creates new custom dialer.
@@ -28,11 +67,17 @@ func NewDialer() *connrotation.Dialer {
 }
 
 // NewForConfig initializes and returns a client using the provided config.
-func NewForConfig(config *rest.Config) (*Client, error) {
+func NewForConfig(config *rest.Config, opts ...ClientOption) (*Client, error) {
 	if config.Dial != nil {
 		return nil, fmt.Errorf("dialer is already set")
 	}
 
+	var options ClientOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	dialer := NewDialer()
 	config.Dial = dialer.DialContext
 
@@ -41,15 +86,87 @@ func NewForConfig(config *rest.Config) (*Client, error) {
 		return nil, err
 	}
 
-	return &Client{
+	client := &Client{
 		Clientset: clientset,
 		dialer:    dialer,
-	}, nil
+	}
+
+	if options.HealthCheckInterval > 0 {
+		client.startHealthCheck(options)
+	}
+
+	return client, nil
 }
 
 // Close all connections.
+//
+// The health checker (if enabled) is stopped.
 func (h *Client) Close() error {
+	if h.stopHealthCheck != nil {
+		h.stopHealthCheck()
+		h.healthCheckWg.Wait()
+	}
+
 	h.dialer.CloseAll()
 
 	return nil
 }
+
+func (h *Client) startHealthCheck(options ClientOptions) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	h.stopHealthCheck = cancel
+
+	timeout := options.HealthCheckTimeout
+	if timeout <= 0 {
+		timeout = options.HealthCheckInterval
+	}
+
+	h.healthCheckWg.Add(1)
+
+	go func() {
+		defer h.healthCheckWg.Done()
+
+		ticker := time.NewTicker(options.HealthCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			err := h.checkHealth(ctx, timeout)
+			if err == nil || ctx.Err() != nil {
+				continue
+			}
+
+			h.dialer.CloseAll()
+
+			if options.OnStaleConnections != nil {
+				options.OnStaleConnections(err)
+			}
+		}
+	}()
+}
+
+// checkHealth returns an error if the health check request fails without getting a response from kube-apiserver.
+func (h *Client) checkHealth(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := h.Discovery().RESTClient().Get().AbsPath("/livez").Do(ctx).Error()
+	if err == nil {
+		return nil
+	}
+
+	// any HTTP response means the connection is alive
+	var status apierrors.APIStatus
+
+	if errors.As(err, &status) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,61 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubernetes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes"
+)
+
+func TestClientHealthCheck(t *testing.T) {
+	var hang atomic.Bool
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hang.Load() {
+			<-r.Context().Done()
+
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+
+	staleCh := make(chan error, 10)
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: srv.URL},
+		kubernetes.WithHealthCheck(50*time.Millisecond, 100*time.Millisecond),
+		kubernetes.WithStaleConnectionsHandler(func(err error) {
+			staleCh <- err
+		}),
+	)
+	require.NoError(t, err)
+
+	// an error response means the connection is healthy
+	select {
+	case err = <-staleCh:
+		require.Failf(t, "unexpected stale connections", "error: %v", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	hang.Store(true)
+
+	select {
+	case err = <-staleCh:
+		require.Error(t, err)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "stale connections not detected")
+	}
+
+	require.NoError(t, client.Close())
+}
@@ -0,0 +1,80 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility
+
+import "slices"
+
+// admissionPlugin is the history of the kube-apiserver admission plugin.
+type admissionPlugin struct {
+	name string
+	// enabledSince is the Kubernetes 1.x minor version the plugin is enabled by default from, 0 if not enabled by default
+	enabledSince uint64
+	// removed is the Kubernetes 1.x minor version the plugin is removed in, 0 if not removed
+	removed uint64
+}
+
+// admissionPlugins are the kube-apiserver admission plugins which are enabled by default or were removed.
+//
+// See DefaultOffAdmissionPlugins in pkg/kubeapiserver/options/plugins.go of the Kubernetes release branches.
+var admissionPlugins = []admissionPlugin{
+	{name: "CertificateApproval", enabledSince: 18},
+	{name: "CertificateSigning", enabledSince: 18},
+	{name: "CertificateSubjectRestriction", enabledSince: 18},
+	{name: "ClusterTrustBundleAttest", enabledSince: 27},
+	{name: "DefaultIngressClass", enabledSince: 18},
+	{name: "DefaultStorageClass", enabledSince: 1},
+	{name: "DefaultTolerationSeconds", enabledSince: 1},
+	{name: "LimitRanger", enabledSince: 1},
+	{name: "MutatingAdmissionPolicy", enabledSince: 32},
+	{name: "MutatingAdmissionWebhook", enabledSince: 1},
+	{name: "NamespaceLifecycle", enabledSince: 1},
+	{name: "PersistentVolumeClaimResize", enabledSince: 15},
+	{name: "PersistentVolumeLabel", removed: 31},
+	{name: "PodSecurity", enabledSince: 23},
+	{name: "PodSecurityPolicy", removed: 25},
+	{name: "Priority", enabledSince: 1},
+	{name: "ResourceQuota", enabledSince: 1},
+	{name: "RuntimeClass", enabledSince: 20},
+	{name: "SecurityContextDeny", removed: 30},
+	{name: "ServiceAccount", enabledSince: 1},
+	{name: "StorageObjectInUseProtection", enabledSince: 11},
+	{name: "TaintNodesByCondition", enabledSince: 17},
+	{name: "ValidatingAdmissionPolicy", enabledSince: 26},
+	{name: "ValidatingAdmissionWebhook", enabledSince: 1},
+}
+
+// DefaultEnabledAdmissionPlugins returns the kube-apiserver admission plugins enabled by default in the Kubernetes version.
+//
+// The plugins are sorted by name (the order is not relevant for --enable-admission-plugins).
+func (v Version) DefaultEnabledAdmissionPlugins() []string {
+	var plugins []string
+
+	for _, plugin := range admissionPlugins {
+		if plugin.enabledSince == 0 {
+			continue
+		}
+
+		if v.Major > 1 || (v.Major == 1 && v.Minor >= plugin.enabledSince) {
+			plugins = append(plugins, plugin.name)
+		}
+	}
+
+	slices.Sort(plugins)
+
+	return plugins
+}
+
+// AdmissionPluginRemoved returns true if the kube-apiserver admission plugin is removed in the Kubernetes version.
+//
+// kube-apiserver fails to start if a removed plugin is listed in --enable-admission-plugins.
+func (v Version) AdmissionPluginRemoved(name string) bool {
+	for _, plugin := range admissionPlugins {
+		if plugin.name == name && plugin.removed != 0 {
+			return v.Major > 1 || (v.Major == 1 && v.Minor >= plugin.removed)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+)
+
+func TestDefaultEnabledAdmissionPlugins(t *testing.T) {
+	v125 := compatibility.Version{Major: 1, Minor: 25}.DefaultEnabledAdmissionPlugins()
+
+	assert.Contains(t, v125, "PodSecurity")
+	assert.NotContains(t, v125, "ValidatingAdmissionPolicy")
+	assert.NotContains(t, v125, "PodSecurityPolicy")
+	assert.IsIncreasing(t, v125)
+
+	v132 := compatibility.Version{Major: 1, Minor: 32, Patch: 1}.DefaultEnabledAdmissionPlugins()
+
+	assert.Contains(t, v132, "ValidatingAdmissionPolicy")
+	assert.Contains(t, v132, "ClusterTrustBundleAttest")
+	assert.Contains(t, v132, "MutatingAdmissionPolicy")
+	assert.Len(t, v132, len(v125)+3)
+}
+
+func TestAdmissionPluginRemoved(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		version compatibility.Version
+
+		expected bool
+	}{
+		{name: "PodSecurityPolicy", version: compatibility.Version{Major: 1, Minor: 24}, expected: false},
+		{name: "PodSecurityPolicy", version: compatibility.Version{Major: 1, Minor: 25}, expected: true},
+		{name: "SecurityContextDeny", version: compatibility.Version{Major: 1, Minor: 29}, expected: false},
+		{name: "SecurityContextDeny", version: compatibility.Version{Major: 1, Minor: 30}, expected: true},
+		{name: "PersistentVolumeLabel", version: compatibility.Version{Major: 1, Minor: 31}, expected: true},
+		{name: "NodeRestriction", version: compatibility.Version{Major: 1, Minor: 31}, expected: false},
+		{name: "PodSecurity", version: compatibility.Version{Major: 1, Minor: 31}, expected: false},
+	} {
+		t.Run(test.name+"/"+test.version.String(), func(t *testing.T) {
+			assert.Equal(t, test.expected, test.version.AdmissionPluginRemoved(test.name))
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// apiVersion is the range of Kubernetes minor versions the API version is served in (by default).
+type apiVersion struct {
+	version string
+	// since is the Kubernetes 1.x minor version the API version is served from
+	since uint64
+	// removed is the Kubernetes 1.x minor version the API version is not served anymore, 0 if still served
+	removed uint64
+	// deprecated is the Kubernetes 1.x minor version the API version is deprecated in, if it differs from the default
+	deprecated uint64
+	// replacement is the API version to migrate to, if it is not in the same group
+	replacement schema.GroupVersion
+}
+
+// served returns true if the API version is served in the Kubernetes version.
+func (a apiVersion) served(v Version) bool {
+	return v.Minor >= a.since && (a.removed == 0 || v.Minor < a.removed)
+}
+
+// PreferredAPIVersion returns the most stable API version of the kind served by the Kubernetes version.
+//
+// If the kind is not known, or no version of it is served by the Kubernetes version, ok is false.
+func (v Version) PreferredAPIVersion(groupKind schema.GroupKind) (gvk schema.GroupVersionKind, ok bool) {
+	if v.Major != 1 {
+		return schema.GroupVersionKind{}, false
+	}
+
+	versions := apiVersions[groupKind]
+
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].served(v) {
+			return groupKind.WithVersion(versions[i].version), true
+		}
+	}
+
+	return schema.GroupVersionKind{}, false
+}
+
+// SupportsAPIVersion returns true if the API version of the kind is served by default by the Kubernetes version.
+//
+// The kinds which are not in the table are reported as not supported.
+func (v Version) SupportsAPIVersion(gvk schema.GroupVersionKind) bool {
+	if v.Major != 1 {
+		return false
+	}
+
+	for _, version := range apiVersions[gvk.GroupKind()] {
+		if version.version == gvk.Version {
+			return version.served(v)
+		}
+	}
+
+	return false
+}
+
+// Deprecation describes the deprecation timeline of the API version.
+type Deprecation struct {
+	// DeprecatedIn is the Kubernetes version the API version is deprecated in.
+	DeprecatedIn Version
+	// RemovedIn is the Kubernetes version the API version is not served anymore.
+	RemovedIn Version
+	// Replacement is the API version to migrate to, empty if there is no replacement.
+	Replacement schema.GroupVersionKind
+}
+
+// DeprecationInfo returns the deprecation timeline of the API version of the kind.
+//
+// If the API version is not known, or it is not deprecated, ok is false.
+func DeprecationInfo(gvk schema.GroupVersionKind) (deprecation Deprecation, ok bool) {
+	versions := apiVersions[gvk.GroupKind()]
+
+	idx := slices.IndexFunc(versions, func(version apiVersion) bool {
+		return version.version == gvk.Version
+	})
+	if idx == -1 || versions[idx].removed == 0 {
+		return Deprecation{}, false
+	}
+
+	version := versions[idx]
+
+	deprecation.RemovedIn = Version{Major: 1, Minor: version.removed}
+
+	switch {
+	case version.deprecated != 0:
+		deprecation.DeprecatedIn = Version{Major: 1, Minor: version.deprecated}
+	case idx+1 < len(versions):
+		deprecation.DeprecatedIn = Version{Major: 1, Minor: versions[idx+1].since}
+	}
+
+	if !version.replacement.Empty() {
+		deprecation.Replacement = version.replacement.WithKind(gvk.Kind)
+
+		return deprecation, true
+	}
+
+	if replacement, found := deprecation.RemovedIn.PreferredAPIVersion(gvk.GroupKind()); found {
+		deprecation.Replacement = replacement
+	}
+
+	return deprecation, true
+}
@@ -0,0 +1,238 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+// apiVersionHistory is the history of the API versions of the kinds sharing it.
+type apiVersionHistory struct {
+	group string
+	kinds []string
+	// versions are ordered from the least to the most stable
+	versions []apiVersion
+}
+
+// apiVersions are the API versions served by default per kind.
+//
+// The table is based on the deprecation guide (https://kubernetes.io/docs/reference/using-api/deprecation-guide/),
+// alpha and beta versions which are not served by default are not listed.
+//
+// The deprecation version defaults to the version the next API version in the list is introduced in,
+// and the replacement defaults to the most stable API version served in the removal version.
+var apiVersions = buildAPIVersions([]apiVersionHistory{
+	{
+		group: "admissionregistration.k8s.io",
+		kinds: []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 9, removed: 22},
+			{version: "v1", since: 16},
+		},
+	},
+	{
+		group: "admissionregistration.k8s.io",
+		kinds: []string{"ValidatingAdmissionPolicy", "ValidatingAdmissionPolicyBinding"},
+		versions: []apiVersion{
+			{version: "v1", since: 30},
+		},
+	},
+	{
+		group: "apiextensions.k8s.io",
+		kinds: []string{"CustomResourceDefinition"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 7, removed: 22},
+			{version: "v1", since: 16},
+		},
+	},
+	{
+		group: "apiregistration.k8s.io",
+		kinds: []string{"APIService"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 7, removed: 22},
+			{version: "v1", since: 10},
+		},
+	},
+	{
+		group: "apps",
+		kinds: []string{"DaemonSet", "Deployment", "ReplicaSet", "StatefulSet"},
+		versions: []apiVersion{
+			{version: "v1beta2", since: 8, removed: 16},
+			{version: "v1", since: 9},
+		},
+	},
+	{
+		group: "autoscaling",
+		kinds: []string{"HorizontalPodAutoscaler"},
+		versions: []apiVersion{
+			{version: "v2beta1", since: 8, deprecated: 22, removed: 25},
+			{version: "v2beta2", since: 12, deprecated: 23, removed: 26},
+			{version: "v1", since: 2},
+			{version: "v2", since: 23},
+		},
+	},
+	{
+		group: "batch",
+		kinds: []string{"CronJob"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 8, removed: 25},
+			{version: "v1", since: 21},
+		},
+	},
+	{
+		group: "certificates.k8s.io",
+		kinds: []string{"CertificateSigningRequest"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 6, removed: 22},
+			{version: "v1", since: 19},
+		},
+	},
+	{
+		group: "coordination.k8s.io",
+		kinds: []string{"Lease"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 12, removed: 22},
+			{version: "v1", since: 14},
+		},
+	},
+	{
+		group: "discovery.k8s.io",
+		kinds: []string{"EndpointSlice"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 17, removed: 25},
+			{version: "v1", since: 21},
+		},
+	},
+	{
+		group: "events.k8s.io",
+		kinds: []string{"Event"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 8, removed: 25},
+			{version: "v1", since: 19},
+		},
+	},
+	{
+		group: "extensions",
+		kinds: []string{"Ingress"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 1, deprecated: 14, removed: 22, replacement: schema.GroupVersion{Group: "networking.k8s.io", Version: "v1"}},
+		},
+	},
+	{
+		group: "flowcontrol.apiserver.k8s.io",
+		kinds: []string{"FlowSchema", "PriorityLevelConfiguration"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 20, removed: 26},
+			{version: "v1beta2", since: 23, removed: 29},
+			{version: "v1beta3", since: 26, removed: 32},
+			{version: "v1", since: 29},
+		},
+	},
+	{
+		group: "networking.k8s.io",
+		kinds: []string{"Ingress"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 14, removed: 22},
+			{version: "v1", since: 19},
+		},
+	},
+	{
+		group: "networking.k8s.io",
+		kinds: []string{"IngressClass"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 18, removed: 22},
+			{version: "v1", since: 19},
+		},
+	},
+	{
+		group: "node.k8s.io",
+		kinds: []string{"RuntimeClass"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 14, removed: 25},
+			{version: "v1", since: 20},
+		},
+	},
+	{
+		group: "policy",
+		kinds: []string{"PodDisruptionBudget"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 5, removed: 25},
+			{version: "v1", since: 21},
+		},
+	},
+	{
+		group: "policy",
+		kinds: []string{"PodSecurityPolicy"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 10, deprecated: 21, removed: 25},
+		},
+	},
+	{
+		group: "rbac.authorization.k8s.io",
+		kinds: []string{"ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 6, deprecated: 17, removed: 22},
+			{version: "v1", since: 8},
+		},
+	},
+	{
+		group: "resource.k8s.io",
+		kinds: []string{"DeviceClass", "ResourceClaim", "ResourceClaimTemplate", "ResourceSlice"},
+		versions: []apiVersion{
+			{version: "v1", since: 34},
+		},
+	},
+	{
+		group: "scheduling.k8s.io",
+		kinds: []string{"PriorityClass"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 11, removed: 22},
+			{version: "v1", since: 14},
+		},
+	},
+	{
+		group: "storage.k8s.io",
+		kinds: []string{"CSIDriver"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 14, removed: 22},
+			{version: "v1", since: 18},
+		},
+	},
+	{
+		group: "storage.k8s.io",
+		kinds: []string{"CSINode"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 14, removed: 22},
+			{version: "v1", since: 17},
+		},
+	},
+	{
+		group: "storage.k8s.io",
+		kinds: []string{"CSIStorageCapacity"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 21, removed: 27},
+			{version: "v1", since: 24},
+		},
+	},
+	{
+		group: "storage.k8s.io",
+		kinds: []string{"VolumeAttachment"},
+		versions: []apiVersion{
+			{version: "v1beta1", since: 10, removed: 22},
+			{version: "v1", since: 13},
+		},
+	},
+})
+
+// buildAPIVersions indexes the API version histories by kind.
+func buildAPIVersions(histories []apiVersionHistory) map[schema.GroupKind][]apiVersion {
+	versions := map[schema.GroupKind][]apiVersion{}
+
+	for _, history := range histories {
+		for _, kind := range history.kinds {
+			versions[schema.GroupKind{Group: history.group, Kind: kind}] = history.versions
+		}
+	}
+
+	return versions
+}
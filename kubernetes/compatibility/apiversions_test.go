@@ -0,0 +1,118 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+)
+
+func TestPreferredAPIVersion(t *testing.T) {
+	flowSchema := schema.GroupKind{Group: "flowcontrol.apiserver.k8s.io", Kind: "FlowSchema"}
+	hpa := schema.GroupKind{Group: "autoscaling", Kind: "HorizontalPodAutoscaler"}
+
+	for _, test := range []struct {
+		version   compatibility.Version
+		groupKind schema.GroupKind
+
+		expectedVersion string
+	}{
+		{compatibility.Version{Major: 1, Minor: 19}, flowSchema, ""},
+		{compatibility.Version{Major: 1, Minor: 22}, flowSchema, "v1beta1"},
+		{compatibility.Version{Major: 1, Minor: 25}, flowSchema, "v1beta2"},
+		{compatibility.Version{Major: 1, Minor: 28}, flowSchema, "v1beta3"},
+		{compatibility.Version{Major: 1, Minor: 29}, flowSchema, "v1"},
+		{compatibility.Version{Major: 1, Minor: 22}, hpa, "v1"},
+		{compatibility.Version{Major: 1, Minor: 23}, hpa, "v2"},
+		{compatibility.Version{Major: 1, Minor: 30}, schema.GroupKind{Group: "example.com", Kind: "Unknown"}, ""},
+	} {
+		t.Run(test.version.String()+"/"+test.groupKind.String(), func(t *testing.T) {
+			gvk, ok := test.version.PreferredAPIVersion(test.groupKind)
+
+			assert.Equal(t, test.expectedVersion != "", ok)
+
+			if ok {
+				assert.Equal(t, test.groupKind.WithVersion(test.expectedVersion), gvk)
+			}
+		})
+	}
+}
+
+func TestSupportsAPIVersion(t *testing.T) {
+	cronJobV1beta1 := schema.GroupVersionKind{Group: "batch", Version: "v1beta1", Kind: "CronJob"}
+	cronJobV1 := schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"}
+
+	assert.True(t, compatibility.Version{Major: 1, Minor: 24}.SupportsAPIVersion(cronJobV1beta1))
+	assert.False(t, compatibility.Version{Major: 1, Minor: 25}.SupportsAPIVersion(cronJobV1beta1))
+	assert.False(t, compatibility.Version{Major: 1, Minor: 20}.SupportsAPIVersion(cronJobV1))
+	assert.True(t, compatibility.Version{Major: 1, Minor: 21, Patch: 3}.SupportsAPIVersion(cronJobV1))
+	assert.False(t, compatibility.Version{Major: 1, Minor: 30}.SupportsAPIVersion(schema.GroupVersionKind{Group: "batch", Version: "v2", Kind: "CronJob"}))
+}
+
+func TestDeprecationInfo(t *testing.T) {
+	for _, test := range []struct {
+		gvk schema.GroupVersionKind
+
+		expectedOk          bool
+		expectedDeprecation compatibility.Deprecation
+	}{
+		{
+			gvk: schema.GroupVersionKind{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "FlowSchema"},
+
+			expectedOk: true,
+			expectedDeprecation: compatibility.Deprecation{
+				DeprecatedIn: compatibility.Version{Major: 1, Minor: 23},
+				RemovedIn:    compatibility.Version{Major: 1, Minor: 26},
+				Replacement:  schema.GroupVersionKind{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "FlowSchema"},
+			},
+		},
+		{
+			gvk: schema.GroupVersionKind{Group: "autoscaling", Version: "v2beta1", Kind: "HorizontalPodAutoscaler"},
+
+			expectedOk: true,
+			expectedDeprecation: compatibility.Deprecation{
+				DeprecatedIn: compatibility.Version{Major: 1, Minor: 22},
+				RemovedIn:    compatibility.Version{Major: 1, Minor: 25},
+				Replacement:  schema.GroupVersionKind{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"},
+			},
+		},
+		{
+			gvk: schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Ingress"},
+
+			expectedOk: true,
+			expectedDeprecation: compatibility.Deprecation{
+				DeprecatedIn: compatibility.Version{Major: 1, Minor: 14},
+				RemovedIn:    compatibility.Version{Major: 1, Minor: 22},
+				Replacement:  schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+			},
+		},
+		{
+			gvk: schema.GroupVersionKind{Group: "policy", Version: "v1beta1", Kind: "PodSecurityPolicy"},
+
+			expectedOk: true,
+			expectedDeprecation: compatibility.Deprecation{
+				DeprecatedIn: compatibility.Version{Major: 1, Minor: 21},
+				RemovedIn:    compatibility.Version{Major: 1, Minor: 25},
+			},
+		},
+		{
+			gvk: schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"},
+		},
+		{
+			gvk: schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Unknown"},
+		},
+	} {
+		t.Run(test.gvk.String(), func(t *testing.T) {
+			deprecation, ok := compatibility.DeprecationInfo(test.gvk)
+
+			assert.Equal(t, test.expectedOk, ok)
+			assert.Equal(t, test.expectedDeprecation, deprecation)
+		})
+	}
+}
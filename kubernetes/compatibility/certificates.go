@@ -0,0 +1,69 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+// Well-known CertificateSigningRequest signer names.
+//
+// See https://kubernetes.io/docs/reference/access-authn-authz/certificate-signing-requests/#kubernetes-signers.
+const (
+	SignerKubeAPIServerClient        = "kubernetes.io/kube-apiserver-client"
+	SignerKubeAPIServerClientKubelet = "kubernetes.io/kube-apiserver-client-kubelet"
+	SignerKubeletServing             = "kubernetes.io/kubelet-serving"
+	SignerLegacyUnknown              = "kubernetes.io/legacy-unknown"
+)
+
+// csrSigners are the well-known signers, with the Kubernetes 1.x minor version ranges they can be requested in.
+var csrSigners = []struct {
+	name    string
+	since   uint64
+	removed uint64
+}{
+	{name: SignerKubeAPIServerClient, since: 18},
+	{name: SignerKubeAPIServerClientKubelet, since: 18},
+	{name: SignerKubeletServing, since: 18},
+	// legacy-unknown can't be requested with certificates.k8s.io/v1, so it goes away with v1beta1
+	{name: SignerLegacyUnknown, since: 18, removed: 22},
+}
+
+var certificateSigningRequestKind = schema.GroupKind{Group: "certificates.k8s.io", Kind: "CertificateSigningRequest"}
+
+// CertificateSigningRequestAPIVersion returns the most stable certificates.k8s.io API version served for CertificateSigningRequests.
+//
+// If the version doesn't serve the CertificateSigningRequests, ok is false.
+func (v Version) CertificateSigningRequestAPIVersion() (gvk schema.GroupVersionKind, ok bool) {
+	return v.PreferredAPIVersion(certificateSigningRequestKind)
+}
+
+// CSRSignerNames returns the well-known CertificateSigningRequest signer names which can be requested in the Kubernetes version.
+func (v Version) CSRSignerNames() []string {
+	var signers []string
+
+	for _, signer := range csrSigners {
+		if v.SupportsCSRSigner(signer.name) {
+			signers = append(signers, signer.name)
+		}
+	}
+
+	return signers
+}
+
+// SupportsCSRSigner returns true if the well-known signer can be requested in the Kubernetes version.
+//
+// Custom (not kubernetes.io) signers are always supported with certificates.k8s.io/v1.
+func (v Version) SupportsCSRSigner(name string) bool {
+	if v.Major != 1 {
+		return v.Major > 1 && name != SignerLegacyUnknown
+	}
+
+	for _, signer := range csrSigners {
+		if signer.name == name {
+			return v.Minor >= signer.since && (signer.removed == 0 || v.Minor < signer.removed)
+		}
+	}
+
+	return v.SupportsAPIVersion(certificateSigningRequestKind.WithVersion("v1"))
+}
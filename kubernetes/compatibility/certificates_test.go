@@ -0,0 +1,44 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+)
+
+func TestCertificates(t *testing.T) {
+	v121 := compatibility.Version{Major: 1, Minor: 21}
+	v130 := compatibility.Version{Major: 1, Minor: 30, Patch: 2}
+
+	gvk, ok := v121.CertificateSigningRequestAPIVersion()
+	assert.True(t, ok)
+	assert.Equal(t, schema.GroupVersionKind{Group: "certificates.k8s.io", Version: "v1", Kind: "CertificateSigningRequest"}, gvk)
+
+	gvk, ok = compatibility.Version{Major: 1, Minor: 18}.CertificateSigningRequestAPIVersion()
+	assert.True(t, ok)
+	assert.Equal(t, "v1beta1", gvk.Version)
+
+	assert.Equal(t, []string{
+		compatibility.SignerKubeAPIServerClient,
+		compatibility.SignerKubeAPIServerClientKubelet,
+		compatibility.SignerKubeletServing,
+		compatibility.SignerLegacyUnknown,
+	}, v121.CSRSignerNames())
+	assert.Equal(t, []string{
+		compatibility.SignerKubeAPIServerClient,
+		compatibility.SignerKubeAPIServerClientKubelet,
+		compatibility.SignerKubeletServing,
+	}, v130.CSRSignerNames())
+
+	assert.True(t, v130.SupportsCSRSigner(compatibility.SignerKubeletServing))
+	assert.False(t, v130.SupportsCSRSigner(compatibility.SignerLegacyUnknown))
+	assert.True(t, v130.SupportsCSRSigner("example.com/custom"))
+	assert.False(t, compatibility.Version{Major: 1, Minor: 17}.SupportsCSRSigner("example.com/custom"))
+}
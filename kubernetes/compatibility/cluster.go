@@ -0,0 +1,62 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+// VersionFromCluster queries the kube-apiserver /version endpoint to return the Kubernetes version.
+//
+// See VersionFromDiscovery for the version parsing rules.
+func VersionFromCluster(ctx context.Context, config *rest.Config) (Version, error) {
+	client, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return Version{}, fmt.Errorf("error building discovery client: %w", err)
+	}
+
+	body, err := client.RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		return Version{}, fmt.Errorf("error fetching server version: %w", err)
+	}
+
+	var info version.Info
+
+	if err = json.Unmarshal(body, &info); err != nil {
+		return Version{}, fmt.Errorf("error unmarshaling server version: %w", err)
+	}
+
+	return versionFromInfo(&info)
+}
+
+// VersionFromDiscovery returns the Kubernetes version reported by the server.
+//
+// The gitVersion is parsed in a tolerant way, the pre-release and build metadata
+// (e.g. "-gke.1014001" or "+k3s1" vendor suffixes) are dropped, so that the version
+// can be compared against the upstream releases.
+func VersionFromDiscovery(client discovery.ServerVersionInterface) (Version, error) {
+	info, err := client.ServerVersion()
+	if err != nil {
+		return Version{}, fmt.Errorf("error fetching server version: %w", err)
+	}
+
+	return versionFromInfo(info)
+}
+
+func versionFromInfo(info *version.Info) (Version, error) {
+	v, err := semver.ParseTolerant(info.GitVersion)
+	if err != nil {
+		return Version{}, fmt.Errorf("error parsing server version %q: %w", info.GitVersion, err)
+	}
+
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}, nil
+}
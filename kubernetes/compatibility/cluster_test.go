@@ -0,0 +1,93 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+)
+
+func TestVersionFromCluster(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		gitVersion string
+
+		expectedVersion compatibility.Version
+		expectedError   string
+	}{
+		{
+			name:       "upstream",
+			gitVersion: "v1.31.2",
+
+			expectedVersion: compatibility.Version{Major: 1, Minor: 31, Patch: 2},
+		},
+		{
+			name:       "vendor suffix",
+			gitVersion: "v1.30.5-gke.1014001",
+
+			expectedVersion: compatibility.Version{Major: 1, Minor: 30, Patch: 5},
+		},
+		{
+			name:       "build metadata",
+			gitVersion: "v1.29.3+k3s1",
+
+			expectedVersion: compatibility.Version{Major: 1, Minor: 29, Patch: 3},
+		},
+		{
+			name:       "invalid",
+			gitVersion: "unknown",
+
+			expectedError: `error parsing server version "unknown"`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/version" {
+					w.WriteHeader(http.StatusNotFound)
+
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"major":"1","gitVersion":"` + test.gitVersion + `"}`)) //nolint:errcheck
+			}))
+			t.Cleanup(srv.Close)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			t.Cleanup(cancel)
+
+			config := &rest.Config{Host: srv.URL}
+
+			version, err := compatibility.VersionFromCluster(ctx, config)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedVersion, version)
+			}
+
+			client, err := discovery.NewDiscoveryClientForConfig(config)
+			require.NoError(t, err)
+
+			version, err = compatibility.VersionFromDiscovery(client)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedVersion, version)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@
 package compatibility
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/blang/semver/v4"
@@ -20,6 +21,45 @@ func (v Version) String() string {
 	return semver.Version(v).String()
 }
 
+// Compare returns 0 if the versions are equal, -1 if v is less than other, and 1 if v is greater than other.
+func (v Version) Compare(other Version) int {
+	return semver.Version(v).Compare(semver.Version(other))
+}
+
+// LessThan returns true if v is less than other.
+func (v Version) LessThan(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+// AtLeast returns true if v is at least major.minor (patch and pre-release versions are ignored).
+func (v Version) AtLeast(major, minor uint64) bool {
+	return v.Major > major || (v.Major == major && v.Minor >= minor)
+}
+
+// InRange returns true if v is within [minVersion, maxVersion] range (inclusive).
+func (v Version) InRange(minVersion, maxVersion Version) bool {
+	return v.Compare(minVersion) >= 0 && v.Compare(maxVersion) <= 0
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+//
+// The version is parsed in a tolerant way, so that both "v1.30.0" and "1.30" are accepted.
+func (v *Version) UnmarshalText(text []byte) error {
+	parsed, err := semver.ParseTolerant(string(text))
+	if err != nil {
+		return fmt.Errorf("error parsing Kubernetes version %q: %w", string(text), err)
+	}
+
+	*v = Version(parsed)
+
+	return nil
+}
+
 // latest is used if the version can't be parsed.
 var latest = Version{
 	Major: 1,
@@ -30,27 +70,75 @@ var latest = Version{
 //
 // If the version can't be parsed, assume latest version.
 func VersionFromImageRef(imageRef string) Version {
-	// try to parse as tagged
-	ref, err := name.NewTag(imageRef)
+	return VersionFromImageRefWithFallback(imageRef, latest)
+}
+
+// VersionFromImageRefWithFallback parses container image ref to return just Kubernetes version.
+//
+// If the version can't be parsed, fallback is returned.
+func VersionFromImageRefWithFallback(imageRef string, fallback Version, opts ...ImageRefOption) Version {
+	v, err := VersionFromImageRefStrict(imageRef, opts...)
 	if err != nil {
-		// try to cut digest part
-		var ok bool
+		return fallback
+	}
+
+	return v
+}
+
+// DigestResolver resolves the image reference pinned by a digest to the image tag, e.g. by looking up the registry.
+type DigestResolver func(imageRef string) (tag string, err error)
+
+// ImageRefOption configures VersionFromImageRefStrict.
+type ImageRefOption func(*imageRefOptions)
+
+type imageRefOptions struct {
+	digestResolver DigestResolver
+}
+
+// WithDigestResolver sets the resolver used for the image references which have a digest, but no tag.
+func WithDigestResolver(resolver DigestResolver) ImageRefOption {
+	return func(o *imageRefOptions) {
+		o.digestResolver = resolver
+	}
+}
+
+// VersionFromImageRefStrict parses container image ref to return just Kubernetes version.
+//
+// If the image ref has no tag, or the tag is not a version, an error is returned.
+// The image refs with a digest only are resolved with the digest resolver (if set).
+func VersionFromImageRefStrict(imageRef string, opts ...ImageRefOption) (Version, error) {
+	var options imageRefOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// cut the digest part
+	repository, _, hasDigest := strings.Cut(imageRef, "@")
+
+	ref, err := name.NewTag(repository)
+	if err != nil {
+		return Version{}, fmt.Errorf("error parsing image reference %q: %w", imageRef, err)
+	}
 
-		imageRef, _, ok = strings.Cut(imageRef, "@")
+	tag := ref.TagStr()
 
-		if ok {
-			ref, err = name.NewTag(imageRef)
+	// name.NewTag defaults to "latest" if there is no tag
+	if !strings.Contains(repository[strings.LastIndex(repository, "/")+1:], ":") {
+		if !hasDigest || options.digestResolver == nil {
+			return Version{}, fmt.Errorf("image reference %q has no tag", imageRef)
 		}
 
+		tag, err = options.digestResolver(imageRef)
 		if err != nil {
-			return latest
+			return Version{}, fmt.Errorf("error resolving image reference %q: %w", imageRef, err)
 		}
 	}
 
-	v, err := semver.ParseTolerant(ref.TagStr())
+	v, err := semver.ParseTolerant(tag)
 	if err != nil {
-		return latest
+		return Version{}, fmt.Errorf("error parsing version from image reference %q: %w", imageRef, err)
 	}
 
-	return Version(v)
+	return Version(v), nil
 }
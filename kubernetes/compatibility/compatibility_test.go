@@ -5,9 +5,12 @@
 package compatibility_test
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
 )
@@ -62,3 +65,84 @@ func TestVersionFromImageRef(t *testing.T) {
 		})
 	}
 }
+
+func TestVersionCompare(t *testing.T) {
+	v130 := compatibility.Version{Major: 1, Minor: 30}
+	v1305 := compatibility.Version{Major: 1, Minor: 30, Patch: 5}
+	v131 := compatibility.Version{Major: 1, Minor: 31}
+
+	assert.Equal(t, 0, v130.Compare(v130))
+	assert.Equal(t, -1, v130.Compare(v1305))
+	assert.Equal(t, 1, v131.Compare(v1305))
+
+	assert.True(t, v130.LessThan(v131))
+	assert.False(t, v131.LessThan(v130))
+	assert.False(t, v130.LessThan(v130))
+
+	assert.True(t, v1305.AtLeast(1, 30))
+	assert.True(t, v131.AtLeast(1, 30))
+	assert.False(t, v1305.AtLeast(1, 31))
+	assert.True(t, compatibility.Version{Major: 2}.AtLeast(1, 99))
+
+	assert.True(t, v1305.InRange(v130, v131))
+	assert.True(t, v130.InRange(v130, v131))
+	assert.True(t, v131.InRange(v130, v131))
+	assert.False(t, v131.InRange(v130, v1305))
+}
+
+func TestVersionText(t *testing.T) {
+	type config struct {
+		Version compatibility.Version `json:"version"`
+	}
+
+	out, err := json.Marshal(config{Version: compatibility.Version{Major: 1, Minor: 31, Patch: 2}})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"version":"1.31.2"}`, string(out))
+
+	var cfg config
+
+	require.NoError(t, json.Unmarshal([]byte(`{"version":"v1.30"}`), &cfg))
+	assert.Equal(t, compatibility.Version{Major: 1, Minor: 30}, cfg.Version)
+
+	require.Error(t, json.Unmarshal([]byte(`{"version":"latest"}`), &cfg))
+}
+
+func TestVersionFromImageRefStrict(t *testing.T) {
+	const digestRef = "ghcr.io/siderolabs/kubelet@sha256:3f226f5b385960e311f19d6c9c3ea1778e86e6ad2e98a7bbbf1b1a63fe963916"
+
+	version, err := compatibility.VersionFromImageRefStrict("registry.k8s.io/kube-apiserver:v1.31.2")
+	require.NoError(t, err)
+	assert.Equal(t, compatibility.Version{Major: 1, Minor: 31, Patch: 2}, version)
+
+	version, err = compatibility.VersionFromImageRefStrict("localhost:5000/kube-apiserver:v1.30.1@sha256:3f226f5b385960e311f19d6c9c3ea1778e86e6ad2e98a7bbbf1b1a63fe963916")
+	require.NoError(t, err)
+	assert.Equal(t, compatibility.Version{Major: 1, Minor: 30, Patch: 1}, version)
+
+	_, err = compatibility.VersionFromImageRefStrict("ghcr.io/siderolabs/kubelet:alpha")
+	require.ErrorContains(t, err, `error parsing version from image reference "ghcr.io/siderolabs/kubelet:alpha"`)
+
+	_, err = compatibility.VersionFromImageRefStrict("localhost:5000/kubelet")
+	require.EqualError(t, err, `image reference "localhost:5000/kubelet" has no tag`)
+
+	_, err = compatibility.VersionFromImageRefStrict(digestRef)
+	require.EqualError(t, err, `image reference "`+digestRef+`" has no tag`)
+
+	version, err = compatibility.VersionFromImageRefStrict(digestRef, compatibility.WithDigestResolver(func(imageRef string) (string, error) {
+		assert.Equal(t, digestRef, imageRef)
+
+		return "v1.29.4", nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, compatibility.Version{Major: 1, Minor: 29, Patch: 4}, version)
+
+	_, err = compatibility.VersionFromImageRefStrict(digestRef, compatibility.WithDigestResolver(func(string) (string, error) {
+		return "", errors.New("not found")
+	}))
+	require.EqualError(t, err, `error resolving image reference "`+digestRef+`": not found`)
+
+	fallback := compatibility.Version{Major: 1, Minor: 28}
+
+	assert.Equal(t, fallback, compatibility.VersionFromImageRefWithFallback("ghcr.io/siderolabs/kubelet:alpha", fallback))
+	assert.Equal(t, compatibility.Version{Major: 1, Minor: 31}, compatibility.VersionFromImageRefWithFallback("ghcr.io/siderolabs/kubelet:v1.31.0", fallback))
+}
@@ -0,0 +1,74 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility
+
+import "github.com/blang/semver/v4"
+
+// coreDNSVersions are the CoreDNS versions shipped by kubeadm per Kubernetes 1.x minor version.
+//
+// See CoreDNSVersion in cmd/kubeadm/app/constants/constants.go of the Kubernetes release branches.
+var coreDNSVersions = []struct {
+	minor   uint64
+	version semver.Version
+}{
+	{minor: 20, version: semver.Version{Major: 1, Minor: 7, Patch: 0}},
+	{minor: 21, version: semver.Version{Major: 1, Minor: 8, Patch: 0}},
+	{minor: 22, version: semver.Version{Major: 1, Minor: 8, Patch: 4}},
+	{minor: 23, version: semver.Version{Major: 1, Minor: 8, Patch: 6}},
+	{minor: 24, version: semver.Version{Major: 1, Minor: 8, Patch: 6}},
+	{minor: 25, version: semver.Version{Major: 1, Minor: 9, Patch: 3}},
+	{minor: 26, version: semver.Version{Major: 1, Minor: 9, Patch: 3}},
+	{minor: 27, version: semver.Version{Major: 1, Minor: 10, Patch: 1}},
+	{minor: 28, version: semver.Version{Major: 1, Minor: 10, Patch: 1}},
+	{minor: 29, version: semver.Version{Major: 1, Minor: 11, Patch: 1}},
+	{minor: 30, version: semver.Version{Major: 1, Minor: 11, Patch: 1}},
+	{minor: 31, version: semver.Version{Major: 1, Minor: 11, Patch: 3}},
+	{minor: 32, version: semver.Version{Major: 1, Minor: 11, Patch: 3}},
+	{minor: 33, version: semver.Version{Major: 1, Minor: 12, Patch: 0}},
+	{minor: 34, version: semver.Version{Major: 1, Minor: 12, Patch: 1}},
+}
+
+// RecommendedCoreDNSVersion returns the CoreDNS version kubeadm ships with the Kubernetes version.
+//
+// Versions older than the oldest known version get the oldest known CoreDNS version,
+// versions newer than the newest known version get the newest known CoreDNS version.
+func (v Version) RecommendedCoreDNSVersion() semver.Version {
+	return coreDNSVersionFor(v.Major, v.Minor)
+}
+
+// CoreDNSVersionRange returns the range of CoreDNS versions compatible with the Kubernetes version.
+//
+// The range starts with the CoreDNS version shipped with the previous Kubernetes minor version
+// (which is kept running while the cluster is being upgraded), and ends with the recommended CoreDNS version.
+func (v Version) CoreDNSVersionRange() (minVersion, maxVersion semver.Version) {
+	minor := v.Minor
+	if v.Major == 1 && minor > 0 {
+		minor--
+	}
+
+	return coreDNSVersionFor(v.Major, minor), v.RecommendedCoreDNSVersion()
+}
+
+// coreDNSVersionFor returns the CoreDNS version for the Kubernetes version.
+func coreDNSVersionFor(major, minor uint64) semver.Version {
+	switch {
+	case major < 1:
+		return coreDNSVersions[0].version
+	case major > 1:
+		return coreDNSVersions[len(coreDNSVersions)-1].version
+	}
+
+	version := coreDNSVersions[0].version
+
+	for _, entry := range coreDNSVersions {
+		if entry.minor > minor {
+			break
+		}
+
+		version = entry.version
+	}
+
+	return version
+}
@@ -0,0 +1,66 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+)
+
+func TestCoreDNSVersion(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		version compatibility.Version
+
+		expectedRecommended string
+		expectedMin         string
+	}{
+		{
+			version: compatibility.Version{Major: 1, Minor: 18},
+
+			expectedRecommended: "1.7.0",
+			expectedMin:         "1.7.0",
+		},
+		{
+			version: compatibility.Version{Major: 1, Minor: 25, Patch: 3},
+
+			expectedRecommended: "1.9.3",
+			expectedMin:         "1.8.6",
+		},
+		{
+			version: compatibility.Version{Major: 1, Minor: 30},
+
+			expectedRecommended: "1.11.1",
+			expectedMin:         "1.11.1",
+		},
+		{
+			version: compatibility.Version{Major: 1, Minor: 33},
+
+			expectedRecommended: "1.12.0",
+			expectedMin:         "1.11.3",
+		},
+		{
+			version: compatibility.Version{Major: 1, Minor: 99},
+
+			expectedRecommended: "1.12.1",
+			expectedMin:         "1.12.1",
+		},
+	} {
+		t.Run(test.version.String(), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expectedRecommended, test.version.RecommendedCoreDNSVersion().String())
+
+			minVersion, maxVersion := test.version.CoreDNSVersionRange()
+
+			assert.Equal(t, test.expectedMin, minVersion.String())
+			assert.Equal(t, test.expectedRecommended, maxVersion.String())
+		})
+	}
+}
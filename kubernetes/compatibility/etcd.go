@@ -0,0 +1,71 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility
+
+import "github.com/blang/semver/v4"
+
+var (
+	// etcdMinProduction is the minimum etcd version recommended to run in production.
+	//
+	// See https://kubernetes.io/docs/tasks/administer-cluster/configure-upgrade-etcd/#prerequisites.
+	etcdMinProduction = semver.Version{Major: 3, Minor: 4, Patch: 22}
+
+	// etcdMinUpgradeTo36 is the minimum etcd version which can be upgraded to etcd 3.6.
+	//
+	// See https://etcd.io/docs/v3.6/upgrades/upgrade_3_6/.
+	etcdMinUpgradeTo36 = semver.Version{Major: 3, Minor: 5, Patch: 20}
+)
+
+// etcdVersions are the etcd versions shipped by kubeadm per Kubernetes 1.x minor version.
+//
+// See SupportedEtcdVersion in cmd/kubeadm/app/constants/constants.go of the Kubernetes release branches.
+var etcdVersions = []struct {
+	minor   uint64
+	version semver.Version
+}{
+	{minor: 24, version: semver.Version{Major: 3, Minor: 5, Patch: 6}},
+	{minor: 25, version: semver.Version{Major: 3, Minor: 5, Patch: 6}},
+	{minor: 26, version: semver.Version{Major: 3, Minor: 5, Patch: 6}},
+	{minor: 27, version: semver.Version{Major: 3, Minor: 5, Patch: 7}},
+	{minor: 28, version: semver.Version{Major: 3, Minor: 5, Patch: 9}},
+	{minor: 29, version: semver.Version{Major: 3, Minor: 5, Patch: 10}},
+	{minor: 30, version: semver.Version{Major: 3, Minor: 5, Patch: 12}},
+	{minor: 31, version: semver.Version{Major: 3, Minor: 5, Patch: 15}},
+	{minor: 32, version: semver.Version{Major: 3, Minor: 5, Patch: 16}},
+	{minor: 33, version: semver.Version{Major: 3, Minor: 5, Patch: 21}},
+	{minor: 34, version: semver.Version{Major: 3, Minor: 6, Patch: 4}},
+}
+
+// SupportedEtcdVersions returns the range of etcd versions supported with the Kubernetes version.
+//
+// The maximum is the etcd version kubeadm ships with the Kubernetes version.
+// The minimum is the oldest etcd version recommended for production, or,
+// if the Kubernetes version ships etcd 3.6, the oldest etcd version which can be upgraded to 3.6.
+//
+// Versions outside of the known range get the closest known range.
+func (v Version) SupportedEtcdVersions() (minVersion, maxVersion semver.Version) {
+	maxVersion = etcdVersions[0].version
+
+	switch {
+	case v.Major > 1:
+		maxVersion = etcdVersions[len(etcdVersions)-1].version
+	case v.Major == 1:
+		for _, entry := range etcdVersions {
+			if entry.minor > v.Minor {
+				break
+			}
+
+			maxVersion = entry.version
+		}
+	}
+
+	minVersion = etcdMinProduction
+
+	if maxVersion.Major == 3 && maxVersion.Minor >= 6 {
+		minVersion = etcdMinUpgradeTo36
+	}
+
+	return minVersion, maxVersion
+}
@@ -0,0 +1,58 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+)
+
+func TestSupportedEtcdVersions(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		version compatibility.Version
+
+		expectedMin string
+		expectedMax string
+	}{
+		{
+			version: compatibility.Version{Major: 1, Minor: 20},
+
+			expectedMin: "3.4.22",
+			expectedMax: "3.5.6",
+		},
+		{
+			version: compatibility.Version{Major: 1, Minor: 31, Patch: 2},
+
+			expectedMin: "3.4.22",
+			expectedMax: "3.5.15",
+		},
+		{
+			version: compatibility.Version{Major: 1, Minor: 34},
+
+			expectedMin: "3.5.20",
+			expectedMax: "3.6.4",
+		},
+		{
+			version: compatibility.Version{Major: 1, Minor: 99},
+
+			expectedMin: "3.5.20",
+			expectedMax: "3.6.4",
+		},
+	} {
+		t.Run(test.version.String(), func(t *testing.T) {
+			t.Parallel()
+
+			minVersion, maxVersion := test.version.SupportedEtcdVersions()
+
+			assert.Equal(t, test.expectedMin, minVersion.String())
+			assert.Equal(t, test.expectedMax, maxVersion.String())
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility
+
+//go:generate go run ../internal/gendata -format compatibility -from 1.20 -to 1.34 -out featuregates_data.go
+
+// featureGateState is the state of the feature gate default starting with the Kubernetes minor version.
+type featureGateState struct {
+	// since is the Kubernetes 1.x minor version the state applies from
+	since uint64
+	// enabled is the default value of the gate
+	enabled bool
+	// locked is true if the gate can't be set to the other value
+	locked bool
+	// removed is true if the gate is not recognized anymore
+	removed bool
+}
+
+// FeatureGateDefault returns the default state of the feature gate in the Kubernetes version.
+//
+// If the feature gate is not known to the version (not introduced yet, or removed), known is false.
+// For the removed gates enabled reports the behavior the gate used to control,
+// e.g. the features which went GA before the removal are always enabled.
+//
+// The feature gate states are generated from the Kubernetes 1.20+ sources into featuregates_data.go,
+// the gates of the older versions are not known.
+// The versions after 1.x are assumed to have the newest known gate states.
+func (v Version) FeatureGateDefault(name string) (enabled, locked, known bool) {
+	if v.Major < 1 {
+		return false, false, false
+	}
+
+	var (
+		state featureGateState
+		found bool
+	)
+
+	for _, s := range featureGates[name] {
+		if v.Major == 1 && s.since > v.Minor {
+			break
+		}
+
+		state, found = s, true
+	}
+
+	if !found {
+		return false, false, false
+	}
+
+	return state.enabled, state.locked, !state.removed
+}
@@ -0,0 +1,1742 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Code generated by gendata. DO NOT EDIT.
+
+package compatibility
+
+// featureGates is the history of the feature gate defaults per Kubernetes minor version.
+//
+// The table can be regenerated from the Kubernetes repository with `go generate`, see kubernetes/internal/gendata.
+var featureGates = map[string][]featureGateState{
+	"APIListChunking": {
+		{since: 20, enabled: true},
+		{since: 29, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"APIPriorityAndFairness": {
+		{since: 20, enabled: true},
+		{since: 29, enabled: true, locked: true},
+		{since: 31, enabled: true, locked: true, removed: true},
+	},
+	"APIResponseCompression": {
+		{since: 20, enabled: true},
+	},
+	"APISelfSubjectReview": {
+		{since: 26},
+		{since: 27, enabled: true},
+		{since: 28, enabled: true, locked: true},
+		{since: 30, enabled: true, locked: true, removed: true},
+	},
+	"APIServerIdentity": {
+		{since: 20},
+		{since: 26, enabled: true},
+	},
+	"APIServerTracing": {
+		{since: 22},
+		{since: 27, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"APIServingWithRoutine": {
+		{since: 30, enabled: true},
+		{since: 31},
+	},
+	"AdmissionWebhookMatchConditions": {
+		{since: 27},
+		{since: 28, enabled: true},
+		{since: 30, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"AdvancedAuditing": {
+		{since: 20, enabled: true},
+		{since: 27, enabled: true, locked: true},
+		{since: 28, enabled: true, locked: true, removed: true},
+	},
+	"AggregatedDiscoveryEndpoint": {
+		{since: 26},
+		{since: 27, enabled: true},
+		{since: 30, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"AggregatedDiscoveryRemoveBetaType": {
+		{since: 33, enabled: true},
+	},
+	"AllowDNSOnlyNodeCSR": {
+		{since: 31},
+	},
+	"AllowInsecureBackendProxy": {
+		{since: 20, enabled: true},
+		{since: 21, enabled: true, locked: true},
+		{since: 23, enabled: true, locked: true, removed: true},
+	},
+	"AllowInsecureKubeletCertificateSigningRequests": {
+		{since: 31},
+	},
+	"AllowOverwriteTerminationGracePeriodSeconds": {
+		{since: 32},
+	},
+	"AllowParsingUserUIDFromCertAuth": {
+		{since: 33, enabled: true},
+	},
+	"AllowServiceLBStatusOnNonLB": {
+		{since: 29},
+		{since: 32, locked: true},
+	},
+	"AllowUnsafeMalformedObjectDeletion": {
+		{since: 32},
+	},
+	"AnonymousAuthConfigurableEndpoints": {
+		{since: 31},
+		{since: 32, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"AnyVolumeDataSource": {
+		{since: 20},
+		{since: 24, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"AppArmor": {
+		{since: 20, enabled: true},
+		{since: 31, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"AppArmorFields": {
+		{since: 30, enabled: true},
+		{since: 31, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"AttachVolumeLimit": {
+		{since: 20, enabled: true, locked: true},
+		{since: 21, enabled: true, locked: true, removed: true},
+	},
+	"AuthorizeNodeWithSelectors": {
+		{since: 31},
+		{since: 32, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"AuthorizeWithSelectors": {
+		{since: 31},
+		{since: 32, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"BalanceAttachedNodeVolumes": {
+		{since: 20},
+		{since: 22, removed: true},
+	},
+	"BlockVolume": {
+		{since: 20, enabled: true, locked: true},
+		{since: 21, enabled: true, locked: true, removed: true},
+	},
+	"BoundServiceAccountTokenVolume": {
+		{since: 20},
+		{since: 21, enabled: true},
+		{since: 22, enabled: true, locked: true},
+		{since: 23, enabled: true, locked: true, removed: true},
+	},
+	"BtreeWatchCache": {
+		{since: 32, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"CBORServingAndStorage": {
+		{since: 32},
+	},
+	"CPUCFSQuotaPeriod": {
+		{since: 32},
+		{since: 33, removed: true},
+	},
+	"CPUManager": {
+		{since: 20, enabled: true},
+		{since: 26, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"CPUManagerPolicyAlphaOptions": {
+		{since: 23},
+	},
+	"CPUManagerPolicyBetaOptions": {
+		{since: 23, enabled: true},
+	},
+	"CPUManagerPolicyOptions": {
+		{since: 22},
+		{since: 23, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"CRDValidationRatcheting": {
+		{since: 28},
+		{since: 30, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"CRIContainerLogRotation": {
+		{since: 20, enabled: true},
+		{since: 21, enabled: true, locked: true},
+		{since: 22, enabled: true, locked: true, removed: true},
+	},
+	"CSIBlockVolume": {
+		{since: 20, enabled: true, locked: true},
+		{since: 21, enabled: true, locked: true, removed: true},
+	},
+	"CSIDriverRegistry": {
+		{since: 20, enabled: true, locked: true},
+		{since: 21, enabled: true, locked: true, removed: true},
+	},
+	"CSIInlineVolume": {
+		{since: 20, enabled: true},
+		{since: 25, enabled: true, locked: true},
+		{since: 27, enabled: true, locked: true, removed: true},
+	},
+	"CSIMigration": {
+		{since: 20, enabled: true},
+		{since: 25, enabled: true, locked: true},
+		{since: 27, enabled: true, locked: true, removed: true},
+	},
+	"CSIMigrationAWS": {
+		{since: 20},
+		{since: 23, enabled: true},
+		{since: 25, enabled: true, locked: true},
+		{since: 27, enabled: true, locked: true, removed: true},
+	},
+	"CSIMigrationAWSComplete": {
+		{since: 20},
+		{since: 21, removed: true},
+	},
+	"CSIMigrationAzureDisk": {
+		{since: 20},
+		{since: 23, enabled: true},
+		{since: 25, enabled: true, locked: true},
+		{since: 27, enabled: true, locked: true, removed: true},
+	},
+	"CSIMigrationAzureDiskComplete": {
+		{since: 20},
+		{since: 21, removed: true},
+	},
+	"CSIMigrationAzureFile": {
+		{since: 20},
+		{since: 24, enabled: true},
+		{since: 27, enabled: true, locked: true},
+		{since: 30, enabled: true, locked: true, removed: true},
+	},
+	"CSIMigrationAzureFileComplete": {
+		{since: 20},
+		{since: 21, removed: true},
+	},
+	"CSIMigrationGCE": {
+		{since: 20},
+		{since: 23, enabled: true},
+		{since: 25, enabled: true, locked: true},
+		{since: 28, enabled: true, locked: true, removed: true},
+	},
+	"CSIMigrationGCEComplete": {
+		{since: 20},
+		{since: 21, removed: true},
+	},
+	"CSIMigrationOpenStack": {
+		{since: 20},
+		{since: 21, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 26, enabled: true, locked: true, removed: true},
+	},
+	"CSIMigrationOpenStackComplete": {
+		{since: 20},
+		{since: 21, removed: true},
+	},
+	"CSIMigrationPortworx": {
+		{since: 23},
+		{since: 31, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"CSIMigrationRBD": {
+		{since: 24},
+		{since: 31, removed: true},
+	},
+	"CSIMigrationvSphere": {
+		{since: 20},
+		{since: 25, enabled: true},
+		{since: 26, enabled: true, locked: true},
+		{since: 29, enabled: true, locked: true, removed: true},
+	},
+	"CSIMigrationvSphereComplete": {
+		{since: 20},
+		{since: 22, removed: true},
+	},
+	"CSINodeExpandSecret": {
+		{since: 25},
+		{since: 27, enabled: true},
+		{since: 29, enabled: true, locked: true},
+		{since: 31, enabled: true, locked: true, removed: true},
+	},
+	"CSINodeInfo": {
+		{since: 20, enabled: true, locked: true},
+		{since: 21, enabled: true, locked: true, removed: true},
+	},
+	"CSIServiceAccountToken": {
+		{since: 20},
+		{since: 21, enabled: true},
+		{since: 22, enabled: true, locked: true},
+		{since: 25, enabled: true, locked: true, removed: true},
+	},
+	"CSIStorageCapacity": {
+		{since: 20},
+		{since: 21, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 28, enabled: true, locked: true, removed: true},
+	},
+	"CSIVolumeFSGroupPolicy": {
+		{since: 20, enabled: true},
+		{since: 23, enabled: true, locked: true},
+		{since: 25, enabled: true, locked: true, removed: true},
+	},
+	"CSIVolumeHealth": {
+		{since: 21},
+	},
+	"CSRDuration": {
+		{since: 22, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 26, enabled: true, locked: true, removed: true},
+	},
+	"ClearingNominatedNodeNameAfterBinding": {
+		{since: 34},
+	},
+	"ClientsAllowCBOR": {
+		{since: 32},
+	},
+	"ClientsPreferCBOR": {
+		{since: 32},
+	},
+	"CloudControllerManagerWebhook": {
+		{since: 27},
+	},
+	"CloudDualStackNodeIPs": {
+		{since: 27},
+		{since: 29, enabled: true},
+		{since: 30, enabled: true, locked: true},
+		{since: 32, enabled: true, locked: true, removed: true},
+	},
+	"ClusterTrustBundle": {
+		{since: 27},
+	},
+	"ClusterTrustBundleProjection": {
+		{since: 29},
+	},
+	"ComponentFlagz": {
+		{since: 32},
+	},
+	"ComponentStatusz": {
+		{since: 32},
+	},
+	"ConcurrentWatchObjectDecode": {
+		{since: 31},
+	},
+	"ConfigurableFSGroupPolicy": {
+		{since: 20, enabled: true},
+		{since: 23, enabled: true, locked: true},
+		{since: 25, enabled: true, locked: true, removed: true},
+	},
+	"ConsistentHTTPGetHandlers": {
+		{since: 26, enabled: true},
+		{since: 30, enabled: true, locked: true},
+		{since: 31, enabled: true, locked: true, removed: true},
+	},
+	"ConsistentListFromCache": {
+		{since: 28},
+		{since: 31, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"ContainerCheckpoint": {
+		{since: 25},
+		{since: 30, enabled: true},
+	},
+	"ContainerRestartRules": {
+		{since: 34},
+	},
+	"ContainerStopSignals": {
+		{since: 33},
+	},
+	"ContextualLogging": {
+		{since: 25},
+		{since: 33, enabled: true},
+	},
+	"ControllerManagerLeaderMigration": {
+		{since: 21},
+		{since: 22, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 27, enabled: true, locked: true, removed: true},
+	},
+	"CoordinatedLeaderElection": {
+		{since: 31},
+	},
+	"CronJobControllerV2": {
+		{since: 20},
+		{since: 21, enabled: true},
+		{since: 22, enabled: true, locked: true},
+		{since: 23, enabled: true, locked: true, removed: true},
+	},
+	"CronJobTimeZone": {
+		{since: 24},
+		{since: 25, enabled: true},
+		{since: 27, enabled: true, locked: true},
+		{since: 29, enabled: true, locked: true, removed: true},
+	},
+	"CronJobsScheduledAnnotation": {
+		{since: 28, enabled: true},
+		{since: 32, enabled: true, locked: true},
+	},
+	"CrossNamespaceVolumeDataSource": {
+		{since: 26},
+	},
+	"CustomCPUCFSQuotaPeriod": {
+		{since: 20},
+		{since: 32, removed: true},
+		{since: 33},
+	},
+	"CustomResourceFieldSelectors": {
+		{since: 30},
+		{since: 31, enabled: true},
+		{since: 32, enabled: true, locked: true},
+	},
+	"CustomResourceValidationExpressions": {
+		{since: 23},
+		{since: 25, enabled: true},
+		{since: 29, enabled: true, locked: true},
+		{since: 31, enabled: true, locked: true, removed: true},
+	},
+	"DRAAdminAccess": {
+		{since: 32},
+		{since: 34, enabled: true},
+	},
+	"DRAConsumableCapacity": {
+		{since: 34},
+	},
+	"DRAControlPlaneController": {
+		{since: 31},
+		{since: 32, removed: true},
+	},
+	"DRADeviceBindingConditions": {
+		{since: 34},
+	},
+	"DRADeviceTaints": {
+		{since: 33},
+	},
+	"DRAExtendedResource": {
+		{since: 34},
+	},
+	"DRAPartitionableDevices": {
+		{since: 33},
+	},
+	"DRAPrioritizedList": {
+		{since: 33},
+		{since: 34, enabled: true},
+	},
+	"DRAResourceClaimDeviceStatus": {
+		{since: 32},
+		{since: 33, enabled: true},
+	},
+	"DRASchedulerFilterTimeout": {
+		{since: 34, enabled: true},
+	},
+	"DaemonSetUpdateSurge": {
+		{since: 21},
+		{since: 22, enabled: true},
+		{since: 25, enabled: true, locked: true},
+		{since: 27, enabled: true, locked: true, removed: true},
+	},
+	"DeclarativeValidation": {
+		{since: 33, enabled: true},
+	},
+	"DeclarativeValidationTakeover": {
+		{since: 33},
+	},
+	"DefaultHostNetworkHostPortsInPodTemplates": {
+		{since: 28},
+		{since: 31, removed: true},
+	},
+	"DefaultPodTopologySpread": {
+		{since: 20, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 26, enabled: true, locked: true, removed: true},
+	},
+	"DelegateFSGroupToCSIDriver": {
+		{since: 22},
+		{since: 23, enabled: true},
+		{since: 26, enabled: true, locked: true},
+		{since: 28, enabled: true, locked: true, removed: true},
+	},
+	"DeploymentReplicaSetTerminatingReplicas": {
+		{since: 33},
+	},
+	"DetectCacheInconsistency": {
+		{since: 34, enabled: true},
+	},
+	"DevicePluginCDIDevices": {
+		{since: 28},
+		{since: 29, enabled: true},
+		{since: 31, enabled: true, locked: true},
+		{since: 34, enabled: true, locked: true, removed: true},
+	},
+	"DevicePlugins": {
+		{since: 20, enabled: true},
+		{since: 26, enabled: true, locked: true},
+		{since: 28, enabled: true, locked: true, removed: true},
+	},
+	"DisableAcceleratorUsageMetrics": {
+		{since: 20, enabled: true},
+		{since: 25, enabled: true, locked: true},
+		{since: 28, enabled: true, locked: true, removed: true},
+	},
+	"DisableAllocatorDualWrite": {
+		{since: 31},
+		{since: 34, enabled: true},
+	},
+	"DisableCPUQuotaWithExclusiveCPUs": {
+		{since: 33, enabled: true},
+	},
+	"DisableCloudProviders": {
+		{since: 22},
+		{since: 29, enabled: true},
+		{since: 31, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"DisableKubeletCloudCredentialProviders": {
+		{since: 23},
+		{since: 29, enabled: true},
+		{since: 31, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"DisableNodeKubeProxyVersion": {
+		{since: 29},
+		{since: 31, enabled: true},
+		{since: 32},
+		{since: 33, enabled: true},
+	},
+	"DownwardAPIHugePages": {
+		{since: 20},
+		{since: 23, enabled: true},
+		{since: 27, enabled: true, locked: true},
+		{since: 29, enabled: true, locked: true, removed: true},
+	},
+	"DryRun": {
+		{since: 20, enabled: true},
+		{since: 26, enabled: true, locked: true},
+		{since: 28, enabled: true, locked: true, removed: true},
+	},
+	"DynamicKubeletConfig": {
+		{since: 20, enabled: true},
+		{since: 22},
+		{since: 26, removed: true},
+	},
+	"DynamicResourceAllocation": {
+		{since: 26},
+		{since: 34, enabled: true},
+	},
+	"EfficientWatchResumption": {
+		{since: 20},
+		{since: 21, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"ElasticIndexedJob": {
+		{since: 27, enabled: true},
+		{since: 31, enabled: true, locked: true},
+		{since: 34, enabled: true, locked: true, removed: true},
+	},
+	"EndpointSlice": {
+		{since: 20, enabled: true},
+		{since: 21, enabled: true, locked: true},
+		{since: 25, enabled: true, locked: true, removed: true},
+	},
+	"EndpointSliceNodeName": {
+		{since: 20},
+		{since: 21, enabled: true, locked: true},
+		{since: 25, enabled: true, locked: true, removed: true},
+	},
+	"EndpointSliceProxying": {
+		{since: 20, enabled: true},
+		{since: 22, enabled: true, locked: true},
+		{since: 25, enabled: true, locked: true, removed: true},
+	},
+	"EndpointSliceTerminatingCondition": {
+		{since: 20},
+		{since: 22, enabled: true},
+		{since: 26, enabled: true, locked: true},
+		{since: 28, enabled: true, locked: true, removed: true},
+	},
+	"EnvFiles": {
+		{since: 34},
+	},
+	"EphemeralContainers": {
+		{since: 20},
+		{since: 23, enabled: true},
+		{since: 25, enabled: true, locked: true},
+		{since: 27, enabled: true, locked: true, removed: true},
+	},
+	"EvenPodsSpread": {
+		{since: 20, enabled: true, locked: true},
+		{since: 21, enabled: true, locked: true, removed: true},
+	},
+	"EventedPLEG": {
+		{since: 26},
+	},
+	"ExecProbeTimeout": {
+		{since: 20, enabled: true},
+	},
+	"ExpandCSIVolumes": {
+		{since: 20, enabled: true},
+		{since: 27, enabled: true, removed: true},
+	},
+	"ExpandInUsePersistentVolumes": {
+		{since: 20, enabled: true},
+		{since: 27, enabled: true, removed: true},
+	},
+	"ExpandPersistentVolumes": {
+		{since: 20, enabled: true},
+		{since: 27, enabled: true, removed: true},
+	},
+	"ExpandedDNSConfig": {
+		{since: 22},
+		{since: 26, enabled: true},
+		{since: 28, enabled: true, locked: true},
+		{since: 30, enabled: true, locked: true, removed: true},
+	},
+	"ExperimentalHostUserNamespaceDefaulting": {
+		{since: 20},
+		{since: 28, locked: true},
+		{since: 30, locked: true, removed: true},
+	},
+	"ExternalPolicyForExternalIP": {
+		{since: 20, enabled: true, locked: true},
+		{since: 22, enabled: true, locked: true, removed: true},
+	},
+	"ExternalServiceAccountTokenSigner": {
+		{since: 32},
+		{since: 34, enabled: true},
+	},
+	"GRPCContainerProbe": {
+		{since: 23},
+		{since: 24, enabled: true},
+		{since: 27, enabled: true, locked: true},
+		{since: 29, enabled: true, locked: true, removed: true},
+	},
+	"GenericEphemeralVolume": {
+		{since: 20},
+		{since: 21, enabled: true},
+		{since: 23, enabled: true, locked: true},
+		{since: 25, enabled: true, locked: true, removed: true},
+	},
+	"GitRepoVolumeDriver": {
+		{since: 33},
+	},
+	"GracefulNodeShutdown": {
+		{since: 20},
+		{since: 21, enabled: true},
+	},
+	"GracefulNodeShutdownBasedOnPodPriority": {
+		{since: 23},
+		{since: 24, enabled: true},
+	},
+	"HPAConfigurableTolerance": {
+		{since: 33},
+	},
+	"HPAContainerMetrics": {
+		{since: 20},
+		{since: 27, enabled: true},
+		{since: 30, enabled: true, locked: true},
+		{since: 32, enabled: true, locked: true, removed: true},
+	},
+	"HPAScaleToZero": {
+		{since: 20},
+	},
+	"HonorPVReclaimPolicy": {
+		{since: 23},
+		{since: 31, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"HostnameOverride": {
+		{since: 34},
+	},
+	"HugePageStorageMediumSize": {
+		{since: 20, enabled: true},
+		{since: 22, enabled: true, locked: true},
+		{since: 24, enabled: true, locked: true, removed: true},
+	},
+	"HyperVContainer": {
+		{since: 20},
+		{since: 21, removed: true},
+	},
+	"IPTablesOwnershipCleanup": {
+		{since: 25},
+		{since: 27, enabled: true},
+		{since: 28, enabled: true, locked: true},
+		{since: 30, enabled: true, locked: true, removed: true},
+	},
+	"IPv6DualStack": {
+		{since: 20},
+		{since: 21, enabled: true},
+		{since: 23, enabled: true, locked: true},
+		{since: 27, enabled: true, locked: true, removed: true},
+	},
+	"IdentifyPodOS": {
+		{since: 23},
+		{since: 24, enabled: true},
+		{since: 25, enabled: true, locked: true},
+		{since: 27, enabled: true, locked: true, removed: true},
+	},
+	"ImageMaximumGCAge": {
+		{since: 29},
+		{since: 30, enabled: true},
+	},
+	"ImageVolume": {
+		{since: 31},
+	},
+	"ImmutableEphemeralVolumes": {
+		{since: 20, enabled: true},
+		{since: 21, enabled: true, locked: true},
+		{since: 24, enabled: true, locked: true, removed: true},
+	},
+	"InOrderInformers": {
+		{since: 33, enabled: true},
+	},
+	"InPlacePodVerticalScaling": {
+		{since: 27},
+		{since: 33, enabled: true},
+	},
+	"InPlacePodVerticalScalingAllocatedStatus": {
+		{since: 32},
+	},
+	"InPlacePodVerticalScalingExclusiveCPUs": {
+		{since: 32},
+	},
+	"InPlacePodVerticalScalingExclusiveMemory": {
+		{since: 34},
+	},
+	"InTreePluginAWSUnregister": {
+		{since: 21},
+		{since: 31, removed: true},
+	},
+	"InTreePluginAzureDiskUnregister": {
+		{since: 21},
+		{since: 31, removed: true},
+	},
+	"InTreePluginAzureFileUnregister": {
+		{since: 21},
+		{since: 31, removed: true},
+	},
+	"InTreePluginGCEUnregister": {
+		{since: 21},
+		{since: 31, removed: true},
+	},
+	"InTreePluginOpenStackUnregister": {
+		{since: 21},
+		{since: 31, removed: true},
+	},
+	"InTreePluginPortworxUnregister": {
+		{since: 23},
+	},
+	"InTreePluginRBDUnregister": {
+		{since: 23},
+		{since: 31, removed: true},
+	},
+	"InTreePluginvSphereUnregister": {
+		{since: 21},
+		{since: 31, removed: true},
+	},
+	"IndexedJob": {
+		{since: 21},
+		{since: 22, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 26, enabled: true, locked: true, removed: true},
+	},
+	"InformerResourceVersion": {
+		{since: 30},
+	},
+	"IngressClassNamespacedParams": {
+		{since: 21},
+		{since: 22, enabled: true},
+		{since: 23, enabled: true, locked: true},
+		{since: 25, enabled: true, locked: true, removed: true},
+	},
+	"JobBackoffLimitPerIndex": {
+		{since: 28},
+		{since: 29, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"JobManagedBy": {
+		{since: 30},
+		{since: 32, enabled: true},
+	},
+	"JobMutableNodeSchedulingDirectives": {
+		{since: 23, enabled: true},
+		{since: 27, enabled: true, locked: true},
+		{since: 29, enabled: true, locked: true, removed: true},
+	},
+	"JobPodFailurePolicy": {
+		{since: 25},
+		{since: 26, enabled: true},
+		{since: 31, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"JobPodReplacementPolicy": {
+		{since: 28},
+		{since: 29, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"JobReadyPods": {
+		{since: 23},
+		{since: 24, enabled: true},
+		{since: 29, enabled: true, locked: true},
+		{since: 31, enabled: true, locked: true, removed: true},
+	},
+	"JobSuccessPolicy": {
+		{since: 30},
+		{since: 31, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"JobTrackingWithFinalizers": {
+		{since: 22},
+		{since: 23, enabled: true},
+		{since: 24},
+		{since: 25, enabled: true},
+		{since: 26, enabled: true, locked: true},
+		{since: 29, enabled: true, locked: true, removed: true},
+	},
+	"KMSv1": {
+		{since: 28, enabled: true},
+		{since: 29},
+	},
+	"KMSv2": {
+		{since: 25},
+		{since: 27, enabled: true},
+		{since: 29, enabled: true, locked: true},
+		{since: 32, enabled: true, locked: true, removed: true},
+	},
+	"KMSv2KDF": {
+		{since: 28},
+		{since: 29, enabled: true, locked: true},
+		{since: 32, enabled: true, locked: true, removed: true},
+	},
+	"KubeProxyDrainingTerminatingNodes": {
+		{since: 28},
+		{since: 30, enabled: true},
+		{since: 31, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"KubeletCgroupDriverFromCRI": {
+		{since: 28},
+		{since: 31, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"KubeletCrashLoopBackOffMax": {
+		{since: 32},
+	},
+	"KubeletCredentialProviders": {
+		{since: 20},
+		{since: 24, enabled: true},
+		{since: 26, enabled: true, locked: true},
+		{since: 28, enabled: true, locked: true, removed: true},
+	},
+	"KubeletEnsureSecretPulledImages": {
+		{since: 33},
+	},
+	"KubeletFineGrainedAuthz": {
+		{since: 32},
+		{since: 33, enabled: true},
+	},
+	"KubeletInUserNamespace": {
+		{since: 22},
+	},
+	"KubeletPSI": {
+		{since: 33},
+		{since: 34, enabled: true},
+	},
+	"KubeletPodResources": {
+		{since: 20, enabled: true},
+		{since: 28, enabled: true, locked: true},
+		{since: 30, enabled: true, locked: true, removed: true},
+	},
+	"KubeletPodResourcesDynamicResources": {
+		{since: 27},
+		{since: 34, enabled: true},
+	},
+	"KubeletPodResourcesGet": {
+		{since: 27},
+		{since: 34, enabled: true},
+	},
+	"KubeletPodResourcesGetAllocatable": {
+		{since: 21},
+		{since: 23, enabled: true},
+		{since: 28, enabled: true, locked: true},
+		{since: 30, enabled: true, locked: true, removed: true},
+	},
+	"KubeletPodResourcesListUseActivePods": {
+		{since: 34, enabled: true},
+	},
+	"KubeletRegistrationGetOnExistsOnly": {
+		{since: 32},
+	},
+	"KubeletSeparateDiskGC": {
+		{since: 29},
+		{since: 31, enabled: true},
+	},
+	"KubeletServiceAccountTokenForCredentialProviders": {
+		{since: 33},
+		{since: 34, enabled: true},
+	},
+	"KubeletTracing": {
+		{since: 25},
+		{since: 27, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"LegacyNodeRoleBehavior": {
+		{since: 20, enabled: true},
+		{since: 21, locked: true},
+		{since: 22, locked: true, removed: true},
+	},
+	"LegacyServiceAccountTokenCleanUp": {
+		{since: 28},
+		{since: 29, enabled: true},
+		{since: 30, enabled: true, locked: true},
+		{since: 32, enabled: true, locked: true, removed: true},
+	},
+	"LegacyServiceAccountTokenNoAutoGeneration": {
+		{since: 24, enabled: true},
+		{since: 27, enabled: true, locked: true},
+		{since: 29, enabled: true, locked: true, removed: true},
+	},
+	"LegacyServiceAccountTokenTracking": {
+		{since: 26},
+		{since: 27, enabled: true},
+		{since: 28, enabled: true, locked: true},
+		{since: 30, enabled: true, locked: true, removed: true},
+	},
+	"LegacySidecarContainers": {
+		{since: 33},
+		{since: 34, removed: true},
+	},
+	"ListFromCacheSnapshot": {
+		{since: 33},
+		{since: 34, enabled: true},
+	},
+	"LoadBalancerIPMode": {
+		{since: 29},
+		{since: 30, enabled: true},
+		{since: 32, enabled: true, locked: true},
+	},
+	"LocalStorageCapacityIsolation": {
+		{since: 20, enabled: true},
+		{since: 25, enabled: true, locked: true},
+		{since: 27, enabled: true, locked: true, removed: true},
+	},
+	"LocalStorageCapacityIsolationFSQuotaMonitoring": {
+		{since: 20},
+		{since: 25, enabled: true},
+		{since: 26},
+	},
+	"LogarithmicScaleDown": {
+		{since: 21},
+		{since: 22, enabled: true},
+		{since: 31, enabled: true, locked: true},
+	},
+	"LoggingAlphaOptions": {
+		{since: 25},
+	},
+	"LoggingBetaOptions": {
+		{since: 25, enabled: true},
+	},
+	"MatchLabelKeysInPodAffinity": {
+		{since: 29},
+		{since: 31, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"MatchLabelKeysInPodTopologySpread": {
+		{since: 25},
+		{since: 27, enabled: true},
+	},
+	"MatchLabelKeysInPodTopologySpreadSelectorMerge": {
+		{since: 34, enabled: true},
+	},
+	"MaxUnavailableStatefulSet": {
+		{since: 24},
+	},
+	"MemoryManager": {
+		{since: 21},
+		{since: 22, enabled: true},
+		{since: 32, enabled: true, locked: true},
+	},
+	"MemoryQoS": {
+		{since: 22},
+	},
+	"MinDomainsInPodTopologySpread": {
+		{since: 24},
+		{since: 27, enabled: true},
+		{since: 30, enabled: true, locked: true},
+		{since: 32, enabled: true, locked: true, removed: true},
+	},
+	"MinimizeIPTablesRestore": {
+		{since: 26},
+		{since: 27, enabled: true},
+		{since: 28, enabled: true, locked: true},
+		{since: 30, enabled: true, locked: true, removed: true},
+	},
+	"MixedProtocolLBService": {
+		{since: 20},
+		{since: 24, enabled: true},
+		{since: 26, enabled: true, locked: true},
+		{since: 28, enabled: true, locked: true, removed: true},
+	},
+	"MultiCIDRRangeAllocator": {
+		{since: 25},
+		{since: 29, removed: true},
+	},
+	"MultiCIDRServiceAllocator": {
+		{since: 27},
+		{since: 33, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"MutableCSINodeAllocatableCount": {
+		{since: 33},
+	},
+	"MutatingAdmissionPolicy": {
+		{since: 30},
+	},
+	"NFTablesProxyMode": {
+		{since: 29},
+		{since: 31, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"NamespaceDefaultLabelName": {
+		{since: 21, enabled: true},
+		{since: 22, enabled: true, locked: true},
+		{since: 24, enabled: true, locked: true, removed: true},
+	},
+	"NetworkPolicyEndPort": {
+		{since: 21},
+		{since: 22, enabled: true},
+		{since: 25, enabled: true, locked: true},
+		{since: 27, enabled: true, locked: true, removed: true},
+	},
+	"NetworkPolicyStatus": {
+		{since: 24},
+		{since: 28, removed: true},
+	},
+	"NewVolumeManagerReconstruction": {
+		{since: 27, enabled: true},
+		{since: 30, enabled: true, locked: true},
+		{since: 32, enabled: true, locked: true, removed: true},
+	},
+	"NodeDisruptionExclusion": {
+		{since: 20, enabled: true},
+		{since: 21, enabled: true, locked: true},
+		{since: 22, enabled: true, locked: true, removed: true},
+	},
+	"NodeInclusionPolicyInPodTopologySpread": {
+		{since: 25},
+		{since: 26, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"NodeLease": {
+		{since: 20, enabled: true, locked: true},
+		{since: 23, enabled: true, locked: true, removed: true},
+	},
+	"NodeLogQuery": {
+		{since: 27},
+	},
+	"NodeOutOfServiceVolumeDetach": {
+		{since: 24},
+		{since: 26, enabled: true},
+		{since: 28, enabled: true, locked: true},
+		{since: 32, enabled: true, locked: true, removed: true},
+	},
+	"NodeSwap": {
+		{since: 22},
+		{since: 30, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"NominatedNodeNameForExpectation": {
+		{since: 34},
+	},
+	"NonPreemptingPriority": {
+		{since: 20, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 26, enabled: true, locked: true, removed: true},
+	},
+	"OpenAPIEnums": {
+		{since: 23},
+		{since: 24, enabled: true},
+	},
+	"OpenAPIV3": {
+		{since: 23},
+		{since: 24, enabled: true},
+		{since: 27, enabled: true, locked: true},
+		{since: 29, enabled: true, locked: true, removed: true},
+	},
+	"OrderedNamespaceDeletion": {
+		{since: 33, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"PDBUnhealthyPodEvictionPolicy": {
+		{since: 26},
+		{since: 27, enabled: true},
+		{since: 31, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"PersistentVolumeLastPhaseTransitionTime": {
+		{since: 28},
+		{since: 29, enabled: true},
+		{since: 31, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"PodAffinityNamespaceSelector": {
+		{since: 21},
+		{since: 22, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 26, enabled: true, locked: true, removed: true},
+	},
+	"PodAndContainerStatsFromCRI": {
+		{since: 23},
+	},
+	"PodCertificateRequest": {
+		{since: 34},
+	},
+	"PodDeletionCost": {
+		{since: 21},
+		{since: 22, enabled: true},
+	},
+	"PodDisruptionBudget": {
+		{since: 20, enabled: true},
+		{since: 21, enabled: true, locked: true},
+		{since: 25, enabled: true, locked: true, removed: true},
+	},
+	"PodDisruptionConditions": {
+		{since: 25},
+		{since: 26, enabled: true},
+		{since: 31, enabled: true, locked: true},
+		{since: 34, enabled: true, locked: true, removed: true},
+	},
+	"PodHasNetworkCondition": {
+		{since: 25},
+		{since: 28, removed: true},
+	},
+	"PodHostIPs": {
+		{since: 28},
+		{since: 29, enabled: true},
+		{since: 30, enabled: true, locked: true},
+		{since: 32, enabled: true, locked: true, removed: true},
+	},
+	"PodIndexLabel": {
+		{since: 28, enabled: true},
+		{since: 32, enabled: true, locked: true},
+	},
+	"PodLevelResources": {
+		{since: 32},
+		{since: 34, enabled: true},
+	},
+	"PodLifecycleSleepAction": {
+		{since: 29},
+		{since: 30, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"PodLifecycleSleepActionAllowZero": {
+		{since: 32},
+		{since: 33, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"PodLogsQuerySplitStreams": {
+		{since: 32},
+	},
+	"PodObservedGenerationTracking": {
+		{since: 33},
+		{since: 34, enabled: true},
+	},
+	"PodOverhead": {
+		{since: 20, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 26, enabled: true, locked: true, removed: true},
+	},
+	"PodReadyToStartContainersCondition": {
+		{since: 28},
+		{since: 29, enabled: true},
+	},
+	"PodSchedulingReadiness": {
+		{since: 26},
+		{since: 27, enabled: true},
+		{since: 30, enabled: true, locked: true},
+	},
+	"PodSecurity": {
+		{since: 22},
+		{since: 23, enabled: true},
+		{since: 25, enabled: true, locked: true},
+		{since: 28, enabled: true, locked: true, removed: true},
+	},
+	"PodTopologyLabelsAdmission": {
+		{since: 33},
+	},
+	"PortForwardWebsockets": {
+		{since: 30},
+		{since: 31, enabled: true},
+	},
+	"PreferNominatedNode": {
+		{since: 21},
+		{since: 22, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 26, enabled: true, locked: true, removed: true},
+	},
+	"PreferSameTrafficDistribution": {
+		{since: 33},
+		{since: 34, enabled: true},
+	},
+	"PreventStaticPodAPIReferences": {
+		{since: 34, enabled: true},
+	},
+	"ProbeHostPodSecurityStandards": {
+		{since: 34, enabled: true, locked: true},
+	},
+	"ProbeTerminationGracePeriod": {
+		{since: 21},
+		{since: 25, enabled: true},
+		{since: 28, enabled: true, locked: true},
+		{since: 29, enabled: true, locked: true, removed: true},
+	},
+	"ProcMountType": {
+		{since: 20},
+		{since: 33, enabled: true},
+	},
+	"ProxyTerminatingEndpoints": {
+		{since: 22},
+		{since: 26, enabled: true},
+		{since: 28, enabled: true, locked: true},
+		{since: 30, enabled: true, locked: true, removed: true},
+	},
+	"QOSReserved": {
+		{since: 20},
+	},
+	"ReadWriteOncePod": {
+		{since: 22},
+		{since: 27, enabled: true},
+		{since: 29, enabled: true, locked: true},
+		{since: 31, enabled: true, locked: true, removed: true},
+	},
+	"RecoverVolumeExpansionFailure": {
+		{since: 23},
+		{since: 32, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"RecursiveReadOnlyMounts": {
+		{since: 30},
+		{since: 31, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"ReduceDefaultCrashLoopBackOffDecay": {
+		{since: 33},
+	},
+	"RelaxedDNSSearchValidation": {
+		{since: 32},
+		{since: 33, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"RelaxedEnvironmentVariableValidation": {
+		{since: 30},
+		{since: 32, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"RelaxedServiceNameValidation": {
+		{since: 34},
+	},
+	"ReloadKubeletServerCertificateFile": {
+		{since: 31, enabled: true},
+	},
+	"RemainingItemCount": {
+		{since: 20, enabled: true},
+		{since: 29, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"RemoteRequestHeaderUID": {
+		{since: 32},
+		{since: 33, enabled: true},
+	},
+	"RemoveSelfLink": {
+		{since: 20, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 30, enabled: true, locked: true, removed: true},
+	},
+	"ResilientWatchCacheInitialization": {
+		{since: 31, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"ResourceHealthStatus": {
+		{since: 31},
+	},
+	"RetroactiveDefaultStorageClass": {
+		{since: 25},
+		{since: 26, enabled: true},
+		{since: 28, enabled: true, locked: true},
+		{since: 29, enabled: true, locked: true, removed: true},
+	},
+	"RetryGenerateName": {
+		{since: 30},
+		{since: 31, enabled: true},
+		{since: 32, enabled: true, locked: true},
+	},
+	"RootCAConfigMap": {
+		{since: 20, enabled: true},
+		{since: 21, enabled: true, locked: true},
+		{since: 22, enabled: true, locked: true, removed: true},
+	},
+	"RotateKubeletClientCertificate": {
+		{since: 20, enabled: true, locked: true},
+		{since: 21, enabled: true, locked: true, removed: true},
+	},
+	"RotateKubeletServerCertificate": {
+		{since: 20, enabled: true},
+	},
+	"RunAsGroup": {
+		{since: 20, enabled: true},
+		{since: 21, enabled: true, locked: true},
+		{since: 22, enabled: true, locked: true, removed: true},
+	},
+	"RuntimeClass": {
+		{since: 20, enabled: true, locked: true},
+		{since: 24, enabled: true, locked: true, removed: true},
+	},
+	"RuntimeClassInImageCriAPI": {
+		{since: 32},
+		{since: 33, removed: true},
+	},
+	"RuntimeClassInImageCriApi": {
+		{since: 29},
+		{since: 32, removed: true},
+		{since: 33},
+	},
+	"SCTPSupport": {
+		{since: 20, enabled: true, locked: true},
+		{since: 22, enabled: true, locked: true, removed: true},
+	},
+	"SELinuxChangePolicy": {
+		{since: 32},
+		{since: 33, enabled: true},
+	},
+	"SELinuxMount": {
+		{since: 30},
+	},
+	"SELinuxMountReadWriteOncePod": {
+		{since: 25},
+		{since: 27, enabled: true},
+	},
+	"SchedulerAsyncAPICalls": {
+		{since: 34, enabled: true},
+	},
+	"SchedulerAsyncPreemption": {
+		{since: 32},
+		{since: 33, enabled: true},
+	},
+	"SchedulerPopFromBackoffQ": {
+		{since: 33, enabled: true},
+	},
+	"SchedulerQueueingHints": {
+		{since: 28, enabled: true},
+		{since: 29},
+		{since: 32, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"SeccompDefault": {
+		{since: 22},
+		{since: 25, enabled: true},
+		{since: 27, enabled: true, locked: true},
+		{since: 29, enabled: true, locked: true, removed: true},
+	},
+	"SecurityContextDeny": {
+		{since: 27},
+		{since: 30, removed: true},
+	},
+	"SelectorIndex": {
+		{since: 20, enabled: true, locked: true},
+		{since: 25, enabled: true, locked: true, removed: true},
+	},
+	"SeparateCacheWatchRPC": {
+		{since: 30, enabled: true},
+		{since: 33},
+	},
+	"SeparateTaintEvictionController": {
+		{since: 29, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"ServerSideApply": {
+		{since: 20, enabled: true},
+		{since: 26, enabled: true, locked: true},
+		{since: 32, enabled: true, locked: true, removed: true},
+	},
+	"ServerSideFieldValidation": {
+		{since: 23},
+		{since: 25, enabled: true},
+		{since: 27, enabled: true, locked: true},
+		{since: 32, enabled: true, locked: true, removed: true},
+	},
+	"ServiceAccountIssuerDiscovery": {
+		{since: 20, enabled: true},
+		{since: 21, enabled: true, locked: true},
+		{since: 23, enabled: true, locked: true, removed: true},
+	},
+	"ServiceAccountNodeAudienceRestriction": {
+		{since: 32, enabled: true},
+	},
+	"ServiceAccountTokenJTI": {
+		{since: 29},
+		{since: 30, enabled: true},
+		{since: 32, enabled: true, locked: true},
+	},
+	"ServiceAccountTokenNodeBinding": {
+		{since: 29},
+		{since: 31, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"ServiceAccountTokenNodeBindingValidation": {
+		{since: 29},
+		{since: 30, enabled: true},
+		{since: 32, enabled: true, locked: true},
+	},
+	"ServiceAccountTokenPodNodeInfo": {
+		{since: 29},
+		{since: 30, enabled: true},
+		{since: 32, enabled: true, locked: true},
+	},
+	"ServiceAppProtocol": {
+		{since: 20, enabled: true, locked: true},
+		{since: 22, enabled: true, locked: true, removed: true},
+	},
+	"ServiceIPStaticSubrange": {
+		{since: 24},
+		{since: 25, enabled: true},
+		{since: 26, enabled: true, locked: true},
+		{since: 28, enabled: true, locked: true, removed: true},
+	},
+	"ServiceInternalTrafficPolicy": {
+		{since: 21},
+		{since: 22, enabled: true},
+		{since: 26, enabled: true, locked: true},
+		{since: 28, enabled: true, locked: true, removed: true},
+	},
+	"ServiceLBNodePortControl": {
+		{since: 20},
+		{since: 22, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 26, enabled: true, locked: true, removed: true},
+	},
+	"ServiceLoadBalancerClass": {
+		{since: 21},
+		{since: 22, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 26, enabled: true, locked: true, removed: true},
+	},
+	"ServiceNodeExclusion": {
+		{since: 20, enabled: true},
+		{since: 21, enabled: true, locked: true},
+		{since: 22, enabled: true, locked: true, removed: true},
+	},
+	"ServiceNodePortStaticSubrange": {
+		{since: 27},
+		{since: 28, enabled: true},
+		{since: 29, enabled: true, locked: true},
+		{since: 31, enabled: true, locked: true, removed: true},
+	},
+	"ServiceTopology": {
+		{since: 20},
+		{since: 22, removed: true},
+	},
+	"ServiceTrafficDistribution": {
+		{since: 30},
+		{since: 31, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"SetHostnameAsFQDN": {
+		{since: 20, enabled: true},
+		{since: 22, enabled: true, locked: true},
+		{since: 24, enabled: true, locked: true, removed: true},
+	},
+	"SidecarContainers": {
+		{since: 28},
+		{since: 29, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"SizeBasedListCostEstimate": {
+		{since: 34, enabled: true},
+	},
+	"SizeMemoryBackedVolumes": {
+		{since: 20},
+		{since: 22, enabled: true},
+		{since: 32, enabled: true, locked: true},
+	},
+	"SkipReadOnlyValidationGCE": {
+		{since: 28},
+		{since: 29, enabled: true},
+		{since: 31, enabled: true, removed: true},
+	},
+	"StableLoadBalancerNodeSet": {
+		{since: 27, enabled: true},
+		{since: 30, enabled: true, locked: true},
+		{since: 32, enabled: true, locked: true, removed: true},
+	},
+	"StartupProbe": {
+		{since: 20, enabled: true, locked: true},
+		{since: 23, enabled: true, locked: true, removed: true},
+	},
+	"StatefulSetAutoDeletePVC": {
+		{since: 23},
+		{since: 27, enabled: true},
+		{since: 32, enabled: true, locked: true},
+	},
+	"StatefulSetMinReadySeconds": {
+		{since: 22},
+		{since: 23, enabled: true},
+		{since: 25, enabled: true, locked: true},
+		{since: 27, enabled: true, locked: true, removed: true},
+	},
+	"StatefulSetStartOrdinal": {
+		{since: 26},
+		{since: 27, enabled: true},
+		{since: 31, enabled: true, locked: true},
+		{since: 34, enabled: true, locked: true, removed: true},
+	},
+	"StorageCapacityScoring": {
+		{since: 33},
+	},
+	"StorageNamespaceIndex": {
+		{since: 30, enabled: true},
+	},
+	"StorageObjectInUseProtection": {
+		{since: 20, enabled: true},
+		{since: 23, enabled: true, locked: true},
+		{since: 25, enabled: true, locked: true, removed: true},
+	},
+	"StorageVersionAPI": {
+		{since: 20},
+	},
+	"StorageVersionHash": {
+		{since: 20, enabled: true},
+	},
+	"StorageVersionMigrator": {
+		{since: 30},
+	},
+	"StreamingCollectionEncodingToJSON": {
+		{since: 33, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"StreamingCollectionEncodingToProtobuf": {
+		{since: 33, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"StreamingProxyRedirects": {
+		{since: 20, enabled: true},
+		{since: 22},
+		{since: 24, removed: true},
+	},
+	"StrictCostEnforcementForVAP": {
+		{since: 31},
+		{since: 32, enabled: true, locked: true},
+	},
+	"StrictCostEnforcementForWebhooks": {
+		{since: 31},
+		{since: 32, enabled: true, locked: true},
+	},
+	"StrictIPCIDRValidation": {
+		{since: 33},
+	},
+	"StructuredAuthenticationConfiguration": {
+		{since: 29},
+		{since: 30, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"StructuredAuthenticationConfigurationEgressSelector": {
+		{since: 34, enabled: true},
+	},
+	"StructuredAuthorizationConfiguration": {
+		{since: 29},
+		{since: 30, enabled: true},
+		{since: 32, enabled: true, locked: true},
+	},
+	"SupplementalGroupsPolicy": {
+		{since: 31},
+		{since: 33, enabled: true},
+	},
+	"SupportNodePidsLimit": {
+		{since: 20, enabled: true, locked: true},
+		{since: 23, enabled: true, locked: true, removed: true},
+	},
+	"SupportPodPidsLimit": {
+		{since: 20, enabled: true, locked: true},
+		{since: 23, enabled: true, locked: true, removed: true},
+	},
+	"SuspendJob": {
+		{since: 21},
+		{since: 22, enabled: true},
+		{since: 24, enabled: true, locked: true},
+		{since: 26, enabled: true, locked: true, removed: true},
+	},
+	"Sysctls": {
+		{since: 20, enabled: true},
+		{since: 21, enabled: true, locked: true},
+		{since: 23, enabled: true, locked: true, removed: true},
+	},
+	"SystemdWatchdog": {
+		{since: 32, enabled: true},
+	},
+	"TTLAfterFinished": {
+		{since: 20},
+		{since: 21, enabled: true},
+		{since: 23, enabled: true, locked: true},
+		{since: 25, enabled: true, locked: true, removed: true},
+	},
+	"TokenRequest": {
+		{since: 20, enabled: true, locked: true},
+		{since: 21, enabled: true, locked: true, removed: true},
+	},
+	"TokenRequestProjection": {
+		{since: 20, enabled: true, locked: true},
+		{since: 21, enabled: true, locked: true, removed: true},
+	},
+	"TokenRequestServiceAccountUIDValidation": {
+		{since: 34, enabled: true},
+	},
+	"TopologyAwareHints": {
+		{since: 21},
+		{since: 24, enabled: true},
+		{since: 33, enabled: true, locked: true},
+	},
+	"TopologyManager": {
+		{since: 20, enabled: true},
+		{since: 27, enabled: true, locked: true},
+		{since: 29, enabled: true, locked: true, removed: true},
+	},
+	"TopologyManagerPolicyAlphaOptions": {
+		{since: 26},
+	},
+	"TopologyManagerPolicyBetaOptions": {
+		{since: 26},
+		{since: 28, enabled: true},
+	},
+	"TopologyManagerPolicyOptions": {
+		{since: 26},
+		{since: 28, enabled: true},
+	},
+	"TranslateStreamCloseWebsocketRequests": {
+		{since: 29},
+		{since: 30, enabled: true},
+	},
+	"UnauthenticatedHTTP2DOSMitigation": {
+		{since: 29, enabled: true},
+	},
+	"UnknownVersionInteroperabilityProxy": {
+		{since: 28},
+	},
+	"UserNamespacesPodSecurityStandards": {
+		{since: 29},
+	},
+	"UserNamespacesStatelessPodsSupport": {
+		{since: 25},
+		{since: 28, removed: true},
+	},
+	"UserNamespacesSupport": {
+		{since: 28},
+		{since: 33, enabled: true},
+	},
+	"ValidateProxyRedirects": {
+		{since: 20, enabled: true},
+		{since: 24, enabled: true, removed: true},
+	},
+	"ValidatingAdmissionPolicy": {
+		{since: 26},
+		{since: 30, enabled: true, locked: true},
+		{since: 32, enabled: true, locked: true, removed: true},
+	},
+	"VolumeAttributesClass": {
+		{since: 29},
+		{since: 34, enabled: true},
+	},
+	"VolumeCapacityPriority": {
+		{since: 21},
+		{since: 33, removed: true},
+	},
+	"VolumePVCDataSource": {
+		{since: 20, enabled: true, locked: true},
+		{since: 21, enabled: true, locked: true, removed: true},
+	},
+	"VolumeSnapshotDataSource": {
+		{since: 20, enabled: true, locked: true},
+		{since: 22, enabled: true, locked: true, removed: true},
+	},
+	"VolumeSubpath": {
+		{since: 20, enabled: true},
+		{since: 23, enabled: true, locked: true},
+		{since: 25, enabled: true, locked: true, removed: true},
+	},
+	"WarningHeaders": {
+		{since: 20, enabled: true},
+		{since: 22, enabled: true, locked: true},
+		{since: 24, enabled: true, locked: true, removed: true},
+	},
+	"WatchBookmark": {
+		{since: 20, enabled: true, locked: true},
+		{since: 33, enabled: true, locked: true, removed: true},
+	},
+	"WatchCacheInitializationPostStartHook": {
+		{since: 31},
+	},
+	"WatchFromStorageWithoutResourceVersion": {
+		{since: 30},
+		{since: 33, locked: true},
+	},
+	"WatchList": {
+		{since: 27},
+		{since: 32, enabled: true},
+		{since: 33},
+		{since: 34, enabled: true},
+	},
+	"WatchListClient": {
+		{since: 30},
+	},
+	"WinDSR": {
+		{since: 20},
+		{since: 33, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"WinOverlay": {
+		{since: 20, enabled: true},
+		{since: 34, enabled: true, locked: true},
+	},
+	"WindowsCPUAndMemoryAffinity": {
+		{since: 32},
+	},
+	"WindowsEndpointSliceProxying": {
+		{since: 20},
+		{since: 21, enabled: true},
+		{since: 22, enabled: true, locked: true},
+		{since: 25, enabled: true, locked: true, removed: true},
+	},
+	"WindowsGMSA": {
+		{since: 20, enabled: true, locked: true},
+		{since: 21, enabled: true, locked: true, removed: true},
+	},
+	"WindowsGracefulNodeShutdown": {
+		{since: 32},
+		{since: 34, enabled: true},
+	},
+	"WindowsHostNetwork": {
+		{since: 26, enabled: true},
+		{since: 33},
+	},
+	"WindowsHostProcessContainers": {
+		{since: 22},
+		{since: 23, enabled: true},
+		{since: 26, enabled: true, locked: true},
+		{since: 28, enabled: true, locked: true, removed: true},
+	},
+	"WindowsRunAsUserName": {
+		{since: 20, enabled: true, locked: true},
+		{since: 21, enabled: true, locked: true, removed: true},
+	},
+	"ZeroLimitedNominalConcurrencyShares": {
+		{since: 29},
+		{since: 30, enabled: true, locked: true},
+		{since: 32, enabled: true, locked: true, removed: true},
+	},
+	"csiMigrationRBD": {
+		{since: 23},
+		{since: 24, removed: true},
+	},
+}
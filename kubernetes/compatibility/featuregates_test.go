@@ -0,0 +1,90 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+)
+
+func TestFeatureGateDefault(t *testing.T) {
+	for _, test := range []struct { //nolint:govet
+		version compatibility.Version
+		gate    string
+
+		expectedEnabled bool
+		expectedLocked  bool
+		expectedKnown   bool
+	}{
+		{
+			version: compatibility.Version{Major: 1, Minor: 27},
+			gate:    "SidecarContainers",
+		},
+		{
+			version:       compatibility.Version{Major: 1, Minor: 28},
+			gate:          "SidecarContainers",
+			expectedKnown: true,
+		},
+		{
+			version:         compatibility.Version{Major: 1, Minor: 30, Patch: 3},
+			gate:            "SidecarContainers",
+			expectedEnabled: true,
+			expectedKnown:   true,
+		},
+		{
+			version:         compatibility.Version{Major: 1, Minor: 33},
+			gate:            "SidecarContainers",
+			expectedEnabled: true,
+			expectedLocked:  true,
+			expectedKnown:   true,
+		},
+		{
+			version:         compatibility.Version{Major: 1, Minor: 31},
+			gate:            "ValidatingAdmissionPolicy",
+			expectedEnabled: true,
+			expectedLocked:  true,
+			expectedKnown:   true,
+		},
+		{
+			version:         compatibility.Version{Major: 1, Minor: 32},
+			gate:            "ValidatingAdmissionPolicy",
+			expectedEnabled: true,
+			expectedLocked:  true,
+		},
+		{
+			version:         compatibility.Version{Major: 2, Minor: 0},
+			gate:            "SidecarContainers",
+			expectedEnabled: true,
+			expectedLocked:  true,
+			expectedKnown:   true,
+		},
+		{
+			version:         compatibility.Version{Major: 2, Minor: 0},
+			gate:            "ValidatingAdmissionPolicy",
+			expectedEnabled: true,
+			expectedLocked:  true,
+		},
+		{
+			version:       compatibility.Version{Major: 1, Minor: 29},
+			gate:          "KMSv1",
+			expectedKnown: true,
+		},
+		{
+			version: compatibility.Version{Major: 1, Minor: 30},
+			gate:    "UnknownGate",
+		},
+	} {
+		t.Run(test.version.String()+"/"+test.gate, func(t *testing.T) {
+			enabled, locked, known := test.version.FeatureGateDefault(test.gate)
+
+			assert.Equal(t, test.expectedEnabled, enabled, "enabled")
+			assert.Equal(t, test.expectedLocked, locked, "locked")
+			assert.Equal(t, test.expectedKnown, known, "known")
+		})
+	}
+}
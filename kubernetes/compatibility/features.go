@@ -10,16 +10,24 @@ const (
 	kubeSchedulerPre131HealthzEndpoint = "/healthz"
 )
 
+// kube-proxy modes.
+const (
+	KubeProxyModeIPTables = "iptables"
+	KubeProxyModeIPVS     = "ipvs"
+	KubeProxyModeNFTables = "nftables"
+)
+
 // SupportsKubeletConfigContainerRuntimeEndpoint returns true if kubelet supports ContainerRuntimEndpoint in kubelet config.
 func (v Version) SupportsKubeletConfigContainerRuntimeEndpoint() bool {
-	// see https://github.com/kubernetes/kubernetes/pull/112136
-	return semver.Version(v).GTE(semver.Version{Major: 1, Minor: 27})
+	return v.Supports(FeatureKubeletConfigContainerRuntimeEndpoint)
 }
 
 // FeatureFlagSeccompDefaultEnabledByDefault returns true if a SeccompDefault feature flag is enabled by default.
 func (v Version) FeatureFlagSeccompDefaultEnabledByDefault() bool {
 	// see https://github.com/kubernetes/kubernetes/pull/110805
-	return semver.Version(v).GTE(semver.Version{Major: 1, Minor: 25})
+	enabled, _, _ := v.FeatureGateDefault("SeccompDefault")
+
+	return enabled
 }
 
 // KubeSchedulerHealthLivenessEndpoint returns the liveness endpoint for the kube-scheduler health check.
@@ -67,7 +75,9 @@ func (v Version) FeatureFlagStructuredAuthorizationConfigurationEnabledByDefault
 	// https://v1-29.docs.kubernetes.io/docs/reference/access-authn-authz/authorization/#configuring-the-api-server-using-an-authorization-config-file
 	// https://v1-30.docs.kubernetes.io/docs/reference/access-authn-authz/authorization/#using-configuration-file-for-authorization
 	// v1.30 and above enables structured authorization configuration by default
-	return semver.Version(v).GTE(semver.Version{Major: 1, Minor: 30})
+	enabled, _, _ := v.FeatureGateDefault("StructuredAuthorizationConfiguration")
+
+	return enabled
 }
 
 // KubeAPIServerAuthorizationConfigAPIVersion returns the API version of the kube-apiserver authorization config.
@@ -81,3 +91,64 @@ func (v Version) KubeAPIServerAuthorizationConfigAPIVersion() string {
 	// see https://v1-29.docs.kubernetes.io/docs/reference/access-authn-authz/authorization/#configuring-the-api-server-using-an-authorization-config-file
 	return "apiserver.config.k8s.io/v1alpha1"
 }
+
+// KubeProxyDefaultMode returns the proxy mode kube-proxy uses on Linux if the mode is not set explicitly.
+func (v Version) KubeProxyDefaultMode() string {
+	// https://kubernetes.io/docs/reference/networking/virtual-ips/#proxy-modes
+	// iptables is still the default, nftables has to be selected explicitly
+	return KubeProxyModeIPTables
+}
+
+// SupportsKubeProxyNFTables returns true if kube-proxy supports nftables mode without enabling the feature gate.
+//
+// With v1.29 and v1.30 nftables mode requires `--feature-gates=NFTablesProxyMode=true`.
+func (v Version) SupportsKubeProxyNFTables() bool {
+	// https://kubernetes.io/blog/2024/08/13/kubernetes-v1-31-release/#nftables-backend-for-kube-proxy
+	return v.Supports(FeatureNFTablesProxyMode)
+}
+
+// KubeAPIServerSupportsAuthenticationConfigFile returns true if kube-apiserver supports authentication config file.
+func (v Version) KubeAPIServerSupportsAuthenticationConfigFile() bool {
+	// https://v1-29.docs.kubernetes.io/docs/reference/access-authn-authz/authentication/#using-authentication-configuration
+	// v1.29 and above supports authentication config file
+	return semver.Version(v).GTE(semver.Version{Major: 1, Minor: 29})
+}
+
+// FeatureFlagStructuredAuthenticationConfigurationEnabledByDefault returns true if structured authentication configuration is enabled by default.
+func (v Version) FeatureFlagStructuredAuthenticationConfigurationEnabledByDefault() bool {
+	// https://v1-30.docs.kubernetes.io/docs/reference/access-authn-authz/authentication/#using-authentication-configuration
+	// v1.30 and above enables structured authentication configuration by default
+	enabled, _, _ := v.FeatureGateDefault("StructuredAuthenticationConfiguration")
+
+	return enabled
+}
+
+// KubeAPIServerAuthenticationConfigAPIVersion returns the API version of the kube-apiserver authentication config.
+//
+// Empty string is returned for the versions which don't support authentication config file (before v1.29).
+func (v Version) KubeAPIServerAuthenticationConfigAPIVersion() string {
+	// https://v1-34.docs.kubernetes.io/docs/reference/access-authn-authz/authentication/#using-authentication-configuration
+	// v1.34 and above supports v1
+	if semver.Version(v).GTE(semver.Version{Major: 1, Minor: 34}) {
+		return "apiserver.config.k8s.io/v1"
+	}
+
+	// v1.30 and above supports v1beta1
+	if semver.Version(v).GTE(semver.Version{Major: 1, Minor: 30}) {
+		return "apiserver.config.k8s.io/v1beta1"
+	}
+
+	// see https://v1-29.docs.kubernetes.io/docs/reference/access-authn-authz/authentication/#using-authentication-configuration
+	if v.KubeAPIServerSupportsAuthenticationConfigFile() {
+		return "apiserver.config.k8s.io/v1alpha1"
+	}
+
+	return ""
+}
+
+// SupportsNativeSidecars returns true if init containers with `restartPolicy: Always` (native sidecar containers) are supported by default.
+func (v Version) SupportsNativeSidecars() bool {
+	// https://kubernetes.io/blog/2023/12/13/kubernetes-v1-29-release/#sidecar-containers-beta
+	// v1.29 and above enables sidecar containers by default
+	return v.Supports(FeatureSidecarContainers)
+}
@@ -16,93 +16,156 @@ func TestFeatures(t *testing.T) {
 	for _, test := range []struct { //nolint:govet
 		versions []compatibility.Version
 
-		expectedSupportsKubeletConfigContainerRuntimeEndpoint                   bool
-		expectedFeatureFlagSeccompDefaultEnabledByDefault                       bool
-		expectedKubeAPIServerSupportsAuthorizationConfigFile                    bool
-		expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault bool
-		expectedKubeSchedulerLivenessEndpoint                                   string
-		expectedKubeSchedulerReadinessEndpoint                                  string
-		expectedKubeSchedulerStartupEndpoint                                    string
-		expectedKubeAPIServerAuthorizationConfigAPIVersion                      string
+		expectedSupportsKubeletConfigContainerRuntimeEndpoint                    bool
+		expectedFeatureFlagSeccompDefaultEnabledByDefault                        bool
+		expectedKubeAPIServerSupportsAuthorizationConfigFile                     bool
+		expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault  bool
+		expectedKubeSchedulerLivenessEndpoint                                    string
+		expectedKubeSchedulerReadinessEndpoint                                   string
+		expectedKubeSchedulerStartupEndpoint                                     string
+		expectedKubeAPIServerAuthorizationConfigAPIVersion                       string
+		expectedKubeProxyDefaultMode                                             string
+		expectedSupportsKubeProxyNFTables                                        bool
+		expectedKubeAPIServerSupportsAuthenticationConfigFile                    bool
+		expectedFeatureFlagStructuredAuthenticationConfigurationEnabledByDefault bool
+		expectedKubeAPIServerAuthenticationConfigAPIVersion                      string
+		expectedSupportsNativeSidecars                                           bool
 	}{
 		{
 			versions: []compatibility.Version{
 				{Major: 1, Minor: 24},
 			},
 
-			expectedSupportsKubeletConfigContainerRuntimeEndpoint:                   false,
-			expectedFeatureFlagSeccompDefaultEnabledByDefault:                       false,
-			expectedKubeAPIServerSupportsAuthorizationConfigFile:                    false,
-			expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault: false,
-			expectedKubeSchedulerLivenessEndpoint:                                   "/healthz",
-			expectedKubeSchedulerReadinessEndpoint:                                  "/healthz",
-			expectedKubeSchedulerStartupEndpoint:                                    "/healthz",
+			expectedSupportsKubeletConfigContainerRuntimeEndpoint:                    false,
+			expectedFeatureFlagSeccompDefaultEnabledByDefault:                        false,
+			expectedKubeAPIServerSupportsAuthorizationConfigFile:                     false,
+			expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault:  false,
+			expectedKubeSchedulerLivenessEndpoint:                                    "/healthz",
+			expectedKubeSchedulerReadinessEndpoint:                                   "/healthz",
+			expectedKubeSchedulerStartupEndpoint:                                     "/healthz",
+			expectedKubeProxyDefaultMode:                                             "iptables",
+			expectedSupportsKubeProxyNFTables:                                        false,
+			expectedKubeAPIServerSupportsAuthenticationConfigFile:                    false,
+			expectedFeatureFlagStructuredAuthenticationConfigurationEnabledByDefault: false,
+			expectedKubeAPIServerAuthenticationConfigAPIVersion:                      "",
+			expectedSupportsNativeSidecars:                                           false,
 		},
 		{
 			versions: []compatibility.Version{
 				{Major: 1, Minor: 25},
 				{Major: 1, Minor: 26},
 			},
-			expectedSupportsKubeletConfigContainerRuntimeEndpoint:                   false,
-			expectedFeatureFlagSeccompDefaultEnabledByDefault:                       true,
-			expectedKubeAPIServerSupportsAuthorizationConfigFile:                    false,
-			expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault: false,
-			expectedKubeSchedulerLivenessEndpoint:                                   "/healthz",
-			expectedKubeSchedulerReadinessEndpoint:                                  "/healthz",
-			expectedKubeSchedulerStartupEndpoint:                                    "/healthz",
+			expectedSupportsKubeletConfigContainerRuntimeEndpoint:                    false,
+			expectedFeatureFlagSeccompDefaultEnabledByDefault:                        true,
+			expectedKubeAPIServerSupportsAuthorizationConfigFile:                     false,
+			expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault:  false,
+			expectedKubeSchedulerLivenessEndpoint:                                    "/healthz",
+			expectedKubeSchedulerReadinessEndpoint:                                   "/healthz",
+			expectedKubeSchedulerStartupEndpoint:                                     "/healthz",
+			expectedKubeProxyDefaultMode:                                             "iptables",
+			expectedSupportsKubeProxyNFTables:                                        false,
+			expectedKubeAPIServerSupportsAuthenticationConfigFile:                    false,
+			expectedFeatureFlagStructuredAuthenticationConfigurationEnabledByDefault: false,
+			expectedKubeAPIServerAuthenticationConfigAPIVersion:                      "",
+			expectedSupportsNativeSidecars:                                           false,
 		},
 		{
 			versions: []compatibility.Version{
 				{Major: 1, Minor: 27},
 				{Major: 1, Minor: 28},
 			},
-			expectedSupportsKubeletConfigContainerRuntimeEndpoint:                   true,
-			expectedFeatureFlagSeccompDefaultEnabledByDefault:                       true,
-			expectedKubeAPIServerSupportsAuthorizationConfigFile:                    false,
-			expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault: false,
-			expectedKubeSchedulerLivenessEndpoint:                                   "/healthz",
-			expectedKubeSchedulerReadinessEndpoint:                                  "/healthz",
-			expectedKubeSchedulerStartupEndpoint:                                    "/healthz",
+			expectedSupportsKubeletConfigContainerRuntimeEndpoint:                    true,
+			expectedFeatureFlagSeccompDefaultEnabledByDefault:                        true,
+			expectedKubeAPIServerSupportsAuthorizationConfigFile:                     false,
+			expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault:  false,
+			expectedKubeSchedulerLivenessEndpoint:                                    "/healthz",
+			expectedKubeSchedulerReadinessEndpoint:                                   "/healthz",
+			expectedKubeSchedulerStartupEndpoint:                                     "/healthz",
+			expectedKubeProxyDefaultMode:                                             "iptables",
+			expectedSupportsKubeProxyNFTables:                                        false,
+			expectedKubeAPIServerSupportsAuthenticationConfigFile:                    false,
+			expectedFeatureFlagStructuredAuthenticationConfigurationEnabledByDefault: false,
+			expectedKubeAPIServerAuthenticationConfigAPIVersion:                      "",
+			expectedSupportsNativeSidecars:                                           false,
 		},
 		{
 			versions: []compatibility.Version{
 				{Major: 1, Minor: 29},
 			},
-			expectedSupportsKubeletConfigContainerRuntimeEndpoint:                   true,
-			expectedFeatureFlagSeccompDefaultEnabledByDefault:                       true,
-			expectedKubeAPIServerSupportsAuthorizationConfigFile:                    true,
-			expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault: false,
-			expectedKubeSchedulerLivenessEndpoint:                                   "/healthz",
-			expectedKubeSchedulerReadinessEndpoint:                                  "/healthz",
-			expectedKubeSchedulerStartupEndpoint:                                    "/healthz",
-			expectedKubeAPIServerAuthorizationConfigAPIVersion:                      "apiserver.config.k8s.io/v1alpha1",
+			expectedSupportsKubeletConfigContainerRuntimeEndpoint:                    true,
+			expectedFeatureFlagSeccompDefaultEnabledByDefault:                        true,
+			expectedKubeAPIServerSupportsAuthorizationConfigFile:                     true,
+			expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault:  false,
+			expectedKubeSchedulerLivenessEndpoint:                                    "/healthz",
+			expectedKubeSchedulerReadinessEndpoint:                                   "/healthz",
+			expectedKubeSchedulerStartupEndpoint:                                     "/healthz",
+			expectedKubeAPIServerAuthorizationConfigAPIVersion:                       "apiserver.config.k8s.io/v1alpha1",
+			expectedKubeProxyDefaultMode:                                             "iptables",
+			expectedSupportsKubeProxyNFTables:                                        false,
+			expectedKubeAPIServerSupportsAuthenticationConfigFile:                    true,
+			expectedFeatureFlagStructuredAuthenticationConfigurationEnabledByDefault: false,
+			expectedKubeAPIServerAuthenticationConfigAPIVersion:                      "apiserver.config.k8s.io/v1alpha1",
+			expectedSupportsNativeSidecars:                                           true,
 		},
 		{
 			versions: []compatibility.Version{
 				{Major: 1, Minor: 30},
 			},
-			expectedSupportsKubeletConfigContainerRuntimeEndpoint:                   true,
-			expectedFeatureFlagSeccompDefaultEnabledByDefault:                       true,
-			expectedKubeAPIServerSupportsAuthorizationConfigFile:                    true,
-			expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault: true,
-			expectedKubeSchedulerLivenessEndpoint:                                   "/healthz",
-			expectedKubeSchedulerReadinessEndpoint:                                  "/healthz",
-			expectedKubeSchedulerStartupEndpoint:                                    "/healthz",
-			expectedKubeAPIServerAuthorizationConfigAPIVersion:                      "apiserver.config.k8s.io/v1beta1",
+			expectedSupportsKubeletConfigContainerRuntimeEndpoint:                    true,
+			expectedFeatureFlagSeccompDefaultEnabledByDefault:                        true,
+			expectedKubeAPIServerSupportsAuthorizationConfigFile:                     true,
+			expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault:  true,
+			expectedKubeSchedulerLivenessEndpoint:                                    "/healthz",
+			expectedKubeSchedulerReadinessEndpoint:                                   "/healthz",
+			expectedKubeSchedulerStartupEndpoint:                                     "/healthz",
+			expectedKubeAPIServerAuthorizationConfigAPIVersion:                       "apiserver.config.k8s.io/v1beta1",
+			expectedKubeProxyDefaultMode:                                             "iptables",
+			expectedSupportsKubeProxyNFTables:                                        false,
+			expectedKubeAPIServerSupportsAuthenticationConfigFile:                    true,
+			expectedFeatureFlagStructuredAuthenticationConfigurationEnabledByDefault: true,
+			expectedKubeAPIServerAuthenticationConfigAPIVersion:                      "apiserver.config.k8s.io/v1beta1",
+			expectedSupportsNativeSidecars:                                           true,
 		},
 		{
 			versions: []compatibility.Version{
 				{Major: 1, Minor: 31},
+				{Major: 1, Minor: 33},
+			},
+			expectedSupportsKubeletConfigContainerRuntimeEndpoint:                    true,
+			expectedFeatureFlagSeccompDefaultEnabledByDefault:                        true,
+			expectedKubeAPIServerSupportsAuthorizationConfigFile:                     true,
+			expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault:  true,
+			expectedKubeSchedulerLivenessEndpoint:                                    "/livez",
+			expectedKubeSchedulerReadinessEndpoint:                                   "/readyz",
+			expectedKubeSchedulerStartupEndpoint:                                     "/livez",
+			expectedKubeAPIServerAuthorizationConfigAPIVersion:                       "apiserver.config.k8s.io/v1beta1",
+			expectedKubeProxyDefaultMode:                                             "iptables",
+			expectedSupportsKubeProxyNFTables:                                        true,
+			expectedKubeAPIServerSupportsAuthenticationConfigFile:                    true,
+			expectedFeatureFlagStructuredAuthenticationConfigurationEnabledByDefault: true,
+			expectedKubeAPIServerAuthenticationConfigAPIVersion:                      "apiserver.config.k8s.io/v1beta1",
+			expectedSupportsNativeSidecars:                                           true,
+		},
+		{
+			versions: []compatibility.Version{
+				{Major: 1, Minor: 34},
 				{Major: 1, Minor: 99},
+				{Major: 2, Minor: 0},
 			},
-			expectedSupportsKubeletConfigContainerRuntimeEndpoint:                   true,
-			expectedFeatureFlagSeccompDefaultEnabledByDefault:                       true,
-			expectedKubeAPIServerSupportsAuthorizationConfigFile:                    true,
-			expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault: true,
-			expectedKubeSchedulerLivenessEndpoint:                                   "/livez",
-			expectedKubeSchedulerReadinessEndpoint:                                  "/readyz",
-			expectedKubeSchedulerStartupEndpoint:                                    "/livez",
-			expectedKubeAPIServerAuthorizationConfigAPIVersion:                      "apiserver.config.k8s.io/v1beta1",
+			expectedSupportsKubeletConfigContainerRuntimeEndpoint:                    true,
+			expectedFeatureFlagSeccompDefaultEnabledByDefault:                        true,
+			expectedKubeAPIServerSupportsAuthorizationConfigFile:                     true,
+			expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault:  true,
+			expectedKubeSchedulerLivenessEndpoint:                                    "/livez",
+			expectedKubeSchedulerReadinessEndpoint:                                   "/readyz",
+			expectedKubeSchedulerStartupEndpoint:                                     "/livez",
+			expectedKubeAPIServerAuthorizationConfigAPIVersion:                       "apiserver.config.k8s.io/v1beta1",
+			expectedKubeProxyDefaultMode:                                             "iptables",
+			expectedSupportsKubeProxyNFTables:                                        true,
+			expectedKubeAPIServerSupportsAuthenticationConfigFile:                    true,
+			expectedFeatureFlagStructuredAuthenticationConfigurationEnabledByDefault: true,
+			expectedKubeAPIServerAuthenticationConfigAPIVersion:                      "apiserver.config.k8s.io/v1",
+			expectedSupportsNativeSidecars:                                           true,
 		},
 	} {
 		for _, version := range test.versions {
@@ -113,6 +176,12 @@ func TestFeatures(t *testing.T) {
 				assert.Equal(t, test.expectedFeatureFlagStructuredAuthorizationConfigurationEnabledByDefault, version.FeatureFlagStructuredAuthorizationConfigurationEnabledByDefault())
 				assert.Equal(t, test.expectedKubeSchedulerLivenessEndpoint, version.KubeSchedulerHealthLivenessEndpoint())
 				assert.Equal(t, test.expectedKubeSchedulerReadinessEndpoint, version.KubeSchedulerHealthReadinessEndpoint())
+				assert.Equal(t, test.expectedKubeProxyDefaultMode, version.KubeProxyDefaultMode())
+				assert.Equal(t, test.expectedSupportsKubeProxyNFTables, version.SupportsKubeProxyNFTables())
+				assert.Equal(t, test.expectedKubeAPIServerSupportsAuthenticationConfigFile, version.KubeAPIServerSupportsAuthenticationConfigFile())
+				assert.Equal(t, test.expectedFeatureFlagStructuredAuthenticationConfigurationEnabledByDefault, version.FeatureFlagStructuredAuthenticationConfigurationEnabledByDefault())
+				assert.Equal(t, test.expectedKubeAPIServerAuthenticationConfigAPIVersion, version.KubeAPIServerAuthenticationConfigAPIVersion())
+				assert.Equal(t, test.expectedSupportsNativeSidecars, version.SupportsNativeSidecars())
 			})
 		}
 	}
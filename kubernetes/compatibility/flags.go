@@ -0,0 +1,83 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility
+
+// Component names as used by the flag removal data.
+const (
+	ComponentKubeAPIServer         = "kube-apiserver"
+	ComponentKubeControllerManager = "kube-controller-manager"
+	ComponentKubeScheduler         = "kube-scheduler"
+	ComponentKubelet               = "kubelet"
+	ComponentKubeProxy             = "kube-proxy"
+)
+
+// removedFlag is a command line flag removed in the Kubernetes 1.x minor version.
+type removedFlag struct {
+	name    string
+	removed uint64
+}
+
+// removedFlags are the removed command line flags per component.
+//
+// The flags are taken from the "Urgent Upgrade Notes" of the changelogs (https://github.com/kubernetes/kubernetes/tree/master/CHANGELOG).
+var removedFlags = map[string][]removedFlag{
+	ComponentKubeAPIServer: {
+		{name: "service-account-api-audiences", removed: 25},
+		{name: "master-service-namespace", removed: 26},
+		{name: "cloud-provider", removed: 33}, // https://github.com/kubernetes/kubernetes/pull/130162
+		{name: "cloud-config", removed: 33},   // https://github.com/kubernetes/kubernetes/pull/130162
+	},
+	ComponentKubeControllerManager: {
+		{name: "deleting-pods-qps", removed: 25},
+		{name: "deleting-pods-burst", removed: 25},
+		{name: "register-retry-count", removed: 25},
+		{name: "enable-taint-manager", removed: 27},
+		{name: "pod-eviction-timeout", removed: 27},
+		{name: "volume-host-cidr-denylist", removed: 31},
+		{name: "volume-host-allow-local-loopback", removed: 31},          // https://github.com/kubernetes/kubernetes/pull/124017
+		{name: "horizontal-pod-autoscaler-upscale-delay", removed: 31},   // https://github.com/kubernetes/kubernetes/pull/124948
+		{name: "horizontal-pod-autoscaler-downscale-delay", removed: 31}, // https://github.com/kubernetes/kubernetes/pull/124948
+	},
+	ComponentKubelet: {
+		{name: "container-runtime", removed: 27},
+		{name: "master-service-namespace", removed: 27},
+		{name: "keep-terminated-pod-volumes", removed: 31}, // https://github.com/kubernetes/kubernetes/pull/122082
+		{name: "iptables-masquerade-bit", removed: 31},     // https://github.com/kubernetes/kubernetes/pull/122363
+		{name: "iptables-drop-bit", removed: 31},           // https://github.com/kubernetes/kubernetes/pull/122363
+		{name: "cloud-config", removed: 34},                // https://github.com/kubernetes/kubernetes/pull/130161
+		{name: "register-schedulable", removed: 34},        // https://github.com/kubernetes/kubernetes/pull/122384
+	},
+	ComponentKubeProxy: {
+		{name: "proxy-port-range", removed: 31}, // https://github.com/kubernetes/kubernetes/pull/126293
+		{name: "healthz-port", removed: 32},     // https://github.com/kubernetes/kubernetes/pull/126889
+		{name: "metrics-port", removed: 32},     // https://github.com/kubernetes/kubernetes/pull/126889
+	},
+}
+
+// FlagRemoved returns true if the command line flag of the component is removed in the Kubernetes version (or earlier).
+//
+// The flag name is expected without the leading dashes, e.g. "pod-eviction-timeout".
+func (v Version) FlagRemoved(component, flag string) bool {
+	for _, f := range removedFlags[component] {
+		if f.name == flag {
+			return v.Major > 1 || (v.Major == 1 && v.Minor >= f.removed)
+		}
+	}
+
+	return false
+}
+
+// FlagsRemovedIn returns the command line flags of the component removed exactly in the Kubernetes minor version.
+func (v Version) FlagsRemovedIn(component string) []string {
+	var flags []string
+
+	for _, f := range removedFlags[component] {
+		if v.Major == 1 && v.Minor == f.removed {
+			flags = append(flags, f.name)
+		}
+	}
+
+	return flags
+}
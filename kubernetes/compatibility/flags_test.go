@@ -0,0 +1,29 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+)
+
+func TestFlagRemoved(t *testing.T) {
+	v126 := compatibility.Version{Major: 1, Minor: 26}
+	v127 := compatibility.Version{Major: 1, Minor: 27, Patch: 3}
+	v131 := compatibility.Version{Major: 1, Minor: 31}
+
+	assert.False(t, v126.FlagRemoved(compatibility.ComponentKubeControllerManager, "pod-eviction-timeout"))
+	assert.True(t, v127.FlagRemoved(compatibility.ComponentKubeControllerManager, "pod-eviction-timeout"))
+	assert.True(t, v131.FlagRemoved(compatibility.ComponentKubeControllerManager, "pod-eviction-timeout"))
+	assert.False(t, v131.FlagRemoved(compatibility.ComponentKubeAPIServer, "pod-eviction-timeout"))
+	assert.False(t, v131.FlagRemoved(compatibility.ComponentKubelet, "node-ip"))
+
+	assert.Equal(t, []string{"container-runtime", "master-service-namespace"}, v127.FlagsRemovedIn(compatibility.ComponentKubelet))
+	assert.Empty(t, v126.FlagsRemovedIn(compatibility.ComponentKubelet))
+	assert.Equal(t, []string{"proxy-port-range"}, v131.FlagsRemovedIn(compatibility.ComponentKubeProxy))
+}
@@ -0,0 +1,46 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility
+
+// pauseVersions are the pause image tags expected by kubeadm per Kubernetes 1.x minor version.
+//
+// See PauseVersion in cmd/kubeadm/app/constants/constants.go of the Kubernetes release branches.
+var pauseVersions = []struct {
+	minor   uint64
+	version string
+}{
+	{minor: 22, version: "3.5"},
+	{minor: 23, version: "3.6"},
+	{minor: 24, version: "3.7"},
+	{minor: 25, version: "3.8"},
+	{minor: 26, version: "3.9"},
+	{minor: 31, version: "3.10"},
+	{minor: 34, version: "3.10.1"},
+}
+
+// PauseImageVersion returns the sandbox (pause) image tag expected for the Kubernetes version.
+//
+// Versions older than the oldest known version get the oldest known tag,
+// versions newer than the newest known version get the newest known tag.
+func (v Version) PauseImageVersion() string {
+	switch {
+	case v.Major < 1:
+		return pauseVersions[0].version
+	case v.Major > 1:
+		return pauseVersions[len(pauseVersions)-1].version
+	}
+
+	version := pauseVersions[0].version
+
+	for _, entry := range pauseVersions {
+		if entry.minor > v.Minor {
+			break
+		}
+
+		version = entry.version
+	}
+
+	return version
+}
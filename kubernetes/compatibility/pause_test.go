@@ -0,0 +1,36 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+)
+
+func TestPauseImageVersion(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		version compatibility.Version
+
+		expected string
+	}{
+		{version: compatibility.Version{Major: 1, Minor: 20}, expected: "3.5"},
+		{version: compatibility.Version{Major: 1, Minor: 24, Patch: 1}, expected: "3.7"},
+		{version: compatibility.Version{Major: 1, Minor: 28}, expected: "3.9"},
+		{version: compatibility.Version{Major: 1, Minor: 31}, expected: "3.10"},
+		{version: compatibility.Version{Major: 1, Minor: 34}, expected: "3.10.1"},
+		{version: compatibility.Version{Major: 1, Minor: 99}, expected: "3.10.1"},
+	} {
+		t.Run(test.version.String(), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, test.version.PauseImageVersion())
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility
+
+import "fmt"
+
+// Skew policy limits, see https://kubernetes.io/releases/version-skew-policy/.
+const (
+	// MaxControlPlaneSkew is the maximum number of minor versions kube-controller-manager, kube-scheduler
+	// and other kube-apiserver instances may lag behind kube-apiserver.
+	MaxControlPlaneSkew = 1
+	// MaxKubectlSkew is the maximum number of minor versions kubectl may differ from kube-apiserver (in either direction).
+	MaxKubectlSkew = 1
+)
+
+// MaxKubeletSkew returns the maximum number of minor versions kubelet (and kube-proxy) may lag behind kube-apiserver of the version.
+func (v Version) MaxKubeletSkew() uint64 {
+	// https://kubernetes.io/blog/2023/08/15/kubernetes-v1-28-release/#changes-to-supported-skew-between-control-plane-and-node-versions
+	if v.Major == 1 && v.Minor < 28 {
+		return 2
+	}
+
+	return 3
+}
+
+// SkewError is returned when the component version doesn't satisfy the version skew policy.
+type SkewError struct {
+	Component string
+	Version   Version
+	APIServer Version
+	// MaxSkew is the maximum allowed number of minor versions between the component and kube-apiserver
+	MaxSkew uint64
+	// Newer is true if the component is newer than allowed, otherwise it is older than allowed
+	Newer bool
+}
+
+// Error implements error.
+func (e *SkewError) Error() string {
+	if e.Newer && e.MaxSkew == 0 {
+		return fmt.Sprintf("%s %s is newer than kube-apiserver %s", e.Component, e.Version, e.APIServer)
+	}
+
+	direction := "older"
+	if e.Newer {
+		direction = "newer"
+	}
+
+	return fmt.Sprintf("%s %s is more than %d minor version(s) %s than kube-apiserver %s", e.Component, e.Version, e.MaxSkew, direction, e.APIServer)
+}
+
+// ValidateAPIServerSkew checks that another kube-apiserver instance (e.g. during an upgrade of highly-available control plane)
+// is not newer than kube-apiserver and is at most one minor version older.
+func ValidateAPIServerSkew(apiServer, other Version) error {
+	return validateSkew("kube-apiserver", apiServer, other, MaxControlPlaneSkew, 0)
+}
+
+// ValidateKubeletSkew checks that kubelet is not newer than kube-apiserver and is at most three (two before 1.28) minor versions older.
+func ValidateKubeletSkew(apiServer, kubelet Version) error {
+	return validateSkew("kubelet", apiServer, kubelet, apiServer.MaxKubeletSkew(), 0)
+}
+
+// ValidateKubeProxySkew checks that kube-proxy is not newer than kube-apiserver and is at most three (two before 1.28) minor versions older.
+func ValidateKubeProxySkew(apiServer, kubeProxy Version) error {
+	return validateSkew("kube-proxy", apiServer, kubeProxy, apiServer.MaxKubeletSkew(), 0)
+}
+
+// ValidateControllerManagerSkew checks that kube-controller-manager is not newer than kube-apiserver and is at most one minor version older.
+func ValidateControllerManagerSkew(apiServer, controllerManager Version) error {
+	return validateSkew("kube-controller-manager", apiServer, controllerManager, MaxControlPlaneSkew, 0)
+}
+
+// ValidateSchedulerSkew checks that kube-scheduler is not newer than kube-apiserver and is at most one minor version older.
+func ValidateSchedulerSkew(apiServer, scheduler Version) error {
+	return validateSkew("kube-scheduler", apiServer, scheduler, MaxControlPlaneSkew, 0)
+}
+
+// ValidateKubectlSkew checks that kubectl is within one minor version (older or newer) of kube-apiserver.
+func ValidateKubectlSkew(apiServer, kubectl Version) error {
+	return validateSkew("kubectl", apiServer, kubectl, MaxKubectlSkew, MaxKubectlSkew)
+}
+
+// validateSkew checks that the component is at most maxOlder minor versions older and at most maxNewer minor versions newer than kube-apiserver.
+//
+// Patch versions are ignored, the skew across major versions is never allowed.
+func validateSkew(component string, apiServer, version Version, maxOlder, maxNewer uint64) error {
+	skewErr := &SkewError{
+		Component: component,
+		Version:   version,
+		APIServer: apiServer,
+	}
+
+	switch {
+	case version.Major > apiServer.Major || (version.Major == apiServer.Major && version.Minor > apiServer.Minor+maxNewer):
+		skewErr.MaxSkew, skewErr.Newer = maxNewer, true
+
+		return skewErr
+	case version.Major < apiServer.Major || (version.Major == apiServer.Major && version.Minor+maxOlder < apiServer.Minor):
+		skewErr.MaxSkew = maxOlder
+
+		return skewErr
+	}
+
+	return nil
+}
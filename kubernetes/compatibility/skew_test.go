@@ -0,0 +1,171 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility_test
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+)
+
+func TestValidateSkew(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name      string
+		validate  func(apiServer, component compatibility.Version) error
+		apiServer string
+		component string
+
+		expectedError string
+	}{
+		{
+			name:      "kubelet same version",
+			validate:  compatibility.ValidateKubeletSkew,
+			apiServer: "1.31.2",
+			component: "1.31.0",
+		},
+		{
+			name:      "kubelet N-3",
+			validate:  compatibility.ValidateKubeletSkew,
+			apiServer: "1.31.0",
+			component: "1.28.5",
+		},
+		{
+			name:      "kubelet N-4",
+			validate:  compatibility.ValidateKubeletSkew,
+			apiServer: "1.31.0",
+			component: "1.27.5",
+
+			expectedError: "kubelet 1.27.5 is more than 3 minor version(s) older than kube-apiserver 1.31.0",
+		},
+		{
+			name:      "kubelet N-3 before 1.28",
+			validate:  compatibility.ValidateKubeletSkew,
+			apiServer: "1.27.0",
+			component: "1.24.0",
+
+			expectedError: "kubelet 1.24.0 is more than 2 minor version(s) older than kube-apiserver 1.27.0",
+		},
+		{
+			name:      "kubelet newer",
+			validate:  compatibility.ValidateKubeletSkew,
+			apiServer: "1.31.0",
+			component: "1.32.0",
+
+			expectedError: "kubelet 1.32.0 is newer than kube-apiserver 1.31.0",
+		},
+		{
+			name:      "kubelet newer patch",
+			validate:  compatibility.ValidateKubeletSkew,
+			apiServer: "1.31.0",
+			component: "1.31.4",
+		},
+		{
+			name:      "kube-proxy N-3",
+			validate:  compatibility.ValidateKubeProxySkew,
+			apiServer: "1.32.0",
+			component: "1.29.0",
+		},
+		{
+			name:      "controller manager N-1",
+			validate:  compatibility.ValidateControllerManagerSkew,
+			apiServer: "1.32.0",
+			component: "1.31.0",
+		},
+		{
+			name:      "controller manager N-2",
+			validate:  compatibility.ValidateControllerManagerSkew,
+			apiServer: "1.32.0",
+			component: "1.30.0",
+
+			expectedError: "kube-controller-manager 1.30.0 is more than 1 minor version(s) older than kube-apiserver 1.32.0",
+		},
+		{
+			name:      "scheduler newer",
+			validate:  compatibility.ValidateSchedulerSkew,
+			apiServer: "1.32.0",
+			component: "1.33.0",
+
+			expectedError: "kube-scheduler 1.33.0 is newer than kube-apiserver 1.32.0",
+		},
+		{
+			name:      "apiserver N-1",
+			validate:  compatibility.ValidateAPIServerSkew,
+			apiServer: "1.32.0",
+			component: "1.31.4",
+		},
+		{
+			name:      "apiserver N-2",
+			validate:  compatibility.ValidateAPIServerSkew,
+			apiServer: "1.32.0",
+			component: "1.30.4",
+
+			expectedError: "kube-apiserver 1.30.4 is more than 1 minor version(s) older than kube-apiserver 1.32.0",
+		},
+		{
+			name:      "kubectl N+1",
+			validate:  compatibility.ValidateKubectlSkew,
+			apiServer: "1.32.0",
+			component: "1.33.1",
+		},
+		{
+			name:      "kubectl N-1",
+			validate:  compatibility.ValidateKubectlSkew,
+			apiServer: "1.32.0",
+			component: "1.31.1",
+		},
+		{
+			name:      "kubectl N+2",
+			validate:  compatibility.ValidateKubectlSkew,
+			apiServer: "1.32.0",
+			component: "1.34.0",
+
+			expectedError: "kubectl 1.34.0 is more than 1 minor version(s) newer than kube-apiserver 1.32.0",
+		},
+		{
+			name:      "kubectl N-2",
+			validate:  compatibility.ValidateKubectlSkew,
+			apiServer: "1.32.0",
+			component: "1.30.0",
+
+			expectedError: "kubectl 1.30.0 is more than 1 minor version(s) older than kube-apiserver 1.32.0",
+		},
+		{
+			name:      "major version",
+			validate:  compatibility.ValidateKubeletSkew,
+			apiServer: "2.0.0",
+			component: "1.99.0",
+
+			expectedError: "kubelet 1.99.0 is more than 3 minor version(s) older than kube-apiserver 2.0.0",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.validate(
+				compatibility.Version(semver.MustParse(test.apiServer)),
+				compatibility.Version(semver.MustParse(test.component)),
+			)
+
+			if test.expectedError == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.EqualError(t, err, test.expectedError)
+
+			var skewErr *compatibility.SkewError
+
+			require.ErrorAs(t, err, &skewErr)
+			assert.Equal(t, test.component, skewErr.Version.String())
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility
+
+// Feature is a major Kubernetes feature which can be queried with Version.Supports.
+type Feature string
+
+// Feature catalog.
+const (
+	FeatureDynamicResourceAllocation             Feature = "DynamicResourceAllocation"
+	FeatureInPlacePodVerticalScaling             Feature = "InPlacePodVerticalScaling"
+	FeatureMutatingAdmissionPolicy               Feature = "MutatingAdmissionPolicy"
+	FeatureNFTablesProxyMode                     Feature = "NFTablesProxyMode"
+	FeatureSidecarContainers                     Feature = "SidecarContainers"
+	FeatureStructuredAuthenticationConfiguration Feature = "StructuredAuthenticationConfiguration"
+	FeatureStructuredAuthorizationConfiguration  Feature = "StructuredAuthorizationConfiguration"
+	FeatureValidatingAdmissionPolicy             Feature = "ValidatingAdmissionPolicy"
+	FeatureKubeletConfigContainerRuntimeEndpoint Feature = "KubeletConfigContainerRuntimeEndpoint"
+	FeatureKubeSchedulerLivezReadyz              Feature = "KubeSchedulerLivezReadyz"
+)
+
+// featureSpec describes when the feature is available.
+type featureSpec struct {
+	// featureGate is the feature gate controlling the feature, the feature is available when the gate is enabled by default
+	featureGate string
+	// since is the Kubernetes 1.x minor version the feature is available from, used if there is no feature gate
+	since uint64
+}
+
+// features is the feature catalog.
+var features = map[Feature]featureSpec{
+	FeatureDynamicResourceAllocation:             {featureGate: "DynamicResourceAllocation"},
+	FeatureInPlacePodVerticalScaling:             {featureGate: "InPlacePodVerticalScaling"},
+	FeatureMutatingAdmissionPolicy:               {featureGate: "MutatingAdmissionPolicy"},
+	FeatureNFTablesProxyMode:                     {featureGate: "NFTablesProxyMode"},
+	FeatureSidecarContainers:                     {featureGate: "SidecarContainers"},
+	FeatureStructuredAuthenticationConfiguration: {featureGate: "StructuredAuthenticationConfiguration"},
+	FeatureStructuredAuthorizationConfiguration:  {featureGate: "StructuredAuthorizationConfiguration"},
+	FeatureValidatingAdmissionPolicy:             {featureGate: "ValidatingAdmissionPolicy"},
+	// see https://github.com/kubernetes/kubernetes/pull/112136
+	FeatureKubeletConfigContainerRuntimeEndpoint: {since: 27},
+	// see https://github.com/kubernetes/kubernetes/pull/118148
+	FeatureKubeSchedulerLivezReadyz: {since: 31},
+}
+
+// Supports returns true if the feature is available by default in the Kubernetes version.
+//
+// The features controlled by a feature gate are available if the gate is enabled by default
+// (or the gate was removed after the feature went GA). Unknown features are reported as not supported.
+func (v Version) Supports(feature Feature) bool {
+	spec, ok := features[feature]
+	if !ok {
+		return false
+	}
+
+	if spec.featureGate != "" {
+		enabled, _, _ := v.FeatureGateDefault(spec.featureGate)
+
+		return enabled
+	}
+
+	return v.Major > 1 || (v.Major == 1 && v.Minor >= spec.since)
+}
@@ -0,0 +1,60 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+)
+
+func TestSupports(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		feature compatibility.Feature
+
+		unsupported compatibility.Version
+		supported   compatibility.Version
+	}{
+		{
+			feature:     compatibility.FeatureDynamicResourceAllocation,
+			unsupported: compatibility.Version{Major: 1, Minor: 33},
+			supported:   compatibility.Version{Major: 1, Minor: 34},
+		},
+		{
+			feature:     compatibility.FeatureInPlacePodVerticalScaling,
+			unsupported: compatibility.Version{Major: 1, Minor: 32},
+			supported:   compatibility.Version{Major: 1, Minor: 33},
+		},
+		{
+			feature:     compatibility.FeatureSidecarContainers,
+			unsupported: compatibility.Version{Major: 1, Minor: 28},
+			supported:   compatibility.Version{Major: 1, Minor: 29},
+		},
+		{
+			feature:     compatibility.FeatureValidatingAdmissionPolicy,
+			unsupported: compatibility.Version{Major: 1, Minor: 29},
+			supported:   compatibility.Version{Major: 1, Minor: 32},
+		},
+		{
+			feature:     compatibility.FeatureKubeSchedulerLivezReadyz,
+			unsupported: compatibility.Version{Major: 1, Minor: 30, Patch: 5},
+			supported:   compatibility.Version{Major: 1, Minor: 31},
+		},
+	} {
+		t.Run(string(test.feature), func(t *testing.T) {
+			t.Parallel()
+
+			assert.False(t, test.unsupported.Supports(test.feature))
+			assert.True(t, test.supported.Supports(test.feature))
+		})
+	}
+
+	assert.False(t, compatibility.Version{Major: 1, Minor: 34}.Supports(compatibility.FeatureMutatingAdmissionPolicy))
+	assert.False(t, compatibility.Version{Major: 1, Minor: 34}.Supports(compatibility.Feature("Unknown")))
+}
@@ -0,0 +1,141 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility
+
+import "crypto/tls"
+
+// goMinorVersions are the Go 1.x minor versions Kubernetes 1.x minor versions are built with.
+//
+// See .go-version in the Kubernetes release branches.
+var goMinorVersions = []struct {
+	minor   uint64
+	goMinor uint64
+}{
+	{minor: 24, goMinor: 18},
+	{minor: 25, goMinor: 19},
+	{minor: 27, goMinor: 20},
+	{minor: 29, goMinor: 21},
+	{minor: 30, goMinor: 22},
+	{minor: 32, goMinor: 23},
+	{minor: 33, goMinor: 24},
+}
+
+// TLSDefaults describes the TLS settings a component uses if --tls-min-version and --tls-cipher-suites are not set.
+//
+// The default cipher suites are the Go crypto/tls defaults, so they depend on the Go version Kubernetes is built with.
+type TLSDefaults struct {
+	// MinVersion is the minimum TLS version accepted by the server, e.g. tls.VersionTLS12.
+	MinVersion uint16
+	// RSAKeyExchange is true if the cipher suites without ECDHE (TLS_RSA_*) are enabled by default.
+	RSAKeyExchange bool
+	// TripleDES is true if the 3DES cipher suites are enabled by default.
+	TripleDES bool
+}
+
+// TLSDefaults returns the default TLS settings of the component.
+//
+// kube-apiserver, kube-controller-manager and kube-scheduler use the k8s.io/apiserver secure serving,
+// which always sets the minimum version to TLS 1.2.
+// The kubelet (and other components) use the Go crypto/tls default minimum version.
+func (v Version) TLSDefaults(component string) TLSDefaults {
+	goMinor := goMinorVersions[0].goMinor
+
+	for _, entry := range goMinorVersions {
+		if v.Major == 1 && entry.minor > v.Minor {
+			break
+		}
+
+		goMinor = entry.goMinor
+	}
+
+	defaults := TLSDefaults{
+		MinVersion:     tls.VersionTLS12,
+		RSAKeyExchange: goMinor < 22, // https://go.dev/doc/go1.22#crypto/tls
+		TripleDES:      goMinor < 23, // https://go.dev/doc/go1.23#crypto/tls
+	}
+
+	// Go 1.22 raised the server minimum version to TLS 1.2
+	if goMinor < 22 && !secureServingComponents[component] {
+		defaults.MinVersion = tls.VersionTLS10
+	}
+
+	return defaults
+}
+
+// secureServingComponents are the components serving with the k8s.io/apiserver secure serving.
+//
+// See pkg/server/secure_serving.go in k8s.io/apiserver.
+var secureServingComponents = map[string]bool{
+	ComponentKubeAPIServer:         true,
+	ComponentKubeControllerManager: true,
+	ComponentKubeScheduler:         true,
+}
+
+// MinVersionFlag returns the minimum TLS version in the --tls-min-version flag format, e.g. "VersionTLS12".
+func (d TLSDefaults) MinVersionFlag() string {
+	return tlsVersionFlags[d.MinVersion]
+}
+
+// tlsVersionFlags are the TLS versions in the --tls-min-version flag format.
+var tlsVersionFlags = map[uint16]string{
+	tls.VersionTLS10: "VersionTLS10",
+	tls.VersionTLS11: "VersionTLS11",
+	tls.VersionTLS12: "VersionTLS12",
+	tls.VersionTLS13: "VersionTLS13",
+}
+
+// CipherSuiteEnabledByDefault returns true if the cipher suite (IANA name, as used in --tls-cipher-suites) is enabled by default.
+//
+// Unknown cipher suites are reported as not enabled.
+func (d TLSDefaults) CipherSuiteEnabledByDefault(name string) bool {
+	switch cipherSuiteClasses[name] {
+	case cipherSuiteDefault:
+		return true
+	case cipherSuiteRSAKeyExchange:
+		return d.RSAKeyExchange
+	case cipherSuiteTripleDES:
+		return d.TripleDES
+	case cipherSuiteRSAKeyExchangeTripleDES:
+		return d.RSAKeyExchange && d.TripleDES
+	default:
+		return false
+	}
+}
+
+type cipherSuiteClass int
+
+const (
+	// cipherSuiteUnknown are unknown cipher suites, or cipher suites which are never enabled by default (RC4, CBC with SHA-256)
+	cipherSuiteUnknown cipherSuiteClass = iota
+	cipherSuiteDefault
+	cipherSuiteRSAKeyExchange
+	cipherSuiteTripleDES
+	cipherSuiteRSAKeyExchangeTripleDES
+)
+
+// cipherSuiteClasses are the cipher suites enabled by default in the Go versions Kubernetes is built with.
+//
+// The runtime crypto/tls lists are not used, as they depend on the Go version this package is built with.
+var cipherSuiteClasses = map[string]cipherSuiteClass{
+	"TLS_AES_128_GCM_SHA256":                        cipherSuiteDefault,
+	"TLS_AES_256_GCM_SHA384":                        cipherSuiteDefault,
+	"TLS_CHACHA20_POLY1305_SHA256":                  cipherSuiteDefault,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA":          cipherSuiteDefault,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA":          cipherSuiteDefault,
+	"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA":            cipherSuiteDefault,
+	"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA":            cipherSuiteDefault,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256":       cipherSuiteDefault,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384":       cipherSuiteDefault,
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256":         cipherSuiteDefault,
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384":         cipherSuiteDefault,
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256":   cipherSuiteDefault,
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256": cipherSuiteDefault,
+	"TLS_RSA_WITH_AES_128_CBC_SHA":                  cipherSuiteRSAKeyExchange,
+	"TLS_RSA_WITH_AES_256_CBC_SHA":                  cipherSuiteRSAKeyExchange,
+	"TLS_RSA_WITH_AES_128_GCM_SHA256":               cipherSuiteRSAKeyExchange,
+	"TLS_RSA_WITH_AES_256_GCM_SHA384":               cipherSuiteRSAKeyExchange,
+	"TLS_RSA_WITH_3DES_EDE_CBC_SHA":                 cipherSuiteRSAKeyExchangeTripleDES,
+	"TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA":           cipherSuiteTripleDES,
+}
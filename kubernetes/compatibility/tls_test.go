@@ -0,0 +1,48 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compatibility_test
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+)
+
+func TestTLSDefaults(t *testing.T) {
+	v129 := compatibility.Version{Major: 1, Minor: 29, Patch: 4}.TLSDefaults(compatibility.ComponentKubelet)
+
+	assert.Equal(t, compatibility.TLSDefaults{MinVersion: tls.VersionTLS10, RSAKeyExchange: true, TripleDES: true}, v129)
+	assert.Equal(t, "VersionTLS10", v129.MinVersionFlag())
+	assert.True(t, v129.CipherSuiteEnabledByDefault("TLS_RSA_WITH_AES_128_GCM_SHA256"))
+	assert.True(t, v129.CipherSuiteEnabledByDefault("TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA"))
+
+	apiServer129 := compatibility.Version{Major: 1, Minor: 29, Patch: 4}.TLSDefaults(compatibility.ComponentKubeAPIServer)
+
+	assert.Equal(t, compatibility.TLSDefaults{MinVersion: tls.VersionTLS12, RSAKeyExchange: true, TripleDES: true}, apiServer129)
+	assert.Equal(t, "VersionTLS12", apiServer129.MinVersionFlag())
+	assert.True(t, apiServer129.CipherSuiteEnabledByDefault("TLS_RSA_WITH_AES_128_GCM_SHA256"))
+
+	v131 := compatibility.Version{Major: 1, Minor: 31}.TLSDefaults(compatibility.ComponentKubelet)
+
+	assert.Equal(t, compatibility.TLSDefaults{MinVersion: tls.VersionTLS12, TripleDES: true}, v131)
+	assert.Equal(t, "VersionTLS12", v131.MinVersionFlag())
+	assert.False(t, v131.CipherSuiteEnabledByDefault("TLS_RSA_WITH_AES_128_GCM_SHA256"))
+	assert.False(t, v131.CipherSuiteEnabledByDefault("TLS_RSA_WITH_3DES_EDE_CBC_SHA"))
+	assert.True(t, v131.CipherSuiteEnabledByDefault("TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA"))
+
+	assert.Equal(t, v131, compatibility.Version{Major: 1, Minor: 31}.TLSDefaults(compatibility.ComponentKubeAPIServer))
+
+	v134 := compatibility.Version{Major: 1, Minor: 34}.TLSDefaults(compatibility.ComponentKubelet)
+
+	assert.Equal(t, compatibility.TLSDefaults{MinVersion: tls.VersionTLS12}, v134)
+	assert.True(t, v134.CipherSuiteEnabledByDefault("TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"))
+	assert.True(t, v134.CipherSuiteEnabledByDefault("TLS_AES_128_GCM_SHA256"))
+	assert.False(t, v134.CipherSuiteEnabledByDefault("TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA"))
+	assert.False(t, v134.CipherSuiteEnabledByDefault("TLS_ECDHE_RSA_WITH_RC4_128_SHA"))
+	assert.False(t, v134.CipherSuiteEnabledByDefault("TLS_UNKNOWN"))
+}
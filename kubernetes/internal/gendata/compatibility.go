@@ -0,0 +1,114 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// gateHistory is the history of a feature gate default.
+type gateHistory struct {
+	Name   string
+	States []gateState
+}
+
+// gateState is the state of the feature gate starting with the minor version.
+type gateState struct {
+	Since   uint64
+	Enabled bool
+	Locked  bool
+	Removed bool
+}
+
+// featureGateHistory returns the history of the feature gates defaults in the versions.
+//
+// Only the changes of the state are recorded.
+func featureGateHistory(versions []string, gates []map[string]featureGate) []gateHistory {
+	names := map[string]struct{}{}
+
+	for _, versionGates := range gates {
+		for name := range versionGates {
+			names[name] = struct{}{}
+		}
+	}
+
+	history := make([]gateHistory, 0, len(names))
+
+	for name := range names {
+		var states []gateState
+
+		for i, version := range versions {
+			_, minor, _ := strings.Cut(version, ".")
+			since, _ := strconv.ParseUint(minor, 10, 64) //nolint:errcheck
+
+			var state gateState
+
+			gate, ok := gates[i][name]
+
+			switch {
+			case ok:
+				state = gateState{Since: since, Enabled: gate.Default, Locked: gate.Locked}
+			case len(states) > 0:
+				last := states[len(states)-1]
+				state = gateState{Since: since, Enabled: last.Enabled, Locked: last.Locked, Removed: true}
+			default:
+				continue
+			}
+
+			if len(states) > 0 {
+				last := states[len(states)-1]
+
+				if last.Enabled == state.Enabled && last.Locked == state.Locked && last.Removed == state.Removed {
+					continue
+				}
+			}
+
+			states = append(states, state)
+		}
+
+		history = append(history, gateHistory{Name: name, States: states})
+	}
+
+	slices.SortFunc(history, func(a, b gateHistory) int { return strings.Compare(a.Name, b.Name) })
+
+	return history
+}
+
+var compatibilityTemplate = template.Must(template.New("compatibility").Parse(`// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Code generated by gendata. DO NOT EDIT.
+
+package compatibility
+
+// featureGates is the history of the feature gate defaults per Kubernetes minor version.
+//
+// The table can be regenerated from the Kubernetes repository with ` + "`go generate`" + `, see kubernetes/internal/gendata.
+var featureGates = map[string][]featureGateState{
+{{- range . }}
+	{{ printf "%q" .Name }}: {
+		{{- range .States }}
+		{since: {{ .Since }}{{ if .Enabled }}, enabled: true{{ end }}{{ if .Locked }}, locked: true{{ end }}{{ if .Removed }}, removed: true{{ end }}},
+		{{- end }}
+	},
+{{- end }}
+}
+`))
+
+func renderCompatibility(history []gateHistory) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := compatibilityTemplate.Execute(&buf, history); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
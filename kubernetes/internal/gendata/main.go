@@ -0,0 +1,272 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package main implements the generator of the upstream feature gates data.
+//
+// The feature gates defined in each Kubernetes minor release are read from the release sources
+// downloaded from the Go module proxy (GOPROXY, https://proxy.golang.org by default).
+// Depending on the -format flag, the data is generated for:
+//   - upgrade: the gates removed or newly locked to their default value in the next minor release;
+//   - compatibility: the history of the gate defaults per Kubernetes minor release.
+//
+// The flag removals are not available in a machine-readable form, so in the upgrade format the flags
+// mentioned as removed in the release changelog are only printed for manual review.
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blang/semver/v4"
+)
+
+const defaultProxyURL = "https://proxy.golang.org"
+
+// stagingPrefix is the prefix of the staging modules in the Kubernetes repository.
+//
+// The staging modules are published as separate k8s.io/<name> v0.<minor> modules.
+const stagingPrefix = "staging/src/"
+
+// featureFiles are the files defining the feature gates in the Kubernetes repository.
+//
+// The files which don't exist in a release are skipped.
+var featureFiles = []string{
+	"pkg/features/kube_features.go",
+	"pkg/features/versioned_kube_features.go",
+	"staging/src/k8s.io/apiserver/pkg/features/kube_features.go",
+	"staging/src/k8s.io/apiextensions-apiserver/pkg/features/kube_features.go",
+	"staging/src/k8s.io/controller-manager/pkg/features/kube_features.go",
+	"staging/src/k8s.io/component-base/logs/api/v1/kube_features.go",
+	"staging/src/k8s.io/client-go/features/known_features.go",
+}
+
+// removedFlagRe matches the flags mentioned in the changelog entries.
+var removedFlagRe = regexp.MustCompile("`?--([a-z0-9][a-z0-9-]+)`?")
+
+func main() {
+	from := flag.String("from", "1.24", "first Kubernetes minor version to generate the data for")
+	to := flag.String("to", "", "last Kubernetes minor version to generate the data for (required)")
+	outputFormat := flag.String("format", "upgrade", "output format (upgrade or compatibility)")
+	out := flag.String("out", "featuregates_generated.go", "output file")
+	timeout := flag.Duration("timeout", 5*time.Minute, "timeout of downloading the Kubernetes sources")
+
+	flag.Parse()
+
+	if *to == "" {
+		log.Fatal("the -to flag is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if err := run(ctx, *outputFormat, *from, *to, *out); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, outputFormat, from, to, out string) error {
+	versions, err := minorVersions(from, to)
+	if err != nil {
+		return err
+	}
+
+	gates := make([]map[string]featureGate, len(versions))
+
+	for i, version := range versions {
+		if gates[i], err = fetchFeatureGates(ctx, version); err != nil {
+			return fmt.Errorf("error fetching feature gates of %s: %w", version, err)
+		}
+	}
+
+	var source []byte
+
+	switch outputFormat {
+	case "upgrade":
+		source, err = generateUpgrade(ctx, versions, gates)
+	case "compatibility":
+		source, err = renderCompatibility(featureGateHistory(versions, gates))
+	default:
+		err = fmt.Errorf("unknown format %q", outputFormat)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(out, source, 0o644)
+}
+
+// minorVersions returns the list of minor versions between from and to (inclusive).
+func minorVersions(from, to string) ([]string, error) {
+	fromVersion, err := semver.ParseTolerant(from)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing version %q: %w", from, err)
+	}
+
+	toVersion, err := semver.ParseTolerant(to)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing version %q: %w", to, err)
+	}
+
+	if fromVersion.Major != toVersion.Major || fromVersion.Minor >= toVersion.Minor {
+		return nil, fmt.Errorf("invalid version range %s-%s", from, to)
+	}
+
+	var versions []string
+
+	for minor := fromVersion.Minor; minor <= toVersion.Minor; minor++ {
+		versions = append(versions, fmt.Sprintf("%d.%d", fromVersion.Major, minor))
+	}
+
+	return versions, nil
+}
+
+// fetchFeatureGates returns the feature gates defined in the Kubernetes release.
+func fetchFeatureGates(ctx context.Context, version string) (map[string]featureGate, error) {
+	gates := map[string]featureGate{}
+
+	for _, file := range featureFiles {
+		source, err := fetch(ctx, version, file)
+		if err != nil {
+			return nil, err
+		}
+
+		if source == nil {
+			continue
+		}
+
+		fileGates, err := parseFeatureGates(source)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", file, err)
+		}
+
+		for name, gate := range fileGates {
+			gates[name] = gate
+		}
+	}
+
+	if len(gates) == 0 {
+		return nil, fmt.Errorf("no feature gates found")
+	}
+
+	return gates, nil
+}
+
+// changelogRemovedFlags returns the flags mentioned in the changelog entries about removals.
+func changelogRemovedFlags(ctx context.Context, version string) ([]string, error) {
+	changelog, err := fetch(ctx, version, "CHANGELOG/CHANGELOG-"+version+".md")
+	if err != nil || changelog == nil {
+		return nil, err
+	}
+
+	var flags []string
+
+	for _, line := range strings.Split(string(changelog), "\n") {
+		if !strings.Contains(strings.ToLower(line), "removed") {
+			continue
+		}
+
+		for _, match := range removedFlagRe.FindAllStringSubmatch(line, -1) {
+			flags = append(flags, match[1])
+		}
+	}
+
+	slices.Sort(flags)
+
+	return slices.Compact(flags), nil
+}
+
+// moduleZips caches the downloaded module zips by module path and version.
+var moduleZips sync.Map
+
+// fetch returns the contents of the file in the Kubernetes release, or nil if the file doesn't exist.
+func fetch(ctx context.Context, version, file string) ([]byte, error) {
+	module, moduleVersion, path := "k8s.io/kubernetes", "v"+version+".0", file
+
+	if stagingFile, ok := strings.CutPrefix(file, stagingPrefix); ok {
+		var name string
+
+		name, path, _ = strings.Cut(strings.TrimPrefix(stagingFile, "k8s.io/"), "/")
+		module, moduleVersion = "k8s.io/"+name, "v0."+strings.TrimPrefix(version, "1.")+".0"
+	}
+
+	archive, err := fetchModule(ctx, module, moduleVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := archive.Open(module + "@" + moduleVersion + "/" + path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	return io.ReadAll(f)
+}
+
+// fetchModule downloads the module zip from the Go module proxy.
+func fetchModule(ctx context.Context, module, version string) (*zip.Reader, error) {
+	key := module + "@" + version
+
+	if archive, ok := moduleZips.Load(key); ok {
+		return archive.(*zip.Reader), nil //nolint:forcetypeassert
+	}
+
+	proxyURL := defaultProxyURL
+
+	if env := os.Getenv("GOPROXY"); env != "" {
+		if first, _, _ := strings.Cut(env, ","); strings.HasPrefix(first, "http") {
+			proxyURL = strings.TrimSuffix(first, "/")
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s/@v/%s.zip", proxyURL, module, version), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s fetching %s", resp.Status, req.URL)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading %s: %w", req.URL, err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", req.URL, err)
+	}
+
+	moduleZips.Store(key, archive)
+
+	return archive, nil
+}
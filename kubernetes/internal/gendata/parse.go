@@ -0,0 +1,184 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+)
+
+// featureGate is the specification of a feature gate in a Kubernetes release.
+type featureGate struct {
+	Default bool
+	Locked  bool
+}
+
+// parseFeatureGates returns the feature gates defined in the Go source file.
+//
+// Both the unversioned (map[featuregate.Feature]featuregate.FeatureSpec) and the versioned
+// (map[featuregate.Feature]featuregate.VersionedSpecs) feature gate maps are supported,
+// for the versioned specs the latest one is used.
+func parseFeatureGates(source []byte) (map[string]featureGate, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", source, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	constants := stringConstants(file)
+	gates := map[string]featureGate{}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		literal, ok := node.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+
+		mapType, ok := literal.Type.(*ast.MapType)
+		if !ok {
+			return true
+		}
+
+		valueType := selectorName(mapType.Value)
+		if valueType != "FeatureSpec" && valueType != "VersionedSpecs" {
+			return true
+		}
+
+		for _, elt := range literal.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			name := gateName(kv.Key, constants)
+			if name == "" {
+				continue
+			}
+
+			spec, ok := kv.Value.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+
+			if valueType == "VersionedSpecs" {
+				if len(spec.Elts) == 0 {
+					continue
+				}
+
+				if spec, ok = spec.Elts[len(spec.Elts)-1].(*ast.CompositeLit); !ok {
+					continue
+				}
+			}
+
+			gates[name] = parseFeatureSpec(spec)
+		}
+
+		return false
+	})
+
+	return gates, nil
+}
+
+// parseFeatureSpec parses the featuregate.FeatureSpec literal.
+func parseFeatureSpec(spec *ast.CompositeLit) featureGate {
+	var gate featureGate
+
+	for _, elt := range spec.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		field, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		value, ok := kv.Value.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		switch field.Name {
+		case "Default":
+			gate.Default = value.Name == "true"
+		case "LockToDefault":
+			gate.Locked = value.Name == "true"
+		}
+	}
+
+	return gate
+}
+
+// stringConstants returns the values of the string constants defined in the file.
+func stringConstants(file *ast.File) map[string]string {
+	constants := map[string]string{}
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+
+			for i, name := range valueSpec.Names {
+				if i >= len(valueSpec.Values) {
+					break
+				}
+
+				lit, ok := valueSpec.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+
+				if value, err := strconv.Unquote(lit.Value); err == nil {
+					constants[name.Name] = value
+				}
+			}
+		}
+	}
+
+	return constants
+}
+
+// gateName returns the name of the feature gate used as the map key.
+//
+// The gates defined in other packages (e.g. genericfeatures.APIListChunking) are named after the constant.
+func gateName(key ast.Expr, constants map[string]string) string {
+	switch key := key.(type) {
+	case *ast.Ident:
+		if value, ok := constants[key.Name]; ok {
+			return value
+		}
+
+		return key.Name
+	case *ast.SelectorExpr:
+		return key.Sel.Name
+	case *ast.BasicLit:
+		value, _ := strconv.Unquote(key.Value) //nolint:errcheck
+
+		return value
+	default:
+		return ""
+	}
+}
+
+// selectorName returns the name of the (possibly package-qualified) type.
+func selectorName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	default:
+		return ""
+	}
+}
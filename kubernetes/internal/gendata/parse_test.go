@@ -0,0 +1,154 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const unversionedFeatures = `package features
+
+import (
+	genericfeatures "k8s.io/apiserver/pkg/features"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	CronJobTimeZone featuregate.Feature = "CronJobTimeZone"
+	DownwardAPIHugePages featuregate.Feature = "DownwardAPIHugePages"
+	ExpandCSIVolumes featuregate.Feature = "ExpandCSIVolumes"
+)
+
+var defaultKubernetesFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	CronJobTimeZone: {Default: true, PreRelease: featuregate.Beta},
+	DownwardAPIHugePages: {Default: true, PreRelease: featuregate.Beta},
+	ExpandCSIVolumes: {Default: true, PreRelease: featuregate.GA, LockToDefault: true},
+
+	genericfeatures.APIListChunking: {Default: true, PreRelease: featuregate.Beta},
+}
+`
+
+const versionedFeatures = `package features
+
+import (
+	genericfeatures "k8s.io/apiserver/pkg/features"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	CronJobTimeZone featuregate.Feature = "CronJobTimeZone"
+	DownwardAPIHugePages featuregate.Feature = "DownwardAPIHugePages"
+)
+
+var defaultVersionedKubernetesFeatureGates = map[featuregate.Feature]featuregate.VersionedSpecs{
+	CronJobTimeZone: {
+		{Version: version.MustParse("1.24"), Default: true, PreRelease: featuregate.Beta},
+		{Version: version.MustParse("1.27"), Default: true, PreRelease: featuregate.GA, LockToDefault: true},
+	},
+	DownwardAPIHugePages: {
+		{Version: version.MustParse("1.22"), Default: true, PreRelease: featuregate.Beta},
+	},
+
+	genericfeatures.APIListChunking: {
+		{Version: version.MustParse("1.29"), Default: true, PreRelease: featuregate.GA, LockToDefault: true},
+	},
+}
+`
+
+func TestParseFeatureGates(t *testing.T) {
+	previous, err := parseFeatureGates([]byte(unversionedFeatures))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]featureGate{
+		"CronJobTimeZone":      {Default: true},
+		"DownwardAPIHugePages": {Default: true},
+		"ExpandCSIVolumes":     {Default: true, Locked: true},
+		"APIListChunking":      {Default: true},
+	}, previous)
+
+	next, err := parseFeatureGates([]byte(versionedFeatures))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]featureGate{
+		"CronJobTimeZone":      {Default: true, Locked: true},
+		"DownwardAPIHugePages": {Default: true},
+		"APIListChunking":      {Default: true, Locked: true},
+	}, next)
+
+	data := diffFeatureGates(previous, next)
+
+	assert.Equal(t, []string{"ExpandCSIVolumes"}, data.Removed)
+	assert.Equal(t, []lockedGate{{Name: "APIListChunking", Value: true}, {Name: "CronJobTimeZone", Value: true}}, data.Locked)
+
+	data.Path = "1.26->1.27"
+
+	source, err := render([]pathData{data})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(source), `"1.26->1.27": {
+		removed: []string{
+			"ExpandCSIVolumes",
+		},
+		locked: map[string]bool{
+			"APIListChunking": true,
+			"CronJobTimeZone": true,
+		},
+	},`)
+}
+
+func TestMinorVersions(t *testing.T) {
+	versions, err := minorVersions("1.32", "1.34")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"1.32", "1.33", "1.34"}, versions)
+
+	_, err = minorVersions("1.34", "1.32")
+	require.Error(t, err)
+}
+
+func TestFeatureGateHistory(t *testing.T) {
+	history := featureGateHistory([]string{"1.26", "1.27", "1.28", "1.29"}, []map[string]featureGate{
+		{"CronJobTimeZone": {Default: true}, "ExpandCSIVolumes": {Default: true, Locked: true}},
+		{"CronJobTimeZone": {Default: true, Locked: true}},
+		{"CronJobTimeZone": {Default: true, Locked: true}, "SidecarContainers": {}},
+		{"SidecarContainers": {Default: true}},
+	})
+
+	assert.Equal(t, []gateHistory{
+		{
+			Name: "CronJobTimeZone",
+			States: []gateState{
+				{Since: 26, Enabled: true},
+				{Since: 27, Enabled: true, Locked: true},
+				{Since: 29, Enabled: true, Locked: true, Removed: true},
+			},
+		},
+		{
+			Name: "ExpandCSIVolumes",
+			States: []gateState{
+				{Since: 26, Enabled: true, Locked: true},
+				{Since: 27, Enabled: true, Locked: true, Removed: true},
+			},
+		},
+		{
+			Name: "SidecarContainers",
+			States: []gateState{
+				{Since: 28},
+				{Since: 29, Enabled: true},
+			},
+		},
+	}, history)
+
+	source, err := renderCompatibility(history)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(source), `"SidecarContainers": {
+		{since: 28},
+		{since: 29, enabled: true},
+	},`)
+}
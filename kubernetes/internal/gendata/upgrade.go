@@ -0,0 +1,121 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"log"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// generateUpgrade generates the upstream feature gates data of the upgrade paths between the versions.
+func generateUpgrade(ctx context.Context, versions []string, gates []map[string]featureGate) ([]byte, error) {
+	var paths []pathData
+
+	for i := 1; i < len(versions); i++ {
+		data := diffFeatureGates(gates[i-1], gates[i])
+		data.Path = versions[i-1] + "->" + versions[i]
+
+		if len(data.Removed) > 0 || len(data.Locked) > 0 {
+			paths = append(paths, data)
+		}
+
+		flags, err := changelogRemovedFlags(ctx, versions[i])
+		if err != nil {
+			return nil, fmt.Errorf("error fetching changelog of %s: %w", versions[i], err)
+		}
+
+		if len(flags) > 0 {
+			log.Printf("%s: flags mentioned as removed in the changelog (review manually): %s", data.Path, strings.Join(flags, ", "))
+		}
+	}
+
+	return render(paths)
+}
+
+// pathData is the feature gates data of an upgrade path.
+type pathData struct {
+	Path    string
+	Removed []string
+	Locked  []lockedGate
+}
+
+type lockedGate struct {
+	Name  string
+	Value bool
+}
+
+// diffFeatureGates returns the feature gates removed or newly locked to their default in the next release.
+func diffFeatureGates(previous, next map[string]featureGate) pathData {
+	var data pathData
+
+	for name := range previous {
+		if _, ok := next[name]; !ok {
+			data.Removed = append(data.Removed, name)
+		}
+	}
+
+	for name, gate := range next {
+		if !gate.Locked {
+			continue
+		}
+
+		if old, ok := previous[name]; ok && old.Locked && old.Default == gate.Default {
+			continue
+		}
+
+		data.Locked = append(data.Locked, lockedGate{Name: name, Value: gate.Default})
+	}
+
+	slices.Sort(data.Removed)
+	slices.SortFunc(data.Locked, func(a, b lockedGate) int { return strings.Compare(a.Name, b.Name) })
+
+	return data
+}
+
+var outputTemplate = template.Must(template.New("output").Parse(`// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Code generated by gendata. DO NOT EDIT.
+
+package upgrade
+
+var upstreamFeatureGates = map[string]upstreamFeatureGateChanges{
+{{- range . }}
+	{{ printf "%q" .Path }}: {
+		{{- if .Removed }}
+		removed: []string{
+			{{- range .Removed }}
+			{{ printf "%q" . }},
+			{{- end }}
+		},
+		{{- end }}
+		{{- if .Locked }}
+		locked: map[string]bool{
+			{{- range .Locked }}
+			{{ printf "%q" .Name }}: {{ .Value }},
+			{{- end }}
+		},
+		{{- end }}
+	},
+{{- end }}
+}
+`))
+
+func render(paths []pathData) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := outputTemplate.Execute(&buf, paths); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
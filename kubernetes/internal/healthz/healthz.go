@@ -0,0 +1,21 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package healthz parses the output of kube-apiserver health endpoints.
+package healthz
+
+import "strings"
+
+// FailedChecks returns the failed checks from the verbose /livez or /readyz output, e.g. "[-]etcd failed: reason withheld".
+func FailedChecks(body []byte) []string {
+	var checks []string
+
+	for _, line := range strings.Split(string(body), "\n") {
+		if check, ok := strings.CutPrefix(strings.TrimSpace(line), "[-]"); ok {
+			checks = append(checks, check)
+		}
+	}
+
+	return checks
+}
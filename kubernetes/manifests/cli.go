@@ -7,22 +7,61 @@ package manifests
 import (
 	"context"
 
+	"github.com/siderolabs/gen/channel"
 	"k8s.io/client-go/rest"
 )
 
 // SyncWithLog applies the manifests to the cluster logging the results via logFunc.
-func SyncWithLog(ctx context.Context, objects []Manifest, config *rest.Config, dryRun bool, logFunc func(string, ...any)) error {
+func SyncWithLog(ctx context.Context, objects []Manifest, config *rest.Config, dryRun bool, logFunc func(string, ...any), opts ...SyncOption) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	eventCh := make(chan SyncEvent)
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- SyncWithEvents(ctx, objects, config, dryRun, eventCh, opts...)
+	}()
+
+	for {
+		select {
+		case event := <-eventCh:
+			for _, line := range event.Format() {
+				logFunc("%s", line)
+			}
+		case err := <-errCh:
+			return err
+		}
+	}
+}
+
+// SyncWithEvents applies the manifests to the cluster and waits for the rollout, reporting the progress as events.
+//
+// SyncWithEvents follows the same flow as SyncWithLog, SyncEvent.Format can be used to render the events.
+// SyncEventRolloutWaiting is only sent for the objects which are not ready when the rollout wait starts,
+// so the rollout of the objects which are already ready produces no events between SyncEventRolloutStarted and SyncEventRolloutComplete.
+func SyncWithEvents(ctx context.Context, objects []Manifest, config *rest.Config, dryRun bool, eventCh chan<- SyncEvent, opts ...SyncOption) error {
+	options := newSyncOptions(opts)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	send := func(event SyncEvent) bool {
+		event.DryRun = dryRun
+
+		return channel.SendWithContext(ctx, eventCh, event)
+	}
+
 	syncCh := make(chan SyncResult)
 	errCh := make(chan error, 1)
 
 	go func() {
-		errCh <- Sync(ctx, objects, config, dryRun, syncCh)
+		errCh <- Sync(ctx, objects, config, dryRun, syncCh, opts...)
 	}()
 
-	logFunc("updating manifests")
+	if !send(SyncEvent{Type: SyncEventStarted}) {
+		return ctx.Err()
+	}
 
 	var updatedManifests []Manifest
 
@@ -30,18 +69,11 @@ syncLoop:
 	for {
 		select {
 		case result := <-syncCh:
-			logFunc(" > processing manifest %s", result.Path)
-
-			switch {
-			case result.Skipped:
-				logFunc(" < no changes")
-			case dryRun:
-				logFunc("%s", result.Diff)
-				logFunc(" < dry run, change skipped")
-			case !dryRun:
-				logFunc("%s", result.Diff)
-				logFunc(" < applied successfully")
+			if !send(SyncEvent{Type: SyncEventManifestApplied, Result: &result}) {
+				return ctx.Err()
+			}
 
+			if !dryRun && !result.Skipped && !result.Deleted {
 				updatedManifests = append(updatedManifests, result.Object)
 			}
 		case err := <-errCh:
@@ -57,20 +89,38 @@ syncLoop:
 		return nil
 	}
 
-	logFunc("waiting for all manifests to be applied")
+	if !send(SyncEvent{Type: SyncEventRolloutStarted}) {
+		return ctx.Err()
+	}
 
 	rolloutCh := make(chan RolloutProgress)
 
 	go func() {
-		errCh <- WaitForRollout(ctx, config, updatedManifests, rolloutCh)
+		errCh <- WaitForRollout(ctx, config, updatedManifests, rolloutCh, options.RolloutOptions...)
 	}()
 
 	for {
 		select {
 		case result := <-rolloutCh:
-			logFunc(" > waiting for %s", result.Path)
+			eventType := SyncEventRolloutWaiting
+
+			if result.Diagnostics != nil {
+				eventType = SyncEventRolloutFailed
+			}
+
+			if !send(SyncEvent{Type: eventType, Rollout: &result}) {
+				return ctx.Err()
+			}
 		case err := <-errCh:
-			return err
+			if err != nil {
+				return err
+			}
+
+			if !send(SyncEvent{Type: SyncEventRolloutComplete}) {
+				return ctx.Err()
+			}
+
+			return nil
 		}
 	}
 }
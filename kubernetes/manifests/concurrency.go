@@ -0,0 +1,115 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"context"
+	"sync"
+
+	"github.com/siderolabs/gen/channel"
+)
+
+// groupByKindWeight splits the objects into runs of consecutive objects with the same install order weight.
+//
+// Objects within the group don't depend on each other, while groups should be processed in order.
+func groupByKindWeight(objects []Manifest, weights map[string]int) [][]Manifest {
+	var groups [][]Manifest
+
+	for i, obj := range objects {
+		if i == 0 || kindWeight(obj, weights) != kindWeight(objects[i-1], weights) {
+			groups = append(groups, nil)
+		}
+
+		groups[len(groups)-1] = append(groups[len(groups)-1], obj)
+	}
+
+	return groups
+}
+
+// syncGroup applies the objects with bounded concurrency sending the results in the order of objects.
+//
+// The first error aborts processing of the group.
+func syncGroup(
+	ctx context.Context,
+	objects []Manifest,
+	concurrency int,
+	apply func(context.Context, Manifest) (SyncResult, error),
+	resultCh chan<- SyncResult,
+) error {
+	type outcome struct {
+		err    error
+		result SyncResult
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	// the first failure aborts the objects still in progress
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+
+			cancel()
+		})
+	}
+
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	semaphore := make(chan struct{}, max(concurrency, 1))
+	outcomes := make([]chan outcome, len(objects))
+
+	for i, obj := range objects {
+		outcomes[i] = make(chan outcome, 1)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				outcomes[i] <- outcome{err: ctx.Err()}
+
+				return
+			}
+
+			defer func() { <-semaphore }()
+
+			result, err := apply(ctx, obj)
+			if err != nil {
+				fail(err)
+			}
+
+			outcomes[i] <- outcome{result: result, err: err}
+		}()
+	}
+
+	for i := range objects {
+		o := <-outcomes[i]
+
+		if o.err != nil {
+			fail(o.err)
+
+			return firstErr
+		}
+
+		if !channel.SendWithContext(ctx, resultCh, o.result) {
+			fail(ctx.Err())
+
+			return firstErr
+		}
+	}
+
+	return nil
+}
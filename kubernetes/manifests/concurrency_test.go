@@ -0,0 +1,203 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/siderolabs/gen/xslices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+func configMaps(n int) []manifests.Manifest {
+	objects := make([]manifests.Manifest, 0, n)
+
+	for i := range n {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetNamespace("default")
+		obj.SetName("config-" + strconv.Itoa(i))
+
+		objects = append(objects, obj)
+	}
+
+	return objects
+}
+
+func TestGroupByKindWeight(t *testing.T) {
+	objects := manifests.SortObjects(parseManifests(t, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: apps
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: sa
+  namespace: apps
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: apps
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+  namespace: apps
+`), nil)
+
+	kinds := func(groups [][]manifests.Manifest) [][]string {
+		return xslices.Map(groups, func(group []manifests.Manifest) []string {
+			return xslices.Map(group, func(obj manifests.Manifest) string { return obj.GetKind() })
+		})
+	}
+
+	assert.Equal(t, [][]string{{"Namespace"}, {"ServiceAccount"}, {"ConfigMap", "Secret"}}, kinds(manifests.GroupByKindWeight(objects, nil)))
+	assert.Equal(t, [][]string{{"Namespace"}, {"ServiceAccount", "ConfigMap", "Secret"}},
+		kinds(manifests.GroupByKindWeight(objects, map[string]int{"ServiceAccount": 50})))
+}
+
+func TestSyncGroupConcurrency(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer ctxCancel()
+
+	const concurrency = 2
+
+	objects := configMaps(6)
+
+	var inFlight, maxInFlight atomic.Int32
+
+	apply := func(_ context.Context, obj manifests.Manifest) (manifests.SyncResult, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			current := maxInFlight.Load()
+			if n <= current || maxInFlight.CompareAndSwap(current, n) {
+				break
+			}
+		}
+
+		// the first objects finish last
+		index, _ := strconv.Atoi(obj.GetName()[len("config-"):]) //nolint:errcheck
+		time.Sleep(time.Duration(len(objects)-index) * 10 * time.Millisecond)
+
+		return manifests.SyncResult{Object: obj}, nil
+	}
+
+	resultCh := make(chan manifests.SyncResult, len(objects))
+
+	require.NoError(t, manifests.SyncGroup(ctx, objects, concurrency, apply, resultCh))
+	close(resultCh)
+
+	var names []string
+
+	for result := range resultCh {
+		names = append(names, result.Object.GetName())
+	}
+
+	assert.Equal(t, xslices.Map(objects, manifests.Manifest.GetName), names)
+	assert.EqualValues(t, concurrency, maxInFlight.Load())
+}
+
+func TestSyncGroupError(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer ctxCancel()
+
+	objects := configMaps(4)
+	errFailed := errors.New("apply failed")
+
+	var started, cancelled atomic.Int32
+
+	apply := func(ctx context.Context, obj manifests.Manifest) (manifests.SyncResult, error) {
+		if obj.GetName() == "config-0" {
+			// the other objects are still in progress when the first one fails
+			for started.Load() < int32(len(objects)-1) {
+				time.Sleep(time.Millisecond)
+			}
+
+			return manifests.SyncResult{}, errFailed
+		}
+
+		started.Add(1)
+		<-ctx.Done()
+		cancelled.Add(1)
+
+		return manifests.SyncResult{}, ctx.Err()
+	}
+
+	resultCh := make(chan manifests.SyncResult, len(objects))
+
+	require.ErrorIs(t, manifests.SyncGroup(ctx, objects, len(objects), apply, resultCh), errFailed)
+	assert.EqualValues(t, len(objects)-1, cancelled.Load())
+	assert.Empty(t, resultCh)
+}
+
+func TestSyncConcurrencyOrder(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer ctxCancel()
+
+	fake, config := newFakeAPIServer(t)
+
+	var namespaceCreated atomic.Bool
+
+	fake.intercept = func(_ http.ResponseWriter, r *http.Request) bool {
+		if r.Method != http.MethodPost {
+			return false
+		}
+
+		if r.URL.Path == "/api/v1/namespaces" {
+			// the objects in the namespace must wait for the namespace to be created
+			time.Sleep(100 * time.Millisecond)
+			namespaceCreated.Store(true)
+		} else {
+			assert.True(t, namespaceCreated.Load(), "%s created before the namespace", r.URL.Path)
+		}
+
+		return false
+	}
+
+	results, err := syncObjects(ctx, config, parseManifests(t, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: apps
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+  namespace: apps
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: apps
+`), false, manifests.WithConcurrency(3))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Namespace", "ConfigMap", "Secret"}, xslices.Map(results, func(result manifests.SyncResult) string {
+		return result.Object.GetKind()
+	}))
+
+	paths := xslices.Map(fake.recorded(), func(req fakeRequest) string { return req.Path })
+	require.Len(t, paths, 3)
+	assert.Equal(t, "/api/v1/namespaces", paths[0])
+	assert.ElementsMatch(t, []string{"/api/v1/namespaces/apps/configmaps", "/api/v1/namespaces/apps/secrets"}, paths[1:])
+}
@@ -0,0 +1,53 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/siderolabs/go-retry/retry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes"
+)
+
+// defaultCRDEstablishedTimeout is the default time to wait for the applied CRDs to be established.
+const defaultCRDEstablishedTimeout = time.Minute
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+func isCRD(obj Manifest) bool {
+	return obj.GetKind() == "CustomResourceDefinition" && obj.GroupVersionKind().Group == "apiextensions.k8s.io"
+}
+
+// waitForCRDs waits for the CRDs to be established, so that the custom resources can be created.
+func waitForCRDs(ctx context.Context, k8sClient dynamic.Interface, crds []Manifest, timeout time.Duration) error {
+	for _, crd := range crds {
+		if err := retry.Constant(timeout, retry.WithUnits(time.Second)).RetryWithContext(ctx, func(ctx context.Context) error {
+			obj, err := k8sClient.Resource(crdResource).Get(ctx, crd.GetName(), metav1.GetOptions{})
+			if err != nil {
+				if kubernetes.IsRetryableError(err) {
+					return retry.ExpectedError(err)
+				}
+
+				return err
+			}
+
+			if status := ComputeStatus(obj); status.Status != StatusCurrent {
+				return retry.ExpectedErrorf("CRD %s is not established: %s", crd.GetName(), status.Message)
+			}
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("error waiting for CRD %s: %w", crd.GetName(), err)
+		}
+	}
+
+	return nil
+}
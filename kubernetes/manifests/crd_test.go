@@ -0,0 +1,124 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/siderolabs/gen/xslices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+const (
+	widgetCRDPath = "/apis/apiextensions.k8s.io/v1/customresourcedefinitions/widgets.example.com"
+	widgetPath    = "/apis/example.com/v1/namespaces/default/widgets/widget"
+)
+
+const widgetManifests = `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: default
+spec:
+  size: 3
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+`
+
+// establishCRD marks the stored CRD as established.
+func establishCRD(fake *fakeAPIServer, path string) {
+	crd := fake.get(path)
+
+	unstructured.SetNestedSlice(crd, []any{ //nolint:errcheck
+		map[string]any{"type": "NamesAccepted", "status": "True"},
+		map[string]any{"type": "Established", "status": "True"},
+	}, "status", "conditions")
+
+	fake.set(path, crd)
+}
+
+func TestSyncCRD(t *testing.T) {
+	for _, test := range []struct {
+		name string
+
+		timeout time.Duration
+		// establishAfter is the number of CRD lookups after which the CRD is established, 0 means never
+		establishAfter int32
+
+		expectedError string
+	}{
+		{
+			name:           "established",
+			timeout:        10 * time.Second,
+			establishAfter: 3,
+		},
+		{
+			name:    "timeout",
+			timeout: 2 * time.Second,
+
+			expectedError: "error waiting for CRD widgets.example.com",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, ctxCancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer ctxCancel()
+
+			fake, config := newFakeAPIServer(t)
+
+			var lookups atomic.Int32
+
+			fake.intercept = func(_ http.ResponseWriter, r *http.Request) bool {
+				if r.Method == http.MethodGet && r.URL.Path == widgetCRDPath && fake.get(widgetCRDPath) != nil {
+					if lookups.Add(1) == test.establishAfter {
+						establishCRD(fake, widgetCRDPath)
+					}
+				}
+
+				return false
+			}
+
+			results, err := syncObjects(ctx, config, parseManifests(t, widgetManifests), false,
+				manifests.WithCRDEstablishedTimeout(test.timeout))
+
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				assert.Len(t, results, 1)
+				assert.Nil(t, fake.get(widgetPath))
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			// the custom resource is created once the CRD is established
+			assert.Equal(t, []string{"CustomResourceDefinition", "Widget"}, xslices.Map(results, func(result manifests.SyncResult) string {
+				return result.Object.GetKind()
+			}))
+			assert.GreaterOrEqual(t, lookups.Load(), test.establishAfter)
+
+			widget := fake.get(widgetPath)
+			require.NotNil(t, widget)
+			size, _, _ := unstructured.NestedFieldNoCopy(widget, "spec", "size") //nolint:errcheck
+			assert.EqualValues(t, 3, size)
+		})
+	}
+}
@@ -0,0 +1,229 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+const (
+	maxDiagnosticsPods   = 10
+	maxDiagnosticsEvents = 10
+)
+
+// RolloutDiagnostics explains why an object failed to become ready.
+type RolloutDiagnostics struct {
+	UnhealthyPods []PodDiagnostics
+	Events        []EventDiagnostics
+}
+
+// PodDiagnostics describes an unhealthy pod of a workload.
+type PodDiagnostics struct {
+	Name       string
+	Phase      string
+	Containers []ContainerDiagnostics
+}
+
+// ContainerDiagnostics describes a container which is not running, e.g. waiting with ImagePullBackOff or CrashLoopBackOff reason.
+type ContainerDiagnostics struct {
+	Name    string
+	Reason  string
+	Message string
+}
+
+// EventDiagnostics is a recent warning event for a workload or one of its pods.
+type EventDiagnostics struct {
+	LastSeen time.Time
+	Object   string
+	Reason   string
+	Message  string
+	Count    int32
+}
+
+// String returns a human-readable summary of the diagnostics.
+func (d *RolloutDiagnostics) String() string {
+	if d == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	if len(d.UnhealthyPods) > 0 {
+		sb.WriteString("unhealthy pods:\n")
+
+		for _, pod := range d.UnhealthyPods {
+			fmt.Fprintf(&sb, "  %s: %s\n", pod.Name, pod.Phase)
+
+			for _, container := range pod.Containers {
+				fmt.Fprintf(&sb, "    container %s: %s", container.Name, container.Reason)
+
+				if container.Message != "" {
+					fmt.Fprintf(&sb, ": %s", container.Message)
+				}
+
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	if len(d.Events) > 0 {
+		sb.WriteString("warning events:\n")
+
+		for _, event := range d.Events {
+			fmt.Fprintf(&sb, "  %s: %s: %s (x%d)\n", event.Object, event.Reason, event.Message, event.Count)
+		}
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// collectDiagnostics collects (best-effort) information on why the object is not ready.
+func collectDiagnostics(ctx context.Context, clientset k8s.Interface, obj Manifest) *RolloutDiagnostics {
+	diagnostics := &RolloutDiagnostics{}
+
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+
+	involved := []string{obj.GetKind() + "/" + obj.GetName()}
+
+	for _, pod := range unhealthyPods(ctx, clientset, obj) {
+		diagnostics.UnhealthyPods = append(diagnostics.UnhealthyPods, pod)
+		involved = append(involved, "Pod/"+pod.Name)
+	}
+
+	for _, kindName := range involved {
+		kind, name, _ := strings.Cut(kindName, "/")
+
+		events, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: fields.Set{
+				"involvedObject.kind": kind,
+				"involvedObject.name": name,
+				"type":                v1.EventTypeWarning,
+			}.String(),
+		})
+		if err != nil {
+			continue
+		}
+
+		for _, event := range events.Items {
+			lastSeen := event.LastTimestamp.Time
+			if lastSeen.IsZero() {
+				lastSeen = event.EventTime.Time
+			}
+
+			diagnostics.Events = append(diagnostics.Events, EventDiagnostics{
+				Object:   kindName,
+				Reason:   event.Reason,
+				Message:  event.Message,
+				Count:    max(event.Count, 1),
+				LastSeen: lastSeen,
+			})
+		}
+	}
+
+	slices.SortStableFunc(diagnostics.Events, func(a, b EventDiagnostics) int {
+		return b.LastSeen.Compare(a.LastSeen)
+	})
+
+	if len(diagnostics.Events) > maxDiagnosticsEvents {
+		diagnostics.Events = diagnostics.Events[:maxDiagnosticsEvents]
+	}
+
+	return diagnostics
+}
+
+func unhealthyPods(ctx context.Context, clientset k8s.Interface, obj Manifest) []PodDiagnostics {
+	switch obj.GroupVersionKind().GroupKind().String() {
+	case "Deployment.apps", "DaemonSet.apps", "StatefulSet.apps", "ReplicaSet.apps", "Job.batch":
+	default:
+		return nil
+	}
+
+	selectorSpec, found, _ := unstructured.NestedMap(obj.Object, "spec", "selector")
+	if !found {
+		return nil
+	}
+
+	var labelSelector metav1.LabelSelector
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selectorSpec, &labelSelector); err != nil {
+		return nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+	if err != nil {
+		return nil
+	}
+
+	pods, err := clientset.CoreV1().Pods(obj.GetNamespace()).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil
+	}
+
+	var result []PodDiagnostics
+
+	for _, pod := range pods.Items {
+		if isPodHealthy(&pod) {
+			continue
+		}
+
+		diagnostics := PodDiagnostics{
+			Name:  pod.Name,
+			Phase: string(pod.Status.Phase),
+		}
+
+		for _, cs := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+			switch {
+			case cs.State.Waiting != nil:
+				diagnostics.Containers = append(diagnostics.Containers, ContainerDiagnostics{
+					Name:    cs.Name,
+					Reason:  cs.State.Waiting.Reason,
+					Message: cs.State.Waiting.Message,
+				})
+			case cs.State.Terminated != nil && cs.State.Terminated.ExitCode != 0:
+				diagnostics.Containers = append(diagnostics.Containers, ContainerDiagnostics{
+					Name:    cs.Name,
+					Reason:  cs.State.Terminated.Reason,
+					Message: cs.State.Terminated.Message,
+				})
+			}
+		}
+
+		result = append(result, diagnostics)
+
+		if len(result) >= maxDiagnosticsPods {
+			break
+		}
+	}
+
+	return result
+}
+
+func isPodHealthy(pod *v1.Pod) bool {
+	if pod.Status.Phase == v1.PodSucceeded {
+		return true
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
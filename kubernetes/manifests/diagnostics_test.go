@@ -0,0 +1,49 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+func TestRolloutDiagnosticsString(t *testing.T) {
+	var empty *manifests.RolloutDiagnostics
+
+	assert.Empty(t, empty.String())
+
+	diagnostics := &manifests.RolloutDiagnostics{
+		UnhealthyPods: []manifests.PodDiagnostics{
+			{
+				Name:  "coredns-5d78c9869d-x7x2z",
+				Phase: "Pending",
+				Containers: []manifests.ContainerDiagnostics{
+					{
+						Name:    "coredns",
+						Reason:  "ImagePullBackOff",
+						Message: `Back-off pulling image "coredns:v0"`,
+					},
+				},
+			},
+		},
+		Events: []manifests.EventDiagnostics{
+			{
+				Object:  "Pod/coredns-5d78c9869d-x7x2z",
+				Reason:  "Failed",
+				Message: "Error: ErrImagePull",
+				Count:   3,
+			},
+		},
+	}
+
+	assert.Equal(t, `unhealthy pods:
+  coredns-5d78c9869d-x7x2z: Pending
+    container coredns: ImagePullBackOff: Back-off pulling image "coredns:v0"
+warning events:
+  Pod/coredns-5d78c9869d-x7x2z: Failed: Error: ErrImagePull (x3)`, diagnostics.String())
+}
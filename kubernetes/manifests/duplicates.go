@@ -0,0 +1,106 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DuplicatePolicy defines how the objects defined more than once are handled.
+type DuplicatePolicy int
+
+// Duplicate policies.
+const (
+	// DuplicatePolicyError fails if the same object is defined more than once with different content.
+	DuplicatePolicyError DuplicatePolicy = iota
+	// DuplicatePolicyFirstWins keeps the first definition of the object, ignoring the later ones.
+	DuplicatePolicyFirstWins
+	// DuplicatePolicyMerge merges the later definitions into the first one, later values take precedence.
+	//
+	// Maps are merged recursively, any other values (including lists) are replaced.
+	DuplicatePolicyMerge
+	// DuplicatePolicyLastWins keeps the last definition of the object, as if the definitions were applied one after another.
+	DuplicatePolicyLastWins
+)
+
+// DuplicateObjectError is returned when the objects are defined more than once with different content.
+type DuplicateObjectError struct {
+	Paths []string
+}
+
+// Error implements error interface.
+func (e *DuplicateObjectError) Error() string {
+	return fmt.Sprintf("objects are defined more than once with different content: %s", strings.Join(e.Paths, ", "))
+}
+
+// ResolveDuplicates returns the objects with duplicates (same group, kind, namespace and name) resolved according to the policy.
+//
+// Identical duplicates are always collapsed into a single object.
+// The resolved object takes the position of the first definition.
+func ResolveDuplicates(objects []Manifest, policy DuplicatePolicy) ([]Manifest, error) {
+	resolved := make([]Manifest, 0, len(objects))
+	index := make(map[string]int, len(objects))
+
+	var conflicts []string
+
+	for _, obj := range objects {
+		key := objectKey(obj)
+
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(resolved)
+			resolved = append(resolved, obj)
+
+			continue
+		}
+
+		if equality.Semantic.DeepEqual(resolved[i].Object, obj.Object) {
+			continue
+		}
+
+		switch policy {
+		case DuplicatePolicyError:
+			conflicts = append(conflicts, manifestPath(obj))
+		case DuplicatePolicyFirstWins:
+		case DuplicatePolicyLastWins:
+			resolved[i] = obj
+		case DuplicatePolicyMerge:
+			merged := resolved[i].DeepCopy()
+			merged.Object = mergeObjects(merged.Object, runtime.DeepCopyJSON(obj.Object))
+
+			resolved[i] = merged
+		default:
+			return nil, fmt.Errorf("unknown duplicate policy %d", policy)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return nil, &DuplicateObjectError{Paths: conflicts}
+	}
+
+	return resolved, nil
+}
+
+// mergeObjects merges src into dst recursively.
+func mergeObjects(dst, src map[string]any) map[string]any {
+	for k, v := range src {
+		srcMap, srcOk := v.(map[string]any)
+		dstMap, dstOk := dst[k].(map[string]any)
+
+		if srcOk && dstOk {
+			dst[k] = mergeObjects(dstMap, srcMap)
+
+			continue
+		}
+
+		dst[k] = v
+	}
+
+	return dst
+}
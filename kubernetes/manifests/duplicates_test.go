@@ -0,0 +1,87 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+const duplicateManifests = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: default
+data:
+  a: "1"
+  b: "2"
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+  namespace: default
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: default
+  labels:
+    app: test
+data:
+  b: "3"
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+  namespace: default
+`
+
+func TestResolveDuplicates(t *testing.T) {
+	objects, err := manifests.Parse([]byte(duplicateManifests))
+	require.NoError(t, err)
+	require.Len(t, objects, 4)
+
+	_, err = manifests.ResolveDuplicates(objects, manifests.DuplicatePolicyError)
+	require.Error(t, err)
+
+	var duplicateErr *manifests.DuplicateObjectError
+
+	require.ErrorAs(t, err, &duplicateErr)
+	assert.Equal(t, []string{"v1.ConfigMap/default/cm"}, duplicateErr.Paths)
+
+	resolved, err := manifests.ResolveDuplicates(objects, manifests.DuplicatePolicyFirstWins)
+	require.NoError(t, err)
+	require.Len(t, resolved, 2)
+
+	assert.Equal(t, "cm", resolved[0].GetName())
+	assert.Empty(t, resolved[0].GetLabels())
+	assert.Equal(t, map[string]any{"a": "1", "b": "2"}, resolved[0].Object["data"])
+	assert.Equal(t, "secret", resolved[1].GetName())
+
+	resolved, err = manifests.ResolveDuplicates(objects, manifests.DuplicatePolicyLastWins)
+	require.NoError(t, err)
+	require.Len(t, resolved, 2)
+
+	assert.Equal(t, "cm", resolved[0].GetName())
+	assert.Equal(t, map[string]string{"app": "test"}, resolved[0].GetLabels())
+	assert.Equal(t, map[string]any{"b": "3"}, resolved[0].Object["data"])
+
+	resolved, err = manifests.ResolveDuplicates(objects, manifests.DuplicatePolicyMerge)
+	require.NoError(t, err)
+	require.Len(t, resolved, 2)
+
+	assert.Equal(t, map[string]string{"app": "test"}, resolved[0].GetLabels())
+	assert.Equal(t, map[string]any{"a": "1", "b": "3"}, resolved[0].Object["data"])
+
+	// the input is not modified
+	assert.Empty(t, objects[0].GetLabels())
+}
@@ -0,0 +1,69 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import "fmt"
+
+// SyncEventType is the type of the SyncEvent.
+type SyncEventType int
+
+// Sync event types.
+const (
+	// SyncEventStarted is sent before the manifests are applied.
+	SyncEventStarted SyncEventType = iota
+	// SyncEventManifestApplied is sent for each applied, unchanged or deleted manifest, Result is set.
+	SyncEventManifestApplied
+	// SyncEventRolloutStarted is sent before waiting for the rollout of the applied manifests.
+	SyncEventRolloutStarted
+	// SyncEventRolloutWaiting is sent when an object is not ready yet, Rollout is set.
+	SyncEventRolloutWaiting
+	// SyncEventRolloutFailed is sent when an object failed to become ready, Rollout is set with the diagnostics.
+	SyncEventRolloutFailed
+	// SyncEventRolloutComplete is sent when all applied manifests are rolled out.
+	SyncEventRolloutComplete
+)
+
+// SyncEvent describes the progress of SyncWithEvents.
+type SyncEvent struct {
+	Result  *SyncResult
+	Rollout *RolloutProgress
+	Type    SyncEventType
+	DryRun  bool
+}
+
+// Format returns the event as log lines, the same way SyncWithLog prints them.
+//
+// Some events (e.g. SyncEventRolloutComplete) are not logged, and nil is returned for them.
+func (e SyncEvent) Format() []string {
+	switch e.Type {
+	case SyncEventStarted:
+		return []string{"updating manifests"}
+	case SyncEventManifestApplied:
+		lines := []string{fmt.Sprintf(" > processing manifest %s", e.Result.Path)}
+
+		for _, warning := range e.Result.Warnings {
+			lines = append(lines, fmt.Sprintf(" ! warning: %s", warning))
+		}
+
+		switch {
+		case e.Result.Skipped:
+			return append(lines, " < no changes")
+		case e.Result.Deleted && !e.DryRun:
+			return append(lines, e.Result.Diff, " < deleted successfully")
+		case e.DryRun:
+			return append(lines, e.Result.Diff, " < dry run, change skipped")
+		default:
+			return append(lines, e.Result.Diff, " < applied successfully")
+		}
+	case SyncEventRolloutStarted:
+		return []string{"waiting for all manifests to be applied"}
+	case SyncEventRolloutWaiting:
+		return []string{fmt.Sprintf(" > waiting for %s: %s", e.Rollout.Path, e.Rollout.Status.Message)}
+	case SyncEventRolloutFailed:
+		return []string{fmt.Sprintf(" < %s failed to become ready: %s", e.Rollout.Path, e.Rollout.Status.Message)}
+	default:
+		return nil
+	}
+}
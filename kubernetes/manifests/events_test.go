@@ -0,0 +1,88 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+func TestSyncEventFormat(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		event manifests.SyncEvent
+
+		expected []string
+	}{
+		{
+			name:     "started",
+			event:    manifests.SyncEvent{Type: manifests.SyncEventStarted},
+			expected: []string{"updating manifests"},
+		},
+		{
+			name: "applied",
+			event: manifests.SyncEvent{
+				Type: manifests.SyncEventManifestApplied,
+				Result: &manifests.SyncResult{
+					Path:     "v1.ConfigMap/default/cm",
+					Diff:     "+data",
+					Warnings: []string{"deprecated"},
+				},
+			},
+			expected: []string{
+				" > processing manifest v1.ConfigMap/default/cm",
+				" ! warning: deprecated",
+				"+data",
+				" < applied successfully",
+			},
+		},
+		{
+			name: "skipped",
+			event: manifests.SyncEvent{
+				Type:   manifests.SyncEventManifestApplied,
+				Result: &manifests.SyncResult{Path: "v1.ConfigMap/default/cm", Skipped: true},
+			},
+			expected: []string{
+				" > processing manifest v1.ConfigMap/default/cm",
+				" < no changes",
+			},
+		},
+		{
+			name: "dry run deleted",
+			event: manifests.SyncEvent{
+				Type:   manifests.SyncEventManifestApplied,
+				Result: &manifests.SyncResult{Path: "v1.ConfigMap/default/cm", Diff: "-data", Deleted: true},
+				DryRun: true,
+			},
+			expected: []string{
+				" > processing manifest v1.ConfigMap/default/cm",
+				"-data",
+				" < dry run, change skipped",
+			},
+		},
+		{
+			name: "rollout waiting",
+			event: manifests.SyncEvent{
+				Type: manifests.SyncEventRolloutWaiting,
+				Rollout: &manifests.RolloutProgress{
+					Path:   "apps/v1.Deployment/default/app",
+					Status: manifests.StatusResult{Status: manifests.StatusInProgress, Message: "1 of 2 replicas are ready"},
+				},
+			},
+			expected: []string{" > waiting for apps/v1.Deployment/default/app: 1 of 2 replicas are ready"},
+		},
+		{
+			name:  "rollout complete",
+			event: manifests.SyncEvent{Type: manifests.SyncEventRolloutComplete},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.event.Format())
+		})
+	}
+}
@@ -0,0 +1,20 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+// ValidateWithClients runs Validate against the given clients.
+var ValidateWithClients = validate
+
+// UpdateManifest applies a single object using the given clients.
+var UpdateManifest = updateManifest
+
+// GroupByKindWeight splits the objects into the groups applied in order.
+var GroupByKindWeight = groupByKindWeight
+
+// SyncGroup applies a group of objects concurrently.
+var SyncGroup = syncGroup
+
+// LoadBalancerIngressStatus checks the Service load balancer ingress, as enabled by WithRolloutLoadBalancerIngress.
+var LoadBalancerIngressStatus = loadBalancerIngressStatus
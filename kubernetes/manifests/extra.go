@@ -0,0 +1,100 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ExtraManifests describes the extra manifests of the Talos machine config
+// (cluster.extraManifests, cluster.extraManifestHeaders and cluster.inlineManifests).
+type ExtraManifests struct {
+	// Headers are sent with each request to download URLs.
+	Headers map[string]string
+	URLs    []string
+	Inline  []InlineManifest
+}
+
+// InlineManifest is a named manifest embedded into the machine config.
+type InlineManifest struct {
+	Name     string
+	Contents string
+}
+
+// ExtraManifestsOptions configures loading the extra manifests.
+type ExtraManifestsOptions struct {
+	// MaxSize is the maximum size of a single downloaded manifest.
+	MaxSize int64
+	// DuplicatePolicy defines how the objects defined both in bootstrap and extra manifests are handled.
+	//
+	// The zero value is DuplicatePolicyError.
+	DuplicatePolicy DuplicatePolicy
+}
+
+// ExtraManifestsOption modifies ExtraManifestsOptions.
+type ExtraManifestsOption func(*ExtraManifestsOptions)
+
+// WithExtraManifestsMaxSize sets the maximum size of a single downloaded manifest.
+func WithExtraManifestsMaxSize(size int64) ExtraManifestsOption {
+	return func(o *ExtraManifestsOptions) {
+		o.MaxSize = size
+	}
+}
+
+// WithExtraManifestsDuplicatePolicy sets the policy for the objects defined more than once.
+//
+// By default, loading fails with DuplicateObjectError if the same object is defined with different content
+// (DuplicatePolicyError), unlike Sync which applies the last definition, so that an extra manifest doesn't
+// silently override a bootstrap manifest.
+func WithExtraManifestsDuplicatePolicy(policy DuplicatePolicy) ExtraManifestsOption {
+	return func(o *ExtraManifestsOptions) {
+		o.DuplicatePolicy = policy
+	}
+}
+
+// LoadExtraManifests downloads and parses the Talos extraManifests and inlineManifests, merging them with the bootstrap manifests.
+//
+// Extra manifest URLs (http or https) are fetched with the headers, and the checksum in the URL fragment
+// (e.g. #sha256=<hex>) is verified if present. The result contains the bootstrap manifests, followed by
+// the extra manifests in the order of URLs, and the inline manifests, with duplicates resolved according
+// to the duplicate policy (see WithExtraManifestsDuplicatePolicy).
+func LoadExtraManifests(ctx context.Context, extra ExtraManifests, bootstrap []Manifest, opts ...ExtraManifestsOption) ([]Manifest, error) {
+	options := ExtraManifestsOptions{
+		MaxSize: maxManifestSize,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	objects := slices.Clone(bootstrap)
+
+	for _, source := range extra.URLs {
+		if !strings.HasPrefix(source, "https://") && !strings.HasPrefix(source, "http://") {
+			return nil, fmt.Errorf("unsupported extra manifest URL %q", source)
+		}
+
+		loaded, err := loadURL(ctx, source, extra.Headers, options.MaxSize)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, loaded...)
+	}
+
+	for _, inline := range extra.Inline {
+		loaded, err := Parse([]byte(inline.Contents))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing inline manifest %q: %w", inline.Name, err)
+		}
+
+		objects = append(objects, loaded...)
+	}
+
+	return ResolveDuplicates(objects, options.DuplicatePolicy)
+}
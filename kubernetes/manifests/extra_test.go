@@ -0,0 +1,117 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/siderolabs/gen/xslices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+const extraManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: extra
+  namespace: default
+`
+
+func TestLoadExtraManifests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		w.Write([]byte(extraManifest)) //nolint:errcheck
+	}))
+	t.Cleanup(srv.Close)
+
+	sum := sha256.Sum256([]byte(extraManifest))
+
+	bootstrap, err := manifests.Parse([]byte(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: apps
+`))
+	require.NoError(t, err)
+
+	extra := manifests.ExtraManifests{
+		URLs:    []string{srv.URL + "/extra.yaml#sha256=" + hex.EncodeToString(sum[:])},
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		Inline: []manifests.InlineManifest{
+			{
+				Name: "inline",
+				Contents: `apiVersion: v1
+kind: Secret
+metadata:
+  name: inline
+  namespace: apps
+`,
+			},
+		},
+	}
+
+	objects, err := manifests.LoadExtraManifests(context.Background(), extra, bootstrap)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Namespace/apps", "ConfigMap/extra", "Secret/inline"}, xslices.Map(objects, func(obj manifests.Manifest) string {
+		return obj.GetKind() + "/" + obj.GetName()
+	}))
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		_, err := manifests.LoadExtraManifests(context.Background(), manifests.ExtraManifests{
+			URLs:    []string{srv.URL + "/extra.yaml#sha256=00"},
+			Headers: extra.Headers,
+		}, nil)
+		require.ErrorContains(t, err, "checksum mismatch")
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		_, err := manifests.LoadExtraManifests(context.Background(), manifests.ExtraManifests{
+			URLs:    []string{srv.URL + "/extra.yaml"},
+			Headers: extra.Headers,
+		}, nil, manifests.WithExtraManifestsMaxSize(16))
+		require.ErrorContains(t, err, "size limit")
+	})
+
+	t.Run("no headers", func(t *testing.T) {
+		_, err := manifests.LoadExtraManifests(context.Background(), manifests.ExtraManifests{
+			URLs: []string{srv.URL + "/extra.yaml"},
+		}, nil)
+		require.ErrorContains(t, err, "unexpected status")
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		_, err := manifests.LoadExtraManifests(context.Background(), manifests.ExtraManifests{
+			Inline: []manifests.InlineManifest{
+				{Name: "dup", Contents: extraManifest + "data:\n  a: b\n"},
+			},
+		}, []manifests.Manifest{objects[1]})
+
+		// the default policy is DuplicatePolicyError
+		var duplicateErr *manifests.DuplicateObjectError
+
+		require.ErrorAs(t, err, &duplicateErr)
+
+		resolved, err := manifests.LoadExtraManifests(context.Background(), manifests.ExtraManifests{
+			Inline: []manifests.InlineManifest{
+				{Name: "dup", Contents: extraManifest + "data:\n  a: b\n"},
+			},
+		}, []manifests.Manifest{objects[1]}, manifests.WithExtraManifestsDuplicatePolicy(manifests.DuplicatePolicyLastWins))
+		require.NoError(t, err)
+		require.Len(t, resolved, 1)
+		assert.Equal(t, map[string]any{"a": "b"}, resolved[0].Object["data"])
+	})
+}
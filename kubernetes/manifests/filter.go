@@ -0,0 +1,81 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Filter selects the manifests, it returns true for the manifests to keep.
+type Filter func(Manifest) bool
+
+// FilterByKind selects the manifests of any of the kinds.
+func FilterByKind(kinds ...string) Filter {
+	return func(obj Manifest) bool {
+		return slices.Contains(kinds, obj.GetKind())
+	}
+}
+
+// ExcludeKinds selects the manifests which are not of any of the kinds.
+func ExcludeKinds(kinds ...string) Filter {
+	return Not(FilterByKind(kinds...))
+}
+
+// FilterByNamespace selects the manifests in any of the namespaces.
+//
+// Cluster-scoped manifests have an empty namespace.
+func FilterByNamespace(namespaces ...string) Filter {
+	return func(obj Manifest) bool {
+		return slices.Contains(namespaces, obj.GetNamespace())
+	}
+}
+
+// FilterByLabelSelector selects the manifests matching the label selector (e.g. `app=foo,tier!=db`).
+func FilterByLabelSelector(selector string) (Filter, error) {
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing label selector %q: %w", selector, err)
+	}
+
+	return func(obj Manifest) bool {
+		return sel.Matches(labels.Set(obj.GetLabels()))
+	}, nil
+}
+
+// And selects the manifests matching all of the filters.
+func And(filters ...Filter) Filter {
+	return func(obj Manifest) bool {
+		for _, filter := range filters {
+			if !filter(obj) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Or selects the manifests matching any of the filters.
+func Or(filters ...Filter) Filter {
+	return func(obj Manifest) bool {
+		for _, filter := range filters {
+			if filter(obj) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not selects the manifests not matching the filter.
+func Not(filter Filter) Filter {
+	return func(obj Manifest) bool {
+		return !filter(obj)
+	}
+}
@@ -0,0 +1,107 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"testing"
+
+	"github.com/siderolabs/gen/xslices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+const filterManifests = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: apps
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: apps
+  labels:
+    app: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: apps
+  labels:
+    app: web
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: kube-proxy
+  namespace: kube-system
+  labels:
+    k8s-app: kube-proxy
+`
+
+func TestFilters(t *testing.T) {
+	objects, err := manifests.Parse([]byte(filterManifests))
+	require.NoError(t, err)
+
+	byLabel, err := manifests.FilterByLabelSelector("app=web")
+	require.NoError(t, err)
+
+	_, err = manifests.FilterByLabelSelector("app=(")
+	require.Error(t, err)
+
+	for _, test := range []struct {
+		name   string
+		filter manifests.Filter
+
+		expected []string
+	}{
+		{
+			name:     "kind",
+			filter:   manifests.FilterByKind("Deployment", "DaemonSet"),
+			expected: []string{"Deployment/web", "DaemonSet/kube-proxy"},
+		},
+		{
+			name:     "exclude kinds",
+			filter:   manifests.ExcludeKinds("Namespace", "Service"),
+			expected: []string{"Deployment/web", "DaemonSet/kube-proxy"},
+		},
+		{
+			name:     "namespace",
+			filter:   manifests.FilterByNamespace("kube-system", ""),
+			expected: []string{"Namespace/apps", "DaemonSet/kube-proxy"},
+		},
+		{
+			name:     "label selector",
+			filter:   byLabel,
+			expected: []string{"Deployment/web", "Service/web"},
+		},
+		{
+			name:     "and",
+			filter:   manifests.And(byLabel, manifests.FilterByKind("Service")),
+			expected: []string{"Service/web"},
+		},
+		{
+			name:     "or",
+			filter:   manifests.Or(byLabel, manifests.FilterByNamespace("kube-system")),
+			expected: []string{"Deployment/web", "Service/web", "DaemonSet/kube-proxy"},
+		},
+		{
+			name:     "not",
+			filter:   manifests.Not(manifests.FilterByNamespace("apps")),
+			expected: []string{"Namespace/apps", "DaemonSet/kube-proxy"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			filtered := xslices.Filter(objects, test.filter)
+
+			assert.Equal(t, test.expected, xslices.Map(filtered, func(obj manifests.Manifest) string {
+				return obj.GetKind() + "/" + obj.GetName()
+			}))
+		})
+	}
+}
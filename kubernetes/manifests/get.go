@@ -6,16 +6,23 @@ package manifests
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/safe"
 	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/siderolabs/talos/pkg/machinery/client"
 	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 // GetBootstrapManifests fetches the bootstrap manifests from the cluster.
-func GetBootstrapManifests(ctx context.Context, st state.State, filter func(Manifest) bool) ([]Manifest, error) {
+//
+// If filter is not nil, only the manifests matching the filter are returned, see FilterByKind and other filter constructors.
+func GetBootstrapManifests(ctx context.Context, st state.State, filter Filter) ([]Manifest, error) {
 	items, err := safe.StateList[*k8s.Manifest](ctx, st, resource.NewMetadata(k8s.ControlPlaneNamespaceName, k8s.ManifestType, "", resource.VersionUndefined))
 	if err != nil {
 		return nil, err
@@ -37,3 +44,123 @@ func GetBootstrapManifests(ctx context.Context, st state.State, filter func(Mani
 
 	return objects, nil
 }
+
+// ManifestDivergence describes an object which is not the same on all nodes.
+type ManifestDivergence struct {
+	Path string
+	// Missing lists the nodes which don't have the object.
+	Missing []string
+	// Differing lists the nodes which have the object with the content different from the first node having it.
+	Differing []string
+}
+
+// ManifestsDivergenceError is returned when the bootstrap manifests differ between the nodes.
+type ManifestsDivergenceError struct {
+	Divergences []ManifestDivergence
+}
+
+// Error implements error interface.
+func (e *ManifestsDivergenceError) Error() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "bootstrap manifests differ between nodes for %d object(s):", len(e.Divergences))
+
+	for _, d := range e.Divergences {
+		fmt.Fprintf(&sb, "\n  %s:", d.Path)
+
+		if len(d.Missing) > 0 {
+			fmt.Fprintf(&sb, " missing on %s", strings.Join(d.Missing, ", "))
+		}
+
+		if len(d.Differing) > 0 {
+			if len(d.Missing) > 0 {
+				sb.WriteString(";")
+			}
+
+			fmt.Fprintf(&sb, " differs on %s", strings.Join(d.Differing, ", "))
+		}
+	}
+
+	return sb.String()
+}
+
+// GetBootstrapManifestsFromNodes fetches the bootstrap manifests from each of the control plane nodes and verifies that they agree.
+//
+// Each node is queried via the Talos API node proxying (see client.WithNode), so st should be backed by the Talos API client.
+// If the manifests differ between the nodes, ManifestsDivergenceError is returned listing the objects which differ.
+// Otherwise, the manifests are returned deduplicated by group, kind, namespace and name.
+func GetBootstrapManifestsFromNodes(ctx context.Context, st state.State, nodes []string, filter Filter) ([]Manifest, error) {
+	if len(nodes) == 0 {
+		return nil, errors.New("no nodes specified")
+	}
+
+	nodeObjects := make([]map[string]Manifest, len(nodes))
+
+	var (
+		objects []Manifest
+		keys    []string
+	)
+
+	seen := map[string]struct{}{}
+
+	for i, node := range nodes {
+		items, err := GetBootstrapManifests(client.WithNode(ctx, node), st, filter)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching bootstrap manifests from node %q: %w", node, err)
+		}
+
+		nodeObjects[i] = make(map[string]Manifest, len(items))
+
+		for _, obj := range items {
+			key := objectKey(obj)
+
+			if _, ok := nodeObjects[i][key]; ok {
+				continue
+			}
+
+			nodeObjects[i][key] = obj
+
+			if i == 0 {
+				objects = append(objects, obj)
+			}
+
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	var divergences []ManifestDivergence
+
+	for _, key := range keys {
+		var (
+			reference  Manifest
+			divergence ManifestDivergence
+		)
+
+		for i, node := range nodes {
+			obj, ok := nodeObjects[i][key]
+
+			switch {
+			case !ok:
+				divergence.Missing = append(divergence.Missing, node)
+			case reference == nil:
+				reference = obj
+				divergence.Path = manifestPath(obj)
+			case !equality.Semantic.DeepEqual(reference.Object, obj.Object):
+				divergence.Differing = append(divergence.Differing, node)
+			}
+		}
+
+		if len(divergence.Missing) > 0 || len(divergence.Differing) > 0 {
+			divergences = append(divergences, divergence)
+		}
+	}
+
+	if len(divergences) > 0 {
+		return nil, &ManifestsDivergenceError{Divergences: divergences}
+	}
+
+	return objects, nil
+}
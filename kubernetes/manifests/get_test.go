@@ -0,0 +1,149 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+// nodeState routes the requests to the state of the node set with client.WithNode, as the Talos API proxying does.
+type nodeState struct {
+	state.CoreState
+
+	nodes map[string]state.CoreState
+}
+
+func (st *nodeState) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+
+	if node := md.Get("node"); len(node) == 1 {
+		if nodeSt, ok := st.nodes[node[0]]; ok {
+			return nodeSt.List(ctx, kind, opts...)
+		}
+	}
+
+	return st.CoreState.List(ctx, kind, opts...)
+}
+
+func bootstrapManifest(t *testing.T, st state.State, id string, objects ...map[string]any) {
+	manifest := k8s.NewManifest(k8s.ControlPlaneNamespaceName, id)
+
+	for _, obj := range objects {
+		manifest.TypedSpec().Items = append(manifest.TypedSpec().Items, k8s.SingleManifest{Object: obj})
+	}
+
+	require.NoError(t, st.Create(context.Background(), manifest))
+}
+
+func configMapObject(name, value string) map[string]any {
+	return map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": "kube-system",
+		},
+		"data": map[string]any{
+			"value": value,
+		},
+	}
+}
+
+func TestGetBootstrapManifestsFromNodes(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		nodes map[string][]map[string]any
+		query []string
+
+		expectedNames       []string
+		expectedDivergences []manifests.ManifestDivergence
+	}{
+		{
+			name: "identical",
+			nodes: map[string][]map[string]any{
+				"cp-1": {configMapObject("a", "1"), configMapObject("b", "2")},
+				"cp-2": {configMapObject("a", "1"), configMapObject("b", "2")},
+			},
+			query: []string{"cp-1", "cp-2"},
+
+			expectedNames: []string{"a", "b"},
+		},
+		{
+			name: "diverging",
+			nodes: map[string][]map[string]any{
+				"cp-1": {configMapObject("a", "1"), configMapObject("b", "2")},
+				"cp-2": {configMapObject("a", "1"), configMapObject("b", "3")},
+				"cp-3": {configMapObject("a", "1")},
+			},
+			query: []string{"cp-1", "cp-2", "cp-3"},
+
+			expectedDivergences: []manifests.ManifestDivergence{
+				{Path: "v1.ConfigMap/kube-system/b", Missing: []string{"cp-3"}, Differing: []string{"cp-2"}},
+			},
+		},
+		{
+			name: "missing node",
+			nodes: map[string][]map[string]any{
+				"cp-1": {configMapObject("a", "1")},
+			},
+			query: []string{"cp-1", "cp-2"},
+
+			expectedDivergences: []manifests.ManifestDivergence{
+				{Path: "v1.ConfigMap/kube-system/a", Missing: []string{"cp-2"}},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, ctxCancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer ctxCancel()
+
+			st := &nodeState{
+				CoreState: namespaced.NewState(inmem.Build),
+				nodes:     map[string]state.CoreState{},
+			}
+
+			for node, objects := range test.nodes {
+				nodeSt := namespaced.NewState(inmem.Build)
+				st.nodes[node] = nodeSt
+
+				bootstrapManifest(t, state.WrapCore(nodeSt), "00-configmaps", objects...)
+			}
+
+			objects, err := manifests.GetBootstrapManifestsFromNodes(ctx, state.WrapCore(st), test.query, nil)
+
+			if test.expectedDivergences != nil {
+				var divergenceErr *manifests.ManifestsDivergenceError
+
+				require.ErrorAs(t, err, &divergenceErr)
+				assert.Equal(t, test.expectedDivergences, divergenceErr.Divergences)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(objects))
+
+			for _, obj := range objects {
+				names = append(names, obj.GetName())
+			}
+
+			assert.Equal(t, test.expectedNames, names)
+		})
+	}
+}
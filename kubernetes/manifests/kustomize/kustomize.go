@@ -0,0 +1,81 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package kustomize builds kustomizations into manifests which can be synced to the cluster.
+package kustomize
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+// BuildDir runs the kustomization found in the directory on the local filesystem.
+func BuildDir(dir string) ([]manifests.Manifest, error) {
+	return build(filesys.MakeFsOnDisk(), dir)
+}
+
+// BuildFS runs the kustomization found in the directory dir of fsys.
+//
+// The contents of fsys are copied into memory before the build, so dir and all
+// the resources it references should be part of fsys.
+func BuildFS(fsys fs.FS, dir string) ([]manifests.Manifest, error) {
+	memFS := filesys.MakeFsInMemory()
+
+	if err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// fs.FS and the in-memory filesystem both use slash-separated paths regardless of the OS
+		if d.IsDir() {
+			return memFS.MkdirAll(path.Join("/", name))
+		}
+
+		contents, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		return memFS.WriteFile(path.Join("/", name), contents)
+	}); err != nil {
+		return nil, fmt.Errorf("error copying kustomization: %w", err)
+	}
+
+	return build(memFS, path.Join("/", dir))
+}
+
+func build(fSys filesys.FileSystem, dir string) ([]manifests.Manifest, error) {
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fSys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("error building kustomization %q: %w", dir, err)
+	}
+
+	resources := resMap.Resources()
+	objects := make([]manifests.Manifest, 0, len(resources))
+
+	for _, res := range resources {
+		// go through JSON, as res.Map() returns plain int values which can't be deep copied
+		data, err := res.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("error converting resource %s: %w", res.CurId(), err)
+		}
+
+		obj := &unstructured.Unstructured{}
+
+		if err = obj.UnmarshalJSON(data); err != nil {
+			return nil, fmt.Errorf("error converting resource %s: %w", res.CurId(), err)
+		}
+
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,81 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kustomize_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests/kustomize"
+)
+
+func TestBuildFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"base/kustomization.yaml": &fstest.MapFile{
+			Data: []byte(`resources:
+- deployment.yaml
+`),
+		},
+		"base/deployment.yaml": &fstest.MapFile{
+			Data: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1
+        ports:
+        - containerPort: 8080
+`),
+		},
+		"overlay/kustomization.yaml": &fstest.MapFile{
+			Data: []byte(`namespace: apps
+namePrefix: prod-
+resources:
+- ../base
+- namespace.yaml
+`),
+		},
+		"overlay/namespace.yaml": &fstest.MapFile{
+			Data: []byte(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: apps
+`),
+		},
+	}
+
+	objects, err := kustomize.BuildFS(fsys, "overlay")
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+
+	assert.Equal(t, "Deployment", objects[0].GetKind())
+	assert.Equal(t, "apps", objects[0].GetNamespace())
+	assert.Equal(t, "prod-app", objects[0].GetName())
+
+	// numeric fields should be deep-copyable (int64)
+	deployment := objects[0].DeepCopy()
+
+	replicas, found, err := unstructured.NestedInt64(deployment.Object, "spec", "replicas")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.EqualValues(t, 2, replicas)
+
+	assert.Equal(t, "Namespace", objects[1].GetKind())
+	assert.Equal(t, "apps", objects[1].GetName())
+}
+
+func TestBuildFSMissing(t *testing.T) {
+	_, err := kustomize.BuildFS(fstest.MapFS{}, "overlay")
+	require.Error(t, err)
+}
@@ -0,0 +1,259 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// maxManifestSize is the maximum size of a single manifest source.
+const maxManifestSize = 32 * 1024 * 1024
+
+// Load reads manifests from the sources.
+//
+// Each source might be:
+//   - a path to a file;
+//   - a path to a directory, which is walked recursively loading files with .yaml, .yml and .json extensions in lexical order;
+//   - a glob pattern as supported by filepath.Glob;
+//   - an HTTPS URL, optionally with the expected checksum in the fragment: https://example.com/manifest.yaml#sha256=<hex>.
+//
+// Manifests are returned in the order of the sources.
+func Load(ctx context.Context, sources ...string) ([]Manifest, error) {
+	var objects []Manifest
+
+	for _, source := range sources {
+		var (
+			loaded []Manifest
+			err    error
+		)
+
+		switch {
+		case strings.HasPrefix(source, "https://"):
+			loaded, err = loadURL(ctx, source, nil, maxManifestSize)
+		case strings.HasPrefix(source, "http://"):
+			err = fmt.Errorf("insecure URL %q, only https is supported", source)
+		case strings.ContainsAny(source, "*?["):
+			loaded, err = loadGlob(source)
+		default:
+			loaded, err = loadPath(source)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, loaded...)
+	}
+
+	return objects, nil
+}
+
+func loadGlob(pattern string) ([]Manifest, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("error expanding glob %q: %w", pattern, err)
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no files match %q", pattern)
+	}
+
+	// filepath.Glob returns matches in lexical order
+	var objects []Manifest
+
+	for _, match := range matches {
+		loaded, err := loadPath(match)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, loaded...)
+	}
+
+	return objects, nil
+}
+
+func loadPath(path string) ([]Manifest, error) {
+	st, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !st.IsDir() {
+		return loadFile(path)
+	}
+
+	var files []string
+
+	if err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && isManifestFile(p) {
+			files = append(files, p)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("error walking directory %q: %w", path, err)
+	}
+
+	slices.Sort(files)
+
+	var objects []Manifest
+
+	for _, file := range files {
+		loaded, err := loadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, loaded...)
+	}
+
+	return objects, nil
+}
+
+func isManifestFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+func loadFile(path string) ([]Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	data, err := readLimited(f, maxManifestSize)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q: %w", path, err)
+	}
+
+	objects, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %q: %w", path, err)
+	}
+
+	return objects, nil
+}
+
+func loadURL(ctx context.Context, source string, headers map[string]string, maxSize int64) ([]Manifest, error) {
+	data, err := fetchURL(ctx, source, headers, maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %q: %w", source, err)
+	}
+
+	return objects, nil
+}
+
+// fetchURL downloads the contents of the URL verifying the checksum from the URL fragment (if any).
+func fetchURL(ctx context.Context, source string, headers map[string]string, maxSize int64) ([]byte, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing URL %q: %w", source, err)
+	}
+
+	checksum := u.Fragment
+	u.Fragment = ""
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %q: %w", u, err)
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching %q: unexpected status %s", u, resp.Status)
+	}
+
+	data, err := readLimited(resp.Body, maxSize)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %q: %w", u, err)
+	}
+
+	if checksum != "" {
+		if err = verifyChecksum(data, checksum); err != nil {
+			return nil, fmt.Errorf("error verifying %q: %w", u, err)
+		}
+	}
+
+	return data, nil
+}
+
+func readLimited(r io.Reader, maxSize int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("manifest exceeds the size limit of %d bytes", maxSize)
+	}
+
+	return data, nil
+}
+
+// verifyChecksum verifies the data against the checksum in the form of <algorithm>=<hex>.
+func verifyChecksum(data []byte, checksum string) error {
+	algorithm, expected, ok := strings.Cut(checksum, "=")
+	if !ok {
+		return fmt.Errorf("invalid checksum %q, expected <algorithm>=<hex>", checksum)
+	}
+
+	var h hash.Hash
+
+	switch algorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported checksum algorithm %q", algorithm)
+	}
+
+	h.Write(data) //nolint:errcheck
+
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", expected, actual)
+	}
+
+	return nil
+}
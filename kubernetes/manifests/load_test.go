@@ -0,0 +1,59 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/siderolabs/gen/xslices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile := func(path, name string) {
+		path = filepath.Join(dir, path)
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: "+name+"\n"), 0o644))
+	}
+
+	writeFile("single.yaml", "single")
+	writeFile("dir/b.yaml", "b")
+	writeFile("dir/a.yml", "a")
+	writeFile("dir/nested/c.json", "c")
+	writeFile("dir/nested/README.md", "ignored")
+	writeFile("glob/y.yaml", "glob-y")
+	writeFile("glob/x.yaml", "glob-x")
+
+	objects, err := manifests.Load(context.Background(),
+		filepath.Join(dir, "single.yaml"),
+		filepath.Join(dir, "dir"),
+		filepath.Join(dir, "glob", "*.yaml"),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"single", "a", "b", "c", "glob-x", "glob-y"}, xslices.Map(objects, func(m manifests.Manifest) string { return m.GetName() }))
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := manifests.Load(context.Background(), filepath.Join(dir, "missing.yaml"))
+	require.Error(t, err)
+
+	_, err = manifests.Load(context.Background(), filepath.Join(dir, "*.yaml"))
+	require.ErrorContains(t, err, "no files match")
+
+	_, err = manifests.Load(context.Background(), "http://example.com/manifest.yaml")
+	require.ErrorContains(t, err, "insecure URL")
+}
@@ -0,0 +1,133 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/siderolabs/go-retry/retry"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes"
+)
+
+// NamespaceTerminatingError is returned when the manifests target namespaces which are being terminated.
+type NamespaceTerminatingError struct {
+	Namespaces []string
+}
+
+// Error implements error interface.
+func (e *NamespaceTerminatingError) Error() string {
+	return fmt.Sprintf("namespaces are being terminated, manifests can't be applied: %s", strings.Join(e.Namespaces, ", "))
+}
+
+// targetNamespaces returns the sorted list of namespaces the objects are applied to (including Namespace objects).
+func targetNamespaces(objects []Manifest) []string {
+	var namespaces []string
+
+	for _, obj := range objects {
+		switch {
+		case obj.GetKind() == "Namespace" && obj.GroupVersionKind().Group == "":
+			namespaces = append(namespaces, obj.GetName())
+		case obj.GetNamespace() != "":
+			namespaces = append(namespaces, obj.GetNamespace())
+		}
+	}
+
+	slices.Sort(namespaces)
+
+	return slices.Compact(namespaces)
+}
+
+// terminatingNamespaces returns the namespaces which are being terminated.
+func terminatingNamespaces(ctx context.Context, k8sClient dynamic.Interface, namespaces []string) ([]string, error) {
+	var terminating []string
+
+	for _, namespace := range namespaces {
+		ns, err := k8sClient.Resource(v1.SchemeGroupVersion.WithResource("namespaces")).Get(ctx, namespace, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		phase, _, _ := unstructured.NestedString(ns.Object, "status", "phase")
+
+		if phase == string(v1.NamespaceTerminating) || ns.GetDeletionTimestamp() != nil {
+			terminating = append(terminating, namespace)
+		}
+	}
+
+	return terminating, nil
+}
+
+// checkNamespaces verifies that none of the target namespaces is being terminated.
+//
+// If timeout is non-zero, it waits for the terminating namespaces to be removed.
+func checkNamespaces(ctx context.Context, k8sClient dynamic.Interface, objects []Manifest, timeout time.Duration) error {
+	namespaces := targetNamespaces(objects)
+
+	var terminating []string
+
+	// the API server might not be ready yet (e.g. during the bootstrap), so retry the lookup
+	err := retry.Constant(3*time.Minute, retry.WithUnits(time.Second)).RetryWithContext(ctx, func(ctx context.Context) error {
+		var err error
+
+		terminating, err = terminatingNamespaces(ctx, k8sClient, namespaces)
+
+		return retryableLookupError(err)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(terminating) == 0 {
+		return nil
+	}
+
+	if timeout == 0 {
+		return &NamespaceTerminatingError{Namespaces: terminating}
+	}
+
+	err = retry.Constant(timeout, retry.WithUnits(time.Second)).RetryWithContext(ctx, func(ctx context.Context) error {
+		// keep the previous result on lookup errors, so that a transient error doesn't clear the list
+		stillTerminating, err := terminatingNamespaces(ctx, k8sClient, terminating)
+		if err != nil {
+			return retryableLookupError(err)
+		}
+
+		terminating = stillTerminating
+
+		if len(terminating) > 0 {
+			return retry.ExpectedError(&NamespaceTerminatingError{Namespaces: terminating})
+		}
+
+		return nil
+	})
+	if err != nil && len(terminating) > 0 {
+		return &NamespaceTerminatingError{Namespaces: terminating}
+	}
+
+	return err
+}
+
+// retryableLookupError marks the namespace lookup errors which are expected while the API server is not ready as retryable.
+func retryableLookupError(err error) error {
+	if err != nil && (kubernetes.IsRetryableError(err) || apierrors.IsServiceUnavailable(err)) {
+		return retry.ExpectedError(err)
+	}
+
+	return err
+}
@@ -0,0 +1,132 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+const namespacedConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: apps
+data:
+  key: value
+`
+
+const terminatingNamespace = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: apps
+status:
+  phase: Terminating
+`
+
+func TestSyncNamespaceTermination(t *testing.T) {
+	const namespacePath = "/api/v1/namespaces/apps"
+
+	for _, test := range []struct {
+		name string
+
+		timeout time.Duration
+		// removeAfter is the number of namespace lookups after which the namespace is removed, 0 means never
+		removeAfter int32
+
+		expectedError bool
+	}{
+		{
+			name: "no wait",
+
+			expectedError: true,
+		},
+		{
+			name:        "terminated",
+			timeout:     time.Minute,
+			removeAfter: 2,
+		},
+		{
+			name:    "timeout",
+			timeout: 2 * time.Second,
+
+			expectedError: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, ctxCancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer ctxCancel()
+
+			fake, config := newFakeAPIServer(t, parseManifests(t, terminatingNamespace)...)
+
+			var lookups atomic.Int32
+
+			fake.intercept = func(_ http.ResponseWriter, r *http.Request) bool {
+				if r.Method == http.MethodGet && r.URL.Path == namespacePath {
+					if lookups.Add(1) == test.removeAfter {
+						fake.mu.Lock()
+						delete(fake.objects, namespacePath)
+						fake.mu.Unlock()
+					}
+				}
+
+				return false
+			}
+
+			results, err := syncObjects(ctx, config, parseManifests(t, namespacedConfigMap), false,
+				manifests.WithNamespaceTerminationTimeout(test.timeout))
+
+			if test.expectedError {
+				var terminatingErr *manifests.NamespaceTerminatingError
+
+				require.ErrorAs(t, err, &terminatingErr)
+				assert.Equal(t, []string{"apps"}, terminatingErr.Namespaces)
+				assert.Empty(t, results)
+				assert.Nil(t, fake.get("/api/v1/namespaces/apps/configmaps/config"))
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.NotNil(t, fake.get("/api/v1/namespaces/apps/configmaps/config"))
+		})
+	}
+}
+
+func TestSyncNamespaceLookupRetry(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer ctxCancel()
+
+	fake, config := newFakeAPIServer(t)
+
+	var unavailable atomic.Bool
+
+	fake.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method == http.MethodGet && r.URL.Path == "/api/v1/namespaces/apps" && !unavailable.Swap(true) {
+			writeStatus(w, http.StatusServiceUnavailable, metav1.StatusReasonServiceUnavailable)
+
+			return true
+		}
+
+		return false
+	}
+
+	results, err := syncObjects(ctx, config, parseManifests(t, namespacedConfigMap), false)
+	require.NoError(t, err)
+
+	assert.True(t, unavailable.Load())
+	require.Len(t, results, 1)
+	assert.NotNil(t, fake.get("/api/v1/namespaces/apps/configmaps/config"))
+}
@@ -0,0 +1,67 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Parse decodes a multi-document YAML (or JSON) stream into manifests.
+//
+// Empty documents are skipped, and List objects are flattened into their items.
+func Parse(data []byte) ([]Manifest, error) {
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+
+	var objects []Manifest
+
+	for {
+		doc, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+
+			return nil, fmt.Errorf("error reading manifest: %w", err)
+		}
+
+		jsonDoc, err := yaml.ToJSON(doc)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding manifest: %w", err)
+		}
+
+		if len(bytes.TrimSpace(jsonDoc)) == 0 || bytes.Equal(jsonDoc, []byte("null")) {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+
+		// unstructured decoding keeps integers as int64 (unlike plain JSON decoding)
+		if err = obj.UnmarshalJSON(jsonDoc); err != nil {
+			return nil, fmt.Errorf("error decoding manifest: %w", err)
+		}
+
+		if !obj.IsList() {
+			objects = append(objects, obj)
+
+			continue
+		}
+
+		if err = obj.EachListItem(func(item runtime.Object) error {
+			objects = append(objects, item.(Manifest)) //nolint:forcetypeassert
+
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("error decoding list manifest: %w", err)
+		}
+	}
+}
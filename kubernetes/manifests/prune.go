@@ -0,0 +1,132 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/siderolabs/gen/channel"
+	"github.com/siderolabs/go-retry/retry"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes"
+)
+
+// objectKey identifies the object regardless of its API version.
+func objectKey(obj Manifest) string {
+	gk := obj.GroupVersionKind().GroupKind()
+
+	return gk.String() + "/" + obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// prunedObjects returns the previous objects which are not part of the current set, in the deletion order.
+//
+// If allowedKinds is not empty, only the objects of these kinds are returned.
+func prunedObjects(current, previous []Manifest, allowedKinds []schema.GroupKind, weights map[string]int) []Manifest {
+	keep := make(map[string]struct{}, len(current))
+
+	for _, obj := range current {
+		keep[objectKey(obj)] = struct{}{}
+	}
+
+	var pruned []Manifest
+
+	for _, obj := range previous {
+		if _, ok := keep[objectKey(obj)]; ok {
+			continue
+		}
+
+		if len(allowedKinds) > 0 && !slices.Contains(allowedKinds, obj.GroupVersionKind().GroupKind()) {
+			continue
+		}
+
+		pruned = append(pruned, obj)
+	}
+
+	return SortObjectsForDeletion(pruned, weights)
+}
+
+func pruneObjects(
+	ctx context.Context,
+	mapper meta.RESTMapper,
+	k8sClient dynamic.Interface,
+	objects []Manifest,
+	dryRun bool,
+	resultCh chan<- SyncResult,
+) error {
+	for _, obj := range objects {
+		var diff string
+
+		if err := retry.Constant(3*time.Minute, retry.WithUnits(10*time.Second), retry.WithErrorLogging(true)).RetryWithContext(ctx, func(ctx context.Context) error {
+			var err error
+
+			diff, err = deleteManifest(ctx, mapper, k8sClient, obj, dryRun)
+			if kubernetes.IsRetryableError(err) {
+				return retry.ExpectedError(err)
+			}
+
+			return err
+		}); err != nil {
+			return err
+		}
+
+		if diff == "" {
+			// object is already gone
+			continue
+		}
+
+		if !channel.SendWithContext(ctx, resultCh, SyncResult{
+			Path:    manifestPath(obj),
+			Object:  obj,
+			Diff:    diff,
+			Deleted: true,
+		}) {
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// deleteManifest deletes the object returning the diff, the diff is empty if the object doesn't exist.
+func deleteManifest(ctx context.Context, mapper meta.RESTMapper, k8sClient dynamic.Interface, obj Manifest, dryRun bool) (string, error) {
+	dr, err := resourceClient(mapper, k8sClient, obj)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			// the resource type is not served anymore
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	current, err := dr.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	diff, err := manifestDiff(current, nil)
+	if err != nil || dryRun {
+		return diff, err
+	}
+
+	propagationPolicy := metav1.DeletePropagationBackground
+
+	if err = dr.Delete(ctx, obj.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}); err != nil && !apierrors.IsNotFound(err) {
+		return "", err
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,145 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/siderolabs/gen/xslices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+const previousManifests = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: old
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: current
+  namespace: default
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: old
+  namespace: default
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: old
+  namespace: default
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: gone
+  namespace: default
+`
+
+const currentManifests = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: current
+  namespace: default
+`
+
+func TestSyncPrune(t *testing.T) {
+	for _, test := range []struct {
+		name string
+
+		allowedKinds []schema.GroupKind
+		dryRun       bool
+
+		expectedDeleted []string
+	}{
+		{
+			name: "all kinds",
+
+			// reverse install order, objects already removed are skipped
+			expectedDeleted: []string{
+				"/api/v1/namespaces/default/secrets/old",
+				"/api/v1/namespaces/default/configmaps/old",
+				"/api/v1/namespaces/old",
+			},
+		},
+		{
+			name:         "allowed kinds",
+			allowedKinds: []schema.GroupKind{{Kind: "ConfigMap"}},
+
+			expectedDeleted: []string{
+				"/api/v1/namespaces/default/configmaps/old",
+			},
+		},
+		{
+			name:         "dry run",
+			allowedKinds: []schema.GroupKind{{Kind: "ConfigMap"}},
+			dryRun:       true,
+
+			expectedDeleted: []string{
+				"/api/v1/namespaces/default/configmaps/old",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, ctxCancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer ctxCancel()
+
+			previous := parseManifests(t, previousManifests)
+
+			// the last object is already removed from the cluster
+			fake, config := newFakeAPIServer(t, previous[:len(previous)-1]...)
+
+			results, err := syncObjects(ctx, config, parseManifests(t, currentManifests), test.dryRun,
+				manifests.WithPrune(previous), manifests.WithPruneAllowedKinds(test.allowedKinds...))
+			require.NoError(t, err)
+
+			// the first result is the applied object
+			require.Len(t, results, len(test.expectedDeleted)+1)
+			assert.False(t, results[0].Deleted)
+			assert.Equal(t, "current", results[0].Object.GetName())
+
+			for _, result := range results[1:] {
+				assert.True(t, result.Deleted)
+				assert.NotEmpty(t, result.Diff)
+			}
+
+			deletes := xslices.Filter(fake.recorded(), func(req fakeRequest) bool { return req.Method == http.MethodDelete })
+
+			if test.dryRun {
+				assert.Equal(t, "v1.ConfigMap/default/old", results[1].Path)
+				assert.Empty(t, deletes)
+				assert.NotNil(t, fake.get("/api/v1/namespaces/default/configmaps/old"))
+
+				return
+			}
+
+			assert.Equal(t, test.expectedDeleted, xslices.Map(deletes, func(req fakeRequest) string { return req.Path }))
+
+			for _, req := range deletes {
+				var opts metav1.DeleteOptions
+
+				require.NoError(t, json.Unmarshal([]byte(req.Body), &opts))
+				require.NotNil(t, opts.PropagationPolicy)
+				assert.Equal(t, metav1.DeletePropagationBackground, *opts.PropagationPolicy)
+
+				assert.Nil(t, fake.get(req.Path))
+			}
+
+			assert.NotNil(t, fake.get("/api/v1/namespaces/default/configmaps/current"))
+		})
+	}
+}
@@ -6,80 +6,151 @@ package manifests
 
 import (
 	"context"
+	"fmt"
+	"maps"
 	"time"
 
 	"github.com/siderolabs/gen/channel"
 	"github.com/siderolabs/go-retry/retry"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	memory "k8s.io/client-go/discovery/cached"
+	"k8s.io/client-go/dynamic"
+	k8s "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 
 	"github.com/siderolabs/go-kubernetes/kubernetes"
 )
 
 // RolloutProgress indicates the current manifest rollout progress.
+//
+// Diagnostics are only set when the object failed to become ready.
 type RolloutProgress struct {
-	Object Manifest
-	Path   string
+	Object      Manifest
+	Diagnostics *RolloutDiagnostics
+	Path        string
+	Status      StatusResult
 }
 
-// WaitForRollout waits for the manifest rollout to be complete.
-func WaitForRollout(ctx context.Context, config *rest.Config, objects []Manifest, resultCh chan<- RolloutProgress) error {
-	var deployments, daemonsets []Manifest
-
-	for _, object := range objects {
-		switch {
-		case object.GetKind() == "Deployment" && object.GroupVersionKind().Group == "apps":
-			deployments = append(deployments, object)
-		case object.GetKind() == "DaemonSet" && object.GroupVersionKind().Group == "apps":
-			daemonsets = append(daemonsets, object)
+// RolloutOptions configures waiting for the manifest rollout.
+type RolloutOptions struct {
+	// KindTimeouts overrides the DefaultTimeout for specific kinds.
+	KindTimeouts map[schema.GroupKind]time.Duration
+	// DefaultTimeout is the time to wait for a single object to become ready.
+	DefaultTimeout time.Duration
+	// PollInterval is the interval between object readiness checks.
+	PollInterval time.Duration
+	// Deadline limits the whole rollout wait, zero means no limit.
+	Deadline time.Duration
+	// LoadBalancerIngress requires Services of type LoadBalancer to have an ingress IP or hostname assigned.
+	LoadBalancerIngress bool
+}
+
+// DefaultRolloutOptions returns the default rollout options.
+func DefaultRolloutOptions() RolloutOptions {
+	return RolloutOptions{
+		KindTimeouts: map[schema.GroupKind]time.Duration{
+			{Group: "apps", Kind: "DaemonSet"}: 5 * time.Minute,
+		},
+		DefaultTimeout: 3 * time.Minute,
+		PollInterval:   10 * time.Second,
+	}
+}
+
+// RolloutOption modifies RolloutOptions.
+type RolloutOption func(*RolloutOptions)
+
+// WithRolloutKindTimeout sets the timeout to wait for objects of the specified kind.
+func WithRolloutKindTimeout(gk schema.GroupKind, timeout time.Duration) RolloutOption {
+	return func(o *RolloutOptions) {
+		o.KindTimeouts = maps.Clone(o.KindTimeouts)
+
+		if o.KindTimeouts == nil {
+			o.KindTimeouts = map[schema.GroupKind]time.Duration{}
 		}
+
+		o.KindTimeouts[gk] = timeout
 	}
+}
 
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return err
+// WithRolloutDefaultTimeout sets the timeout to wait for objects which have no kind-specific timeout.
+func WithRolloutDefaultTimeout(timeout time.Duration) RolloutOption {
+	return func(o *RolloutOptions) {
+		o.DefaultTimeout = timeout
 	}
+}
 
-	defer clientset.Close() //nolint:errcheck
+// WithRolloutPollInterval sets the interval between object readiness checks.
+func WithRolloutPollInterval(interval time.Duration) RolloutOption {
+	return func(o *RolloutOptions) {
+		o.PollInterval = interval
+	}
+}
 
-	if err = waitForDeploymentsRollout(ctx, clientset, deployments, resultCh); err != nil {
-		return err
+// WithRolloutDeadline limits the overall time waiting for the rollout.
+func WithRolloutDeadline(deadline time.Duration) RolloutOption {
+	return func(o *RolloutOptions) {
+		o.Deadline = deadline
 	}
+}
 
-	return waitForDaemonSetsRollout(ctx, clientset, daemonsets, resultCh)
+// WithRolloutLoadBalancerIngress waits for Services of type LoadBalancer to receive an ingress IP or hostname.
+//
+// By default, such Services are considered ready once the cluster IP is assigned, as not every cluster
+// has a load balancer implementation.
+func WithRolloutLoadBalancerIngress() RolloutOption {
+	return func(o *RolloutOptions) {
+		o.LoadBalancerIngress = true
+	}
 }
 
-func waitForDeploymentsRollout(ctx context.Context, clientset *kubernetes.Client, deployments []Manifest, resultCh chan<- RolloutProgress) error {
-	for _, obj := range deployments {
-		if !channel.SendWithContext(ctx, resultCh,
-			RolloutProgress{
-				Object: obj,
-				Path:   manifestPath(obj),
-			}) {
-			return ctx.Err()
-		}
+func (o *RolloutOptions) timeout(obj Manifest) time.Duration {
+	if timeout, ok := o.KindTimeouts[obj.GroupVersionKind().GroupKind()]; ok {
+		return timeout
+	}
 
-		err := retry.Constant(3*time.Minute, retry.WithUnits(10*time.Second)).Retry(func() error {
-			deployment, err := clientset.AppsV1().Deployments(obj.GetNamespace()).Get(ctx, obj.GetName(), metav1.GetOptions{})
-			if err != nil {
-				if kubernetes.IsRetryableError(err) {
-					return retry.ExpectedError(err)
-				}
+	return o.DefaultTimeout
+}
 
-				return err
-			}
+// WaitForRollout waits for the manifest rollout to be complete.
+//
+// The readiness of each object is evaluated with ComputeStatus, so any kind is supported:
+// workloads, Services, PVCs, CRDs and custom resources with standard conditions.
+// Objects which are not yet ready are reported via resultCh before waiting for them,
+// and once again with diagnostics attached if they fail to become ready; objects which are already ready are not reported.
+// Objects with StatusFailed are not waited for, the diagnostics are reported right away.
+func WaitForRollout(ctx context.Context, config *rest.Config, objects []Manifest, resultCh chan<- RolloutProgress, opts ...RolloutOption) error {
+	options := DefaultRolloutOptions()
 
-			if deployment.Generation != deployment.Status.ObservedGeneration {
-				return retry.ExpectedErrorf("deployment %s generation %d != observed generation %d", deployment.Name, deployment.Generation, deployment.Status.ObservedGeneration)
-			}
+	for _, opt := range opts {
+		opt(&options)
+	}
 
-			if deployment.Status.ReadyReplicas != deployment.Status.Replicas || deployment.Status.UpdatedReplicas != deployment.Status.Replicas {
-				return retry.ExpectedErrorf("deployment %s ready replicas %d != replicas %d", deployment.Name, deployment.Status.ReadyReplicas, deployment.Status.Replicas)
-			}
+	if options.Deadline > 0 {
+		var cancel context.CancelFunc
 
-			return nil
-		})
-		if err != nil {
+		ctx, cancel = context.WithTimeout(ctx, options.Deadline)
+		defer cancel()
+	}
+
+	config = rest.CopyConfig(config)
+
+	dialer := kubernetes.NewDialer()
+	config.Dial = dialer.DialContext
+
+	defer dialer.CloseAll()
+
+	waiter, err := newRolloutWaiter(config, resultCh, options)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objects {
+		if err = waiter.wait(ctx, obj); err != nil {
 			return err
 		}
 	}
@@ -87,57 +158,119 @@ func waitForDeploymentsRollout(ctx context.Context, clientset *kubernetes.Client
 	return nil
 }
 
-func waitForDaemonSetsRollout(ctx context.Context, clientset *kubernetes.Client, daemonSets []Manifest, resultCh chan<- RolloutProgress) error {
-	for _, obj := range daemonSets {
-		if !channel.SendWithContext(ctx, resultCh,
-			RolloutProgress{
-				Object: obj,
-				Path:   manifestPath(obj),
-			}) {
-			return ctx.Err()
-		}
+type rolloutWaiter struct {
+	mapper    *restmapper.DeferredDiscoveryRESTMapper
+	k8sClient dynamic.Interface
+	clientset k8s.Interface
+	resultCh  chan<- RolloutProgress
+	options   RolloutOptions
+}
 
-		err := retry.Constant(5*time.Minute, retry.WithUnits(10*time.Second)).Retry(func() error {
-			daemonSet, err := clientset.AppsV1().DaemonSets(obj.GetNamespace()).Get(ctx, obj.GetName(), metav1.GetOptions{})
-			if err != nil {
-				if kubernetes.IsRetryableError(err) {
-					return retry.ExpectedError(err)
-				}
+func newRolloutWaiter(config *rest.Config, resultCh chan<- RolloutProgress, options RolloutOptions) (*rolloutWaiter, error) {
+	k8sClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
 
-				return err
-			}
+	clientset, err := k8s.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
 
-			if daemonSet.Generation != daemonSet.Status.ObservedGeneration {
-				return retry.ExpectedErrorf("expected observed generation for %s to be %d, got %d",
-					daemonSet.Name, daemonSet.Generation, daemonSet.Status.ObservedGeneration)
-			}
+	dc, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
 
-			if daemonSet.Status.UpdatedNumberScheduled != daemonSet.Status.DesiredNumberScheduled {
-				return retry.ExpectedErrorf("expected current number up-to-date for %s to be %d, got %d",
-					daemonSet.Name, daemonSet.Status.UpdatedNumberScheduled, daemonSet.Status.CurrentNumberScheduled)
-			}
+	return &rolloutWaiter{
+		mapper:    restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc)),
+		k8sClient: k8sClient,
+		clientset: clientset,
+		resultCh:  resultCh,
+		options:   options,
+	}, nil
+}
 
-			if daemonSet.Status.CurrentNumberScheduled != daemonSet.Status.DesiredNumberScheduled {
-				return retry.ExpectedErrorf("expected current number scheduled for %s to be %d, got %d",
-					daemonSet.Name, daemonSet.Status.DesiredNumberScheduled, daemonSet.Status.CurrentNumberScheduled)
-			}
+func (w *rolloutWaiter) wait(ctx context.Context, obj Manifest) error {
+	var (
+		reported bool
+		current  Manifest
+		status   StatusResult
+	)
 
-			if daemonSet.Status.NumberAvailable != daemonSet.Status.DesiredNumberScheduled {
-				return retry.ExpectedErrorf("expected number available for %s to be %d, got %d",
-					daemonSet.Name, daemonSet.Status.DesiredNumberScheduled, daemonSet.Status.NumberAvailable)
-			}
+	err := retry.Constant(w.options.timeout(obj), retry.WithUnits(w.options.PollInterval)).RetryWithContext(ctx, func(ctx context.Context) error {
+		dr, err := resourceClient(w.mapper, w.k8sClient, obj)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				// the CRD might have been just created
+				w.mapper.Reset()
 
-			if daemonSet.Status.NumberReady != daemonSet.Status.DesiredNumberScheduled {
-				return retry.ExpectedErrorf("expected number ready for %s to be %d, got %d",
-					daemonSet.Name, daemonSet.Status.DesiredNumberScheduled, daemonSet.Status.NumberReady)
+				return retry.ExpectedError(err)
 			}
 
-			return nil
-		})
+			return err
+		}
+
+		current, err = dr.Get(ctx, obj.GetName(), metav1.GetOptions{})
 		if err != nil {
+			if kubernetes.IsRetryableError(err) || apierrors.IsNotFound(err) {
+				return retry.ExpectedError(err)
+			}
+
 			return err
 		}
+
+		status = ComputeStatus(current)
+
+		if status.Status == StatusCurrent && w.options.LoadBalancerIngress {
+			status = loadBalancerIngressStatus(current)
+		}
+
+		if status.Status == StatusCurrent {
+			return nil
+		}
+
+		if status.Status == StatusFailed {
+			// the object is not expected to become ready without intervention, so don't wait for the timeout
+			return fmt.Errorf("%s is %s: %s", manifestPath(obj), status.Status, status.Message)
+		}
+
+		if !reported {
+			reported = true
+
+			if !channel.SendWithContext(ctx, w.resultCh,
+				RolloutProgress{
+					Object: obj,
+					Path:   manifestPath(obj),
+					Status: status,
+				}) {
+				return ctx.Err()
+			}
+		}
+
+		return retry.ExpectedErrorf("%s is %s: %s", manifestPath(obj), status.Status, status.Message)
+	})
+	if err == nil {
+		return nil
 	}
 
-	return nil
+	if current != nil && ctx.Err() == nil {
+		diagnostics := collectDiagnostics(ctx, w.clientset, current)
+
+		if !channel.SendWithContext(ctx, w.resultCh,
+			RolloutProgress{
+				Object:      obj,
+				Path:        manifestPath(obj),
+				Status:      status,
+				Diagnostics: diagnostics,
+			}) {
+			return ctx.Err()
+		}
+
+		if summary := diagnostics.String(); summary != "" {
+			return fmt.Errorf("error waiting for %s rollout: %w\n%s", manifestPath(obj), err, summary)
+		}
+	}
+
+	return fmt.Errorf("error waiting for %s rollout: %w", manifestPath(obj), err)
 }
@@ -0,0 +1,169 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/siderolabs/gen/xslices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+const (
+	deploymentPath = "/apis/apps/v1/namespaces/default/deployments/app"
+
+	deploymentManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: app
+`
+
+	deploymentReadyStatus = `status:
+  replicas: 1
+  updatedReplicas: 1
+  readyReplicas: 1
+  availableReplicas: 1
+`
+
+	deploymentFailedStatus = `status:
+  replicas: 1
+  updatedReplicas: 1
+  conditions:
+  - type: Progressing
+    status: "False"
+    reason: ProgressDeadlineExceeded
+`
+
+	unhealthyPod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: app-5d78c9869d-x7x2z
+  namespace: default
+  labels:
+    app: app
+status:
+  phase: Pending
+  containerStatuses:
+  - name: app
+    state:
+      waiting:
+        reason: ImagePullBackOff
+        message: Back-off pulling image "app:v0"
+`
+)
+
+// waitForRollout runs WaitForRollout collecting the progress.
+func waitForRollout(ctx context.Context, config *rest.Config, objects []manifests.Manifest, opts ...manifests.RolloutOption) ([]manifests.RolloutProgress, error) {
+	resultCh := make(chan manifests.RolloutProgress)
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- manifests.WaitForRollout(ctx, config, objects, resultCh, opts...)
+	}()
+
+	var progress []manifests.RolloutProgress
+
+	for {
+		select {
+		case result := <-resultCh:
+			progress = append(progress, result)
+		case err := <-errCh:
+			return progress, err
+		}
+	}
+}
+
+func TestWaitForRollout(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer ctxCancel()
+
+	for _, test := range []struct {
+		name   string
+		status string
+		// readyAfter is the number of lookups after which the deployment becomes ready, 0 means never
+		readyAfter int32
+
+		expectedStatuses []manifests.Status
+	}{
+		{
+			name:   "ready",
+			status: deploymentReadyStatus,
+		},
+		{
+			name:       "becomes ready",
+			readyAfter: 3,
+
+			// reported once while waiting
+			expectedStatuses: []manifests.Status{manifests.StatusInProgress},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fake, config := newFakeAPIServer(t, parseManifests(t, deploymentManifest+test.status)...)
+
+			var lookups atomic.Int32
+
+			fake.intercept = func(_ http.ResponseWriter, r *http.Request) bool {
+				if r.Method == http.MethodGet && r.URL.Path == deploymentPath && lookups.Add(1) == test.readyAfter {
+					fake.set(deploymentPath, parseManifests(t, deploymentManifest+deploymentReadyStatus)[0].Object)
+				}
+
+				return false
+			}
+
+			progress, err := waitForRollout(ctx, config, parseManifests(t, deploymentManifest),
+				manifests.WithRolloutPollInterval(100*time.Millisecond))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatuses, xslices.Map(progress, func(p manifests.RolloutProgress) manifests.Status { return p.Status.Status }))
+
+			for _, p := range progress {
+				assert.Nil(t, p.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestWaitForRolloutFailed(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer ctxCancel()
+
+	_, config := newFakeAPIServer(t, parseManifests(t, deploymentManifest+deploymentFailedStatus+"---\n"+unhealthyPod)...)
+
+	start := time.Now()
+
+	// the failed object is not waited for up to the timeout
+	progress, err := waitForRollout(ctx, config, parseManifests(t, deploymentManifest),
+		manifests.WithRolloutDefaultTimeout(time.Minute), manifests.WithRolloutPollInterval(10*time.Second))
+	require.ErrorContains(t, err, "progress deadline exceeded")
+	assert.ErrorContains(t, err, "ImagePullBackOff")
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	require.Len(t, progress, 1)
+	assert.Equal(t, manifests.StatusFailed, progress[0].Status.Status)
+	require.NotNil(t, progress[0].Diagnostics)
+	assert.Equal(t, []manifests.PodDiagnostics{
+		{
+			Name:  "app-5d78c9869d-x7x2z",
+			Phase: "Pending",
+			Containers: []manifests.ContainerDiagnostics{
+				{Name: "app", Reason: "ImagePullBackOff", Message: `Back-off pulling image "app:v0"`},
+			},
+		},
+	}, progress[0].Diagnostics.UnhealthyPods)
+}
@@ -0,0 +1,71 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"cmp"
+	"slices"
+)
+
+// defaultKindWeight is the weight of the kinds which are not listed in kindWeights.
+const defaultKindWeight = 50
+
+// kindWeights defines the install order: objects with lower weight are applied first.
+var kindWeights = map[string]int{
+	"Namespace": 0,
+
+	"CustomResourceDefinition": 10,
+
+	"ServiceAccount":     20,
+	"ClusterRole":        20,
+	"ClusterRoleBinding": 20,
+	"Role":               20,
+	"RoleBinding":        20,
+
+	"APIService":                       100,
+	"MutatingWebhookConfiguration":     100,
+	"ValidatingWebhookConfiguration":   100,
+	"ValidatingAdmissionPolicy":        100,
+	"ValidatingAdmissionPolicyBinding": 100,
+}
+
+// kindWeight returns the weight of the object's kind, weights override the default kind weights.
+func kindWeight(obj Manifest, weights map[string]int) int {
+	if weight, ok := weights[obj.GetKind()]; ok {
+		return weight
+	}
+
+	if weight, ok := kindWeights[obj.GetKind()]; ok {
+		return weight
+	}
+
+	return defaultKindWeight
+}
+
+// SortObjects returns a copy of objects sorted in the install order.
+//
+// Namespaces come first, then CRDs, RBAC, everything else, and webhooks last.
+// The order of the objects of the same weight is preserved.
+//
+// Weights override the default weights of the kinds (lower weight is installed first),
+// e.g. to install custom resources required by other objects early. The default weights are:
+// Namespace 0, CRDs 10, ServiceAccounts and RBAC 20, webhooks and APIServices 100, other kinds 50.
+func SortObjects(objects []Manifest, weights map[string]int) []Manifest {
+	sorted := slices.Clone(objects)
+
+	slices.SortStableFunc(sorted, func(a, b Manifest) int {
+		return cmp.Compare(kindWeight(a, weights), kindWeight(b, weights))
+	})
+
+	return sorted
+}
+
+// SortObjectsForDeletion returns a copy of objects sorted in the deletion order, which is the reverse of SortObjects.
+func SortObjectsForDeletion(objects []Manifest, weights map[string]int) []Manifest {
+	sorted := SortObjects(objects, weights)
+	slices.Reverse(sorted)
+
+	return sorted
+}
@@ -0,0 +1,101 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"testing"
+
+	"github.com/siderolabs/gen/xslices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+const sortManifests = `apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: webhook
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: apps
+---
+apiVersion: cilium.io/v2
+kind: CiliumLoadBalancerIPPool
+metadata:
+  name: pool
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app
+  namespace: apps
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: ciliumloadbalancerippools.cilium.io
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: apps
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: apps
+`
+
+func TestSortObjects(t *testing.T) {
+	objects, err := manifests.Parse([]byte(sortManifests))
+	require.NoError(t, err)
+
+	kinds := func(objects []manifests.Manifest) []string {
+		return xslices.Map(objects, manifests.Manifest.GetKind)
+	}
+
+	assert.Equal(t, []string{
+		"Namespace",
+		"CustomResourceDefinition",
+		"ServiceAccount",
+		"Deployment",
+		"CiliumLoadBalancerIPPool",
+		"ConfigMap",
+		"ValidatingWebhookConfiguration",
+	}, kinds(manifests.SortObjects(objects, nil)))
+
+	weights := map[string]int{
+		"CiliumLoadBalancerIPPool": 15,
+		"ServiceAccount":           60,
+	}
+
+	assert.Equal(t, []string{
+		"Namespace",
+		"CustomResourceDefinition",
+		"CiliumLoadBalancerIPPool",
+		"Deployment",
+		"ConfigMap",
+		"ServiceAccount",
+		"ValidatingWebhookConfiguration",
+	}, kinds(manifests.SortObjects(objects, weights)))
+
+	assert.Equal(t, []string{
+		"ValidatingWebhookConfiguration",
+		"ServiceAccount",
+		"ConfigMap",
+		"Deployment",
+		"CiliumLoadBalancerIPPool",
+		"CustomResourceDefinition",
+		"Namespace",
+	}, kinds(manifests.SortObjectsForDeletion(objects, weights)))
+
+	// the input is not modified
+	assert.Equal(t, "ValidatingWebhookConfiguration", objects[0].GetKind())
+}
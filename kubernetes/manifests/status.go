@@ -0,0 +1,337 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Status is the readiness status of an object.
+//
+// The statuses and the rules to compute them follow kstatus from sigs.k8s.io/cli-utils.
+type Status string
+
+// Object statuses.
+const (
+	// StatusInProgress means the object is not yet reconciled, but might become ready.
+	StatusInProgress Status = "InProgress"
+	// StatusFailed means the object failed to reconcile and is not expected to become ready without intervention.
+	StatusFailed Status = "Failed"
+	// StatusCurrent means the object is fully reconciled.
+	StatusCurrent Status = "Current"
+	// StatusTerminating means the object is being deleted.
+	StatusTerminating Status = "Terminating"
+)
+
+// StatusResult is the computed status of an object with a human-readable explanation.
+type StatusResult struct {
+	Status  Status
+	Message string
+}
+
+type condition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+// ComputeStatus computes the readiness status of the object based on its kind, generation and conditions.
+//
+// Objects with no known readiness semantics are reported as current.
+func ComputeStatus(obj Manifest) StatusResult {
+	if obj.GetDeletionTimestamp() != nil {
+		return StatusResult{Status: StatusTerminating, Message: "object is being deleted"}
+	}
+
+	if observedGeneration, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration"); found && observedGeneration != obj.GetGeneration() {
+		return inProgress("generation %d != observed generation %d", obj.GetGeneration(), observedGeneration)
+	}
+
+	conditions := getConditions(obj)
+
+	// standard conditions apply to any kind
+	if c, ok := conditions["Reconciling"]; ok && c.Status == "True" {
+		return inProgress("%s", conditionMessage(c))
+	}
+
+	if c, ok := conditions["Stalled"]; ok && c.Status == "True" {
+		return failed("%s", conditionMessage(c))
+	}
+
+	gvk := obj.GroupVersionKind()
+
+	switch {
+	case gvk.Group == "apps" && gvk.Kind == "Deployment":
+		return deploymentStatus(obj, conditions)
+	case gvk.Group == "apps" && gvk.Kind == "DaemonSet":
+		return daemonSetStatus(obj)
+	case gvk.Group == "apps" && gvk.Kind == "StatefulSet":
+		return statefulSetStatus(obj)
+	case gvk.Group == "apps" && gvk.Kind == "ReplicaSet":
+		return replicaSetStatus(obj)
+	case gvk.Group == "batch" && gvk.Kind == "Job":
+		return jobStatus(conditions)
+	case gvk.Group == "" && gvk.Kind == "Pod":
+		return podStatus(obj, conditions)
+	case gvk.Group == "" && gvk.Kind == "PersistentVolumeClaim":
+		return pvcStatus(obj)
+	case gvk.Group == "" && gvk.Kind == "Service":
+		return serviceStatus(obj)
+	case gvk.Group == "apiextensions.k8s.io" && gvk.Kind == "CustomResourceDefinition":
+		return crdStatus(conditions)
+	}
+
+	// custom resources following the standard Ready condition
+	if c, ok := conditions["Ready"]; ok && c.Status != "True" {
+		return inProgress("%s", conditionMessage(c))
+	}
+
+	return current("resource is current")
+}
+
+func deploymentStatus(obj Manifest, conditions map[string]condition) StatusResult {
+	if c, ok := conditions["Progressing"]; ok && c.Reason == "ProgressDeadlineExceeded" {
+		return failed("progress deadline exceeded")
+	}
+
+	replicas := nestedInt(obj, 1, "spec", "replicas")
+	statusReplicas := nestedInt(obj, 0, "status", "replicas")
+	updatedReplicas := nestedInt(obj, 0, "status", "updatedReplicas")
+	readyReplicas := nestedInt(obj, 0, "status", "readyReplicas")
+	availableReplicas := nestedInt(obj, 0, "status", "availableReplicas")
+
+	switch {
+	case updatedReplicas < replicas:
+		return inProgress("updated replicas %d != replicas %d", updatedReplicas, replicas)
+	case statusReplicas > updatedReplicas:
+		return inProgress("pending termination of %d old replicas", statusReplicas-updatedReplicas)
+	case readyReplicas < replicas:
+		return inProgress("ready replicas %d != replicas %d", readyReplicas, replicas)
+	case availableReplicas < replicas:
+		return inProgress("available replicas %d != replicas %d", availableReplicas, replicas)
+	}
+
+	if c, ok := conditions["Available"]; ok && c.Status != "True" {
+		return inProgress("deployment not available")
+	}
+
+	return current("deployment is available, replicas: %d", replicas)
+}
+
+func daemonSetStatus(obj Manifest) StatusResult {
+	desired := nestedInt(obj, 0, "status", "desiredNumberScheduled")
+
+	for _, field := range []string{"currentNumberScheduled", "updatedNumberScheduled", "numberAvailable", "numberReady"} {
+		if actual := nestedInt(obj, 0, "status", field); actual < desired {
+			return inProgress("%s %d != desired number scheduled %d", field, actual, desired)
+		}
+	}
+
+	return current("all replicas scheduled as expected, replicas: %d", desired)
+}
+
+func statefulSetStatus(obj Manifest) StatusResult {
+	if strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type"); strategy == "OnDelete" {
+		return current("statefulset uses OnDelete update strategy")
+	}
+
+	replicas := nestedInt(obj, 1, "spec", "replicas")
+
+	for _, field := range []string{"replicas", "readyReplicas", "currentReplicas"} {
+		if actual := nestedInt(obj, 0, "status", field); actual < replicas {
+			return inProgress("%s %d != replicas %d", field, actual, replicas)
+		}
+	}
+
+	partition := nestedInt(obj, 0, "spec", "updateStrategy", "rollingUpdate", "partition")
+
+	if updated := nestedInt(obj, 0, "status", "updatedReplicas"); updated < replicas-partition {
+		return inProgress("updated replicas %d != expected %d", updated, replicas-partition)
+	}
+
+	currentRevision, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+	updateRevision, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+
+	if partition == 0 && currentRevision != updateRevision {
+		return inProgress("waiting for update revision %s", updateRevision)
+	}
+
+	return current("all replicas scheduled as expected, replicas: %d", replicas)
+}
+
+func replicaSetStatus(obj Manifest) StatusResult {
+	replicas := nestedInt(obj, 1, "spec", "replicas")
+
+	for _, field := range []string{"labeledReplicas", "availableReplicas", "readyReplicas"} {
+		if actual := nestedInt(obj, 0, "status", field); actual < replicas {
+			return inProgress("%s %d != replicas %d", field, actual, replicas)
+		}
+	}
+
+	return current("replicaset is available, replicas: %d", replicas)
+}
+
+func jobStatus(conditions map[string]condition) StatusResult {
+	if c, ok := conditions["Failed"]; ok && c.Status == "True" {
+		return failed("job failed: %s", conditionMessage(c))
+	}
+
+	if c, ok := conditions["Complete"]; ok && c.Status == "True" {
+		return current("job completed")
+	}
+
+	return inProgress("job in progress")
+}
+
+func podStatus(obj Manifest, conditions map[string]condition) StatusResult {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+
+	switch phase {
+	case "Succeeded":
+		return current("pod has completed successfully")
+	case "Failed":
+		return failed("pod has failed")
+	case "Running":
+		if c, ok := conditions["Ready"]; ok && c.Status == "True" {
+			return current("pod is ready")
+		}
+	}
+
+	containerStatuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "containerStatuses")
+
+	for _, cs := range containerStatuses {
+		reason, _, _ := unstructured.NestedString(asMap(cs), "state", "waiting", "reason")
+
+		if reason == "CrashLoopBackOff" {
+			return failed("pod is in CrashLoopBackOff")
+		}
+	}
+
+	return inProgress("pod is not ready")
+}
+
+func pvcStatus(obj Manifest) StatusResult {
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase != "Bound" {
+		return inProgress("PVC is not bound")
+	}
+
+	return current("PVC is bound")
+}
+
+func serviceStatus(obj Manifest) StatusResult {
+	serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type")
+
+	if serviceType == "LoadBalancer" {
+		if clusterIP, _, _ := unstructured.NestedString(obj.Object, "spec", "clusterIP"); clusterIP == "" {
+			return inProgress("cluster IP not set")
+		}
+	}
+
+	return current("service is ready")
+}
+
+// loadBalancerIngressStatus checks that the Service of type LoadBalancer has received an ingress IP or hostname.
+func loadBalancerIngressStatus(obj Manifest) StatusResult {
+	gvk := obj.GroupVersionKind()
+
+	if gvk.Group != "" || gvk.Kind != "Service" {
+		return current("resource is current")
+	}
+
+	if serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type"); serviceType != "LoadBalancer" {
+		return current("service is ready")
+	}
+
+	ingress, _, _ := unstructured.NestedSlice(obj.Object, "status", "loadBalancer", "ingress")
+
+	for _, item := range ingress {
+		entry := asMap(item)
+
+		if ip, _, _ := unstructured.NestedString(entry, "ip"); ip != "" {
+			return current("load balancer ingress is ready")
+		}
+
+		if hostname, _, _ := unstructured.NestedString(entry, "hostname"); hostname != "" {
+			return current("load balancer ingress is ready")
+		}
+	}
+
+	return inProgress("load balancer ingress is not assigned")
+}
+
+func crdStatus(conditions map[string]condition) StatusResult {
+	if c, ok := conditions["NamesAccepted"]; ok && c.Status == "False" {
+		return failed("CRD names have not been accepted: %s", c.Message)
+	}
+
+	if c, ok := conditions["Established"]; !ok || c.Status != "True" {
+		return inProgress("CRD is not established")
+	}
+
+	return current("CRD is established")
+}
+
+func getConditions(obj Manifest) map[string]condition {
+	items, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+
+	conditions := make(map[string]condition, len(items))
+
+	for _, item := range items {
+		m := asMap(item)
+
+		c := condition{}
+		c.Type, _, _ = unstructured.NestedString(m, "type")
+		c.Status, _, _ = unstructured.NestedString(m, "status")
+		c.Reason, _, _ = unstructured.NestedString(m, "reason")
+		c.Message, _, _ = unstructured.NestedString(m, "message")
+
+		if c.Type != "" {
+			conditions[c.Type] = c
+		}
+	}
+
+	return conditions
+}
+
+func conditionMessage(c condition) string {
+	switch {
+	case c.Message != "":
+		return c.Message
+	case c.Reason != "":
+		return fmt.Sprintf("%s: %s", c.Type, c.Reason)
+	default:
+		return fmt.Sprintf("%s is %s", c.Type, c.Status)
+	}
+}
+
+func nestedInt(obj Manifest, defaultValue int64, fields ...string) int64 {
+	v, found, err := unstructured.NestedInt64(obj.Object, fields...)
+	if !found || err != nil {
+		return defaultValue
+	}
+
+	return v
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any) //nolint:errcheck
+
+	return m
+}
+
+func inProgress(format string, args ...any) StatusResult {
+	return StatusResult{Status: StatusInProgress, Message: fmt.Sprintf(format, args...)}
+}
+
+func failed(format string, args ...any) StatusResult {
+	return StatusResult{Status: StatusFailed, Message: fmt.Sprintf(format, args...)}
+}
+
+func current(format string, args ...any) StatusResult {
+	return StatusResult{Status: StatusCurrent, Message: fmt.Sprintf(format, args...)}
+}
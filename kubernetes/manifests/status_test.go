@@ -0,0 +1,258 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+func TestComputeStatus(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		manifest string
+		// loadBalancerIngress enables the load balancer ingress check, as WithRolloutLoadBalancerIngress does
+		loadBalancerIngress bool
+
+		expectedStatus manifests.Status
+	}{
+		{
+			name: "configmap",
+			manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`,
+			expectedStatus: manifests.StatusCurrent,
+		},
+		{
+			name: "deployment ready",
+			manifest: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  generation: 2
+spec:
+  replicas: 2
+status:
+  observedGeneration: 2
+  replicas: 2
+  updatedReplicas: 2
+  readyReplicas: 2
+  availableReplicas: 2
+  conditions:
+  - type: Available
+    status: "True"
+`,
+			expectedStatus: manifests.StatusCurrent,
+		},
+		{
+			name: "deployment old generation",
+			manifest: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  generation: 3
+spec:
+  replicas: 2
+status:
+  observedGeneration: 2
+  replicas: 2
+  updatedReplicas: 2
+  readyReplicas: 2
+  availableReplicas: 2
+`,
+			expectedStatus: manifests.StatusInProgress,
+		},
+		{
+			name: "deployment progress deadline",
+			manifest: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+status:
+  conditions:
+  - type: Progressing
+    status: "False"
+    reason: ProgressDeadlineExceeded
+`,
+			expectedStatus: manifests.StatusFailed,
+		},
+		{
+			name: "daemonset not ready",
+			manifest: `apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: ds
+status:
+  desiredNumberScheduled: 3
+  currentNumberScheduled: 3
+  updatedNumberScheduled: 3
+  numberAvailable: 2
+  numberReady: 2
+`,
+			expectedStatus: manifests.StatusInProgress,
+		},
+		{
+			name: "pvc pending",
+			manifest: `apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+status:
+  phase: Pending
+`,
+			expectedStatus: manifests.StatusInProgress,
+		},
+		{
+			name: "crd established",
+			manifest: `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: foos.example.com
+status:
+  conditions:
+  - type: NamesAccepted
+    status: "True"
+  - type: Established
+    status: "True"
+`,
+			expectedStatus: manifests.StatusCurrent,
+		},
+		{
+			name: "custom resource not ready",
+			manifest: `apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+status:
+  conditions:
+  - type: Ready
+    status: "False"
+    message: waiting for backend
+`,
+			expectedStatus: manifests.StatusInProgress,
+		},
+		{
+			name: "custom resource stalled",
+			manifest: `apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+status:
+  conditions:
+  - type: Stalled
+    status: "True"
+`,
+			expectedStatus: manifests.StatusFailed,
+		},
+		{
+			name: "load balancer without ingress",
+			manifest: `apiVersion: v1
+kind: Service
+metadata:
+  name: lb
+spec:
+  type: LoadBalancer
+  clusterIP: 10.96.0.10
+`,
+			expectedStatus: manifests.StatusCurrent,
+		},
+		{
+			name: "load balancer without ingress (ingress required)",
+			manifest: `apiVersion: v1
+kind: Service
+metadata:
+  name: lb
+spec:
+  type: LoadBalancer
+  clusterIP: 10.96.0.10
+status:
+  loadBalancer: {}
+`,
+			loadBalancerIngress: true,
+			expectedStatus:      manifests.StatusInProgress,
+		},
+		{
+			name: "load balancer with ingress IP",
+			manifest: `apiVersion: v1
+kind: Service
+metadata:
+  name: lb
+spec:
+  type: LoadBalancer
+  clusterIP: 10.96.0.10
+status:
+  loadBalancer:
+    ingress:
+    - ip: 192.168.0.10
+`,
+			loadBalancerIngress: true,
+			expectedStatus:      manifests.StatusCurrent,
+		},
+		{
+			name: "load balancer with ingress hostname",
+			manifest: `apiVersion: v1
+kind: Service
+metadata:
+  name: lb
+spec:
+  type: LoadBalancer
+  clusterIP: 10.96.0.10
+status:
+  loadBalancer:
+    ingress:
+    - hostname: lb.example.com
+`,
+			loadBalancerIngress: true,
+			expectedStatus:      manifests.StatusCurrent,
+		},
+		{
+			name: "load balancer without cluster IP",
+			manifest: `apiVersion: v1
+kind: Service
+metadata:
+  name: lb
+spec:
+  type: LoadBalancer
+`,
+			loadBalancerIngress: true,
+			expectedStatus:      manifests.StatusInProgress,
+		},
+		{
+			name: "cluster IP service (ingress required)",
+			manifest: `apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  type: ClusterIP
+  clusterIP: 10.96.0.11
+`,
+			loadBalancerIngress: true,
+			expectedStatus:      manifests.StatusCurrent,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			objects, err := manifests.Parse([]byte(test.manifest))
+			require.NoError(t, err)
+			require.Len(t, objects, 1)
+
+			status := manifests.ComputeStatus(objects[0])
+
+			if status.Status == manifests.StatusCurrent && test.loadBalancerIngress {
+				status = manifests.LoadBalancerIngressStatus(objects[0])
+			}
+
+			assert.Equal(t, test.expectedStatus, status.Status, status.Message)
+		})
+	}
+}
@@ -13,15 +13,22 @@ import (
 	"github.com/hexops/gotextdiff"
 	"github.com/hexops/gotextdiff/myers"
 	"github.com/hexops/gotextdiff/span"
-	"github.com/siderolabs/gen/channel"
+	"github.com/siderolabs/gen/xslices"
 	"github.com/siderolabs/go-retry/retry"
+	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/client-go/discovery"
 	memory "k8s.io/client-go/discovery/cached"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	k8syaml "sigs.k8s.io/yaml"
@@ -30,15 +37,172 @@ import (
 )
 
 // SyncResult describes the result of a single manifest sync.
+//
+// Deleted is set for the objects removed by pruning (see WithPrune).
+// Warnings are the warnings returned by the API server while applying the object, e.g. about deprecated APIs.
+// ResourceVersion and UID identify the resulting object in the cluster, they are empty in dry-run mode.
 type SyncResult struct {
-	Path    string
-	Object  Manifest
-	Diff    string
-	Skipped bool
+	Path            string
+	Object          Manifest
+	Diff            string
+	ResourceVersion string
+	UID             types.UID
+	Warnings        []string
+	Skipped         bool
+	Deleted         bool
+}
+
+// UpdateStrategy defines how Sync updates existing objects.
+type UpdateStrategy int
+
+// Update strategies.
+const (
+	// UpdateStrategyThreeWayMerge patches the object using a three-way merge between the last applied
+	// configuration (stored in the annotation), the manifest and the live object,
+	// so that only the fields owned by the manifest are overwritten.
+	UpdateStrategyThreeWayMerge UpdateStrategy = iota
+	// UpdateStrategyReplace replaces the object with the manifest, clobbering fields set by other controllers.
+	UpdateStrategyReplace
+)
+
+// SyncOptions configures the manifest sync.
+type SyncOptions struct {
+	// RolloutOptions are used by SyncWithLog to wait for the rollout of the applied manifests.
+	RolloutOptions []RolloutOption
+	// UpdateStrategy defines how existing objects are updated.
+	UpdateStrategy UpdateStrategy
+	// DuplicatePolicy defines how the objects defined more than once are handled.
+	DuplicatePolicy DuplicatePolicy
+	// KindWeights override the default install order weights of the kinds, see SortObjects.
+	KindWeights map[string]int
+	// PreviousObjects are the objects applied by the previous sync, used for pruning.
+	PreviousObjects []Manifest
+	// PruneAllowedKinds limits pruning to the listed kinds, if empty, any kind can be pruned.
+	PruneAllowedKinds []schema.GroupKind
+	// PreserveOrder disables sorting the objects in the install order.
+	PreserveOrder bool
+	// NamespaceTerminationTimeout is the time to wait for the terminating target namespaces to be removed.
+	NamespaceTerminationTimeout time.Duration
+	// CRDEstablishedTimeout is the time to wait for the applied CRDs to be established.
+	CRDEstablishedTimeout time.Duration
+	// Concurrency is the maximum number of objects applied concurrently.
+	Concurrency int
+	// Prune enables deleting the previous objects which are not part of the sync anymore.
+	Prune bool
+}
+
+// SyncOption modifies SyncOptions.
+type SyncOption func(*SyncOptions)
+
+// WithUpdateStrategy sets the strategy to update existing objects.
+func WithUpdateStrategy(strategy UpdateStrategy) SyncOption {
+	return func(o *SyncOptions) {
+		o.UpdateStrategy = strategy
+	}
+}
+
+// WithDuplicatePolicy sets the policy for the objects defined more than once.
+//
+// By default, the last definition of the object is applied (DuplicatePolicyLastWins),
+// use DuplicatePolicyError to fail with DuplicateObjectError if the same object is defined with different content.
+func WithDuplicatePolicy(policy DuplicatePolicy) SyncOption {
+	return func(o *SyncOptions) {
+		o.DuplicatePolicy = policy
+	}
+}
+
+// WithPreserveOrder applies the objects in the order they are passed to Sync.
+//
+// By default, objects are sorted so that dependencies are created first:
+// Namespaces, CRDs, RBAC, everything else, and webhooks last.
+func WithPreserveOrder() SyncOption {
+	return func(o *SyncOptions) {
+		o.PreserveOrder = true
+	}
+}
+
+// WithKindWeights overrides the install order weights of the kinds, see SortObjects.
+func WithKindWeights(weights map[string]int) SyncOption {
+	return func(o *SyncOptions) {
+		o.KindWeights = weights
+	}
+}
+
+// WithPrune deletes the objects which were applied previously, but are not part of the manifests anymore.
+//
+// The previous set of objects is provided by the caller, e.g. the manifests applied by the previous sync.
+// Objects are matched by group, kind, namespace and name; deletion happens after all manifests are applied,
+// in the reverse install order.
+func WithPrune(previous []Manifest) SyncOption {
+	return func(o *SyncOptions) {
+		o.Prune = true
+		o.PreviousObjects = previous
+	}
+}
+
+// WithPruneAllowedKinds limits pruning to the objects of the listed kinds.
+//
+// This guards against an incorrect previous object set cascading into deletion
+// of the objects which should never be pruned, e.g. CRDs or Namespaces.
+func WithPruneAllowedKinds(kinds ...schema.GroupKind) SyncOption {
+	return func(o *SyncOptions) {
+		o.PruneAllowedKinds = append(o.PruneAllowedKinds, kinds...)
+	}
+}
+
+// WithNamespaceTerminationTimeout waits up to the timeout for the target namespaces which are being terminated to be removed.
+//
+// By default, Sync fails with NamespaceTerminatingError right away if any of the target namespaces is terminating.
+func WithNamespaceTerminationTimeout(timeout time.Duration) SyncOption {
+	return func(o *SyncOptions) {
+		o.NamespaceTerminationTimeout = timeout
+	}
+}
+
+// WithCRDEstablishedTimeout sets the time to wait for the applied CRDs to be established (default is one minute).
+func WithCRDEstablishedTimeout(timeout time.Duration) SyncOption {
+	return func(o *SyncOptions) {
+		o.CRDEstablishedTimeout = timeout
+	}
+}
+
+// WithConcurrency sets the maximum number of objects applied concurrently (default is 1).
+//
+// Only the objects of the same install order weight (e.g. all RBAC objects) are applied concurrently,
+// so that dependencies are still created first. Results are reported in the order of the objects.
+func WithConcurrency(concurrency int) SyncOption {
+	return func(o *SyncOptions) {
+		o.Concurrency = concurrency
+	}
+}
+
+// WithRolloutOptions sets the options to wait for the rollout after the sync.
+func WithRolloutOptions(opts ...RolloutOption) SyncOption {
+	return func(o *SyncOptions) {
+		o.RolloutOptions = append(o.RolloutOptions, opts...)
+	}
+}
+
+func newSyncOptions(opts []SyncOption) SyncOptions {
+	options := SyncOptions{
+		DuplicatePolicy:       DuplicatePolicyLastWins,
+		CRDEstablishedTimeout: defaultCRDEstablishedTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
 }
 
 // Sync applies the manifests to the cluster providing the results.
-func Sync(ctx context.Context, objects []Manifest, config *rest.Config, dryRun bool, resultCh chan<- SyncResult) error {
+//
+// Applied CRDs are waited to be established before the objects which follow them in the install order are applied.
+// Objects defined more than once are applied once, by default with the last definition (see WithDuplicatePolicy).
+func Sync(ctx context.Context, objects []Manifest, config *rest.Config, dryRun bool, resultCh chan<- SyncResult, opts ...SyncOption) error {
+	options := newSyncOptions(opts)
+
 	dialer := kubernetes.NewDialer()
 	config.Dial = dialer.DialContext
 
@@ -48,127 +212,297 @@ func Sync(ctx context.Context, objects []Manifest, config *rest.Config, dryRun b
 		config.Dial = nil
 	}()
 
-	k8sClient, err := dynamic.NewForConfig(config)
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return err
+	}
+
+	k8sClient, err := dynamic.NewForConfigAndClient(config, httpClient)
 	if err != nil {
 		return err
 	}
 
-	dc, err := discovery.NewDiscoveryClientForConfig(config)
+	dc, err := discovery.NewDiscoveryClientForConfigAndClient(config, httpClient)
 	if err != nil {
 		return err
 	}
 
 	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
 
-	for _, obj := range objects {
+	objects, err = ResolveDuplicates(objects, options.DuplicatePolicy)
+	if err != nil {
+		return err
+	}
+
+	if !options.PreserveOrder {
+		objects = SortObjects(objects, options.KindWeights)
+	}
+
+	if err = checkNamespaces(ctx, k8sClient, objects, options.NamespaceTerminationTimeout); err != nil {
+		return err
+	}
+
+	apply := func(ctx context.Context, obj Manifest) (SyncResult, error) {
 		var (
 			resp    Manifest
 			diff    string
 			skipped bool
 		)
 
-		if err = retry.Constant(3*time.Minute, retry.WithUnits(10*time.Second), retry.WithErrorLogging(true)).RetryWithContext(ctx, func(ctx context.Context) error {
-			resp, diff, skipped, err = updateManifest(ctx, mapper, k8sClient, obj, dryRun)
+		// each object gets its own client sharing the connection to attribute the warnings to the object
+		warnings := &warningCollector{}
+
+		objConfig := rest.CopyConfig(config)
+		objConfig.WarningHandler = warnings
+
+		objClient, err := dynamic.NewForConfigAndClient(objConfig, httpClient)
+		if err != nil {
+			return SyncResult{}, err
+		}
+
+		if err := retry.Constant(3*time.Minute, retry.WithUnits(10*time.Second), retry.WithErrorLogging(true)).RetryWithContext(ctx, func(ctx context.Context) error {
+			var err error
+
+			warnings.reset()
+
+			resp, diff, skipped, err = updateManifest(ctx, mapper, objClient, obj, dryRun, options.UpdateStrategy)
 			if kubernetes.IsRetryableError(err) || apierrors.IsConflict(err) {
 				return retry.ExpectedError(err)
 			}
 
 			return err
 		}); err != nil {
+			return SyncResult{}, err
+		}
+
+		result := SyncResult{
+			Path:     manifestPath(resp),
+			Object:   resp,
+			Diff:     diff,
+			Warnings: warnings.get(),
+			Skipped:  skipped,
+		}
+
+		if !dryRun {
+			result.ResourceVersion = resp.GetResourceVersion()
+			result.UID = resp.GetUID()
+		}
+
+		return result, nil
+	}
+
+	for _, group := range groupByKindWeight(objects, options.KindWeights) {
+		if err = syncGroup(ctx, group, options.Concurrency, apply, resultCh); err != nil {
 			return err
 		}
 
-		if !channel.SendWithContext(ctx, resultCh, SyncResult{
-			Path:    manifestPath(resp),
-			Object:  resp,
-			Diff:    diff,
-			Skipped: skipped,
-		}) {
-			return ctx.Err()
+		// custom resources can only be created once the CRDs are established, and the mapper is refreshed
+		if crds := xslices.Filter(group, isCRD); len(crds) > 0 && !dryRun {
+			if err = waitForCRDs(ctx, k8sClient, crds, options.CRDEstablishedTimeout); err != nil {
+				return err
+			}
+
+			mapper.Reset()
 		}
 	}
 
+	if options.Prune {
+		return pruneObjects(ctx, mapper, k8sClient, prunedObjects(objects, options.PreviousObjects, options.PruneAllowedKinds, options.KindWeights), dryRun, resultCh)
+	}
+
 	return nil
 }
 
 func updateManifest(
 	ctx context.Context,
-	mapper *restmapper.DeferredDiscoveryRESTMapper,
+	mapper meta.RESTMapper,
 	k8sClient dynamic.Interface,
 	obj Manifest,
 	dryRun bool,
+	strategy UpdateStrategy,
 ) (
 	resp Manifest,
 	diff string,
 	skipped bool,
 	err error,
 ) {
-	mapping, err := mapper.RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
+	dr, err := resourceClient(mapper, k8sClient, obj)
 	if err != nil {
-		err = fmt.Errorf("error creating mapping for object %s: %w", obj.GetName(), err)
-
 		return nil, "", false, err
 	}
 
-	var dr dynamic.ResourceInterface
-	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
-		// namespaced resources should specify the namespace
-		dr = k8sClient.Resource(mapping.Resource).Namespace(obj.GetNamespace())
-	} else {
-		// for cluster-wide resources
-		dr = k8sClient.Resource(mapping.Resource)
-	}
-
-	exists := true
-
-	diff, err = getResourceDiff(ctx, dr, obj)
+	current, err := dr.Get(ctx, obj.GetName(), metav1.GetOptions{})
 	if err != nil {
 		if !apierrors.IsNotFound(err) {
 			return nil, "", false, err
 		}
 
-		exists = false
+		diff, err = manifestDiff(nil, obj)
+		if err != nil || dryRun {
+			return obj, diff, false, err
+		}
+
+		if strategy == UpdateStrategyThreeWayMerge {
+			if obj, err = withLastAppliedConfiguration(obj); err != nil {
+				return nil, "", false, err
+			}
+		}
+
+		resp, err = dr.Create(ctx, obj, metav1.CreateOptions{})
+
+		return resp, diff, false, err
+	}
+
+	// run the update in dry-run mode first to get the diff
+	resp, err = updateResource(ctx, dr, current, obj, strategy, true)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	diff, err = resourceDiff(current, resp)
+	if err != nil {
+		return nil, "", false, err
 	}
 
+	// objects created before the last applied configuration was recorded should get it,
+	// otherwise the fields removed from the manifest are never pruned by the three-way merge
+	_, hasLastApplied := current.GetAnnotations()[v1.LastAppliedConfigAnnotation]
+	missingLastApplied := strategy == UpdateStrategyThreeWayMerge && !hasLastApplied
+
 	switch {
 	case dryRun:
 		return obj, diff, diff == "", nil
-	case !exists:
-		resp, err = dr.Create(ctx, obj, metav1.CreateOptions{})
-	case diff != "":
-		resp, err = dr.Update(ctx, obj, metav1.UpdateOptions{})
-	default:
-		skipped = true
-		resp = obj
+	case diff == "" && missingLastApplied:
+		resp, err = updateResource(ctx, dr, current, obj, strategy, false)
+
+		return resp, "", true, err
+	case diff == "":
+		// report the live object version for the unchanged object
+		resp = obj.DeepCopy()
+		resp.SetResourceVersion(current.GetResourceVersion())
+		resp.SetUID(current.GetUID())
+
+		return resp, "", true, nil
 	}
 
-	return resp, diff, skipped, err
+	resp, err = updateResource(ctx, dr, current, obj, strategy, false)
+
+	return resp, diff, false, err
 }
 
-func getResourceDiff(ctx context.Context, dr dynamic.ResourceInterface, obj Manifest) (string, error) {
-	current, err := dr.Get(ctx, obj.GetName(), metav1.GetOptions{})
+func updateResource(ctx context.Context, dr dynamic.ResourceInterface, current, obj Manifest, strategy UpdateStrategy, dryRun bool) (Manifest, error) {
+	var dryRunOpts []string
+
+	if dryRun {
+		dryRunOpts = []string{metav1.DryRunAll}
+	}
+
+	if strategy == UpdateStrategyReplace {
+		obj.SetResourceVersion(current.GetResourceVersion())
+
+		return dr.Update(ctx, obj, metav1.UpdateOptions{
+			DryRun: dryRunOpts,
+		})
+	}
+
+	patchType, patch, err := threeWayMergePatch(current, obj)
 	if err != nil {
-		if apierrors.IsNotFound(err) {
-			diff, diffErr := manifestDiff(nil, obj)
-			if diffErr != nil {
-				return "", diffErr
-			}
+		return nil, fmt.Errorf("error computing patch for %s: %w", manifestPath(obj), err)
+	}
 
-			return diff, err
+	return dr.Patch(ctx, obj.GetName(), patchType, patch, metav1.PatchOptions{
+		DryRun: dryRunOpts,
+	})
+}
+
+// threeWayMergePatch computes the patch from the last applied configuration (if any) to the manifest
+// taking into account the live object, the same way 'kubectl apply' does.
+//
+// Strategic merge patch is used for the built-in types, and JSON merge patch for everything else.
+func threeWayMergePatch(current, obj Manifest) (types.PatchType, []byte, error) {
+	modified, err := withLastAppliedConfiguration(obj)
+	if err != nil {
+		return "", nil, err
+	}
+
+	modifiedJSON, err := modified.MarshalJSON()
+	if err != nil {
+		return "", nil, err
+	}
+
+	currentJSON, err := current.MarshalJSON()
+	if err != nil {
+		return "", nil, err
+	}
+
+	original := []byte(current.GetAnnotations()[v1.LastAppliedConfigAnnotation])
+
+	versionedObject, err := scheme.Scheme.New(obj.GroupVersionKind())
+
+	switch {
+	case err == nil:
+		lookupPatchMeta, err := strategicpatch.NewPatchMetaFromStruct(versionedObject)
+		if err != nil {
+			return "", nil, err
 		}
 
-		return "", err
+		patch, err := strategicpatch.CreateThreeWayMergePatch(original, modifiedJSON, currentJSON, lookupPatchMeta, true)
+
+		return types.StrategicMergePatchType, patch, err
+	case runtime.IsNotRegisteredError(err):
+		patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(original, modifiedJSON, currentJSON)
+
+		return types.MergePatchType, patch, err
+	default:
+		return "", nil, err
 	}
+}
 
-	obj.SetResourceVersion(current.GetResourceVersion())
+// withLastAppliedConfiguration returns a copy of the object with the last applied configuration annotation set.
+func withLastAppliedConfiguration(obj Manifest) (Manifest, error) {
+	obj = obj.DeepCopy()
 
-	resp, err := dr.Update(ctx, obj, metav1.UpdateOptions{
-		DryRun: []string{"All"},
-	})
+	annotations := obj.GetAnnotations()
+	delete(annotations, v1.LastAppliedConfigAnnotation)
+	obj.SetAnnotations(annotations)
+
+	lastApplied, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[v1.LastAppliedConfigAnnotation] = string(lastApplied)
+	obj.SetAnnotations(annotations)
+
+	return obj, nil
+}
+
+// resourceClient returns the dynamic client for the object's resource.
+func resourceClient(mapper meta.RESTMapper, k8sClient dynamic.Interface, obj Manifest) (dynamic.ResourceInterface, error) {
+	mapping, err := mapper.RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("error creating mapping for object %s: %w", obj.GetName(), err)
 	}
 
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		// namespaced resources should specify the namespace
+		return k8sClient.Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+	}
+
+	// for cluster-wide resources
+	return k8sClient.Resource(mapping.Resource), nil
+}
+
+// resourceDiff computes the diff between the live object and the result of the update
+// ignoring the fields managed by Kubernetes.
+func resourceDiff(current, resp Manifest) (string, error) {
+	current = current.DeepCopy()
+	resp = resp.DeepCopy()
+
 	ignoreKey := func(key ...string) {
 		unstructured.RemoveNestedField(current.Object, key...)
 		unstructured.RemoveNestedField(resp.Object, key...)
@@ -0,0 +1,671 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+func TestUpdateManifestLastApplied(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+	for _, test := range []struct {
+		name        string
+		annotations string
+
+		expectedPatch bool
+	}{
+		{
+			name:          "created before the last applied configuration was recorded",
+			expectedPatch: true,
+		},
+		{
+			name:        "last applied configuration recorded",
+			annotations: `,"annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{}"}`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, ctxCancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer ctxCancel()
+
+			var (
+				mu      sync.Mutex
+				patches []string
+			)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/namespaces/default/configmaps/cm" {
+					w.WriteHeader(http.StatusNotFound)
+
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+
+				switch r.Method {
+				case http.MethodGet:
+					//nolint:errcheck
+					w.Write([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"default","resourceVersion":"1"` +
+						test.annotations + `},"data":{"a":"1"}}`))
+				case http.MethodPatch:
+					if r.URL.Query().Get("dryRun") == "" {
+						body, _ := io.ReadAll(r.Body) //nolint:errcheck
+
+						mu.Lock()
+						patches = append(patches, string(body))
+						mu.Unlock()
+					}
+
+					// the patch only adds the last applied configuration, which is not part of the diff
+					//nolint:errcheck
+					w.Write([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"default","resourceVersion":"2",` +
+						`"annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{}"}},"data":{"a":"1"}}`))
+				default:
+					w.WriteHeader(http.StatusMethodNotAllowed)
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			k8sClient, err := dynamic.NewForConfig(&rest.Config{Host: srv.URL})
+			require.NoError(t, err)
+
+			obj := &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]any{
+					"name":      "cm",
+					"namespace": "default",
+				},
+				"data": map[string]any{
+					"a": "1",
+				},
+			}}
+
+			_, diff, skipped, err := manifests.UpdateManifest(ctx, mapper, k8sClient, obj, false, manifests.UpdateStrategyThreeWayMerge)
+			require.NoError(t, err)
+
+			assert.Empty(t, diff)
+			assert.True(t, skipped)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if !test.expectedPatch {
+				assert.Empty(t, patches)
+
+				return
+			}
+
+			require.Len(t, patches, 1)
+			assert.Contains(t, patches[0], "last-applied-configuration")
+		})
+	}
+}
+
+// appliedObject returns the live object which was applied from the lastApplied manifest, and then modified to match live.
+func appliedObject(t *testing.T, lastApplied, live string) manifests.Manifest {
+	t.Helper()
+
+	lastAppliedJSON, err := parseManifests(t, lastApplied)[0].MarshalJSON()
+	require.NoError(t, err)
+
+	obj := parseManifests(t, live)[0]
+	obj.SetAnnotations(map[string]string{corev1.LastAppliedConfigAnnotation: string(lastAppliedJSON)})
+
+	return obj
+}
+
+func TestSyncThreeWayMerge(t *testing.T) {
+	for _, test := range []struct {
+		name string
+
+		live     []manifests.Manifest
+		manifest string
+		path     string
+
+		expectedPatchType types.PatchType
+		// expectedData is the expected value of the top-level field of the live object after the sync
+		expectedField string
+		expectedData  map[string]any
+	}{
+		{
+			name: "strategic merge patch",
+			live: []manifests.Manifest{
+				appliedObject(t, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+data:
+  a: "1"
+  b: "2"
+`, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+data:
+  a: "1"
+  b: "2"
+  c: set-by-controller
+`),
+			},
+			manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+data:
+  a: "10"
+`,
+			path: "/api/v1/namespaces/default/configmaps/config",
+
+			expectedPatchType: types.StrategicMergePatchType,
+			// "b" was removed from the manifest, "c" is not managed by the manifest
+			expectedField: "data",
+			expectedData:  map[string]any{"a": "10", "c": "set-by-controller"},
+		},
+		{
+			name: "JSON merge patch",
+			live: append(parseManifests(t, widgetManifests)[1:], appliedObject(t, `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: default
+spec:
+  size: 3
+  color: red
+`, `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: default
+spec:
+  size: 3
+  color: red
+  owner: set-by-controller
+`)),
+			manifest: `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: default
+spec:
+  size: 5
+`,
+			path: widgetPath,
+
+			expectedPatchType: types.MergePatchType,
+			expectedField:     "spec",
+			expectedData:      map[string]any{"size": float64(5), "owner": "set-by-controller"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, ctxCancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer ctxCancel()
+
+			fake, config := newFakeAPIServer(t, test.live...)
+
+			if fake.get(widgetCRDPath) != nil {
+				establishCRD(fake, widgetCRDPath)
+			}
+
+			var patchTypes []string
+
+			fake.intercept = func(_ http.ResponseWriter, r *http.Request) bool {
+				if r.Method == http.MethodPatch {
+					patchTypes = append(patchTypes, r.Header.Get("Content-Type"))
+				}
+
+				return false
+			}
+
+			results, err := syncObjects(ctx, config, parseManifests(t, test.manifest), false)
+			require.NoError(t, err)
+
+			require.Len(t, results, 1)
+			assert.False(t, results[0].Skipped)
+			assert.NotEmpty(t, results[0].Diff)
+
+			// dry run first to compute the diff, then the actual patch
+			assert.Equal(t, []string{string(test.expectedPatchType), string(test.expectedPatchType)}, patchTypes)
+
+			obj := fake.get(test.path)
+			require.NotNil(t, obj)
+
+			assert.Equal(t, test.expectedData, obj[test.expectedField])
+		})
+	}
+}
+
+// fakeResource is a resource served by fakeAPIServer.
+type fakeResource struct {
+	group, version, resource, kind string
+	namespaced                     bool
+	// crd is the name of the CRD which should be established for the resource to be served
+	crd string
+}
+
+var fakeResources = []fakeResource{
+	{version: "v1", resource: "namespaces", kind: "Namespace"},
+	{version: "v1", resource: "configmaps", kind: "ConfigMap", namespaced: true},
+	{version: "v1", resource: "secrets", kind: "Secret", namespaced: true},
+	{version: "v1", resource: "services", kind: "Service", namespaced: true},
+	{version: "v1", resource: "pods", kind: "Pod", namespaced: true},
+	{version: "v1", resource: "events", kind: "Event", namespaced: true},
+	{group: "apps", version: "v1", resource: "deployments", kind: "Deployment", namespaced: true},
+	{group: "apiextensions.k8s.io", version: "v1", resource: "customresourcedefinitions", kind: "CustomResourceDefinition"},
+	{group: "example.com", version: "v1", resource: "widgets", kind: "Widget", namespaced: true, crd: "widgets.example.com"},
+}
+
+// fakeRequest is a request recorded by fakeAPIServer.
+type fakeRequest struct {
+	Method string
+	Path   string
+	DryRun bool
+	Body   string
+}
+
+// fakeAPIServer is a minimal in-memory Kubernetes API server for the Sync tests.
+//
+// It serves the discovery of fakeResources, and GET/POST/PUT/PATCH/DELETE of single objects keyed by their URL path.
+type fakeAPIServer struct {
+	// intercept is called before the request is handled, if it returns true, the request is considered handled
+	intercept func(w http.ResponseWriter, r *http.Request) bool
+
+	objects  map[string]map[string]any
+	requests []fakeRequest
+
+	mu sync.Mutex
+
+	resourceVersion int
+}
+
+func newFakeAPIServer(t *testing.T, objects ...manifests.Manifest) (*fakeAPIServer, *rest.Config) {
+	t.Helper()
+
+	fake := &fakeAPIServer{
+		objects: map[string]map[string]any{},
+	}
+
+	for _, obj := range objects {
+		fake.objects[fake.objectPath(obj)] = obj.DeepCopy().Object
+	}
+
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	return fake, &rest.Config{Host: srv.URL}
+}
+
+func (s *fakeAPIServer) objectPath(obj manifests.Manifest) string {
+	gvk := obj.GroupVersionKind()
+
+	for _, res := range fakeResources {
+		if res.group == gvk.Group && res.version == gvk.Version && res.kind == gvk.Kind {
+			return res.collectionPath(obj.GetNamespace()) + "/" + obj.GetName()
+		}
+	}
+
+	panic("unknown kind " + gvk.String())
+}
+
+func (r fakeResource) prefix() string {
+	if r.group == "" {
+		return "/api/" + r.version
+	}
+
+	return "/apis/" + r.group + "/" + r.version
+}
+
+func (r fakeResource) collectionPath(namespace string) string {
+	if r.namespaced {
+		return r.prefix() + "/namespaces/" + namespace + "/" + r.resource
+	}
+
+	return r.prefix() + "/" + r.resource
+}
+
+// get returns a copy of the stored object.
+func (s *fakeAPIServer) get(path string) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if obj, ok := s.objects[path]; ok {
+		return runtime.DeepCopyJSON(obj)
+	}
+
+	return nil
+}
+
+// set stores the object.
+func (s *fakeAPIServer) set(path string, obj map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects[path] = obj
+}
+
+// recorded returns the recorded write requests (everything but GET).
+func (s *fakeAPIServer) recorded() []fakeRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+func (s *fakeAPIServer) served(res fakeResource) bool {
+	if res.crd == "" {
+		return true
+	}
+
+	crd, ok := s.objects["/apis/apiextensions.k8s.io/v1/customresourcedefinitions/"+res.crd]
+	if !ok {
+		return false
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(crd, "status", "conditions") //nolint:errcheck
+
+	return slices.ContainsFunc(conditions, func(c any) bool {
+		condition, _ := c.(map[string]any) //nolint:errcheck
+
+		return condition["type"] == "Established" && condition["status"] == "True"
+	})
+}
+
+func (s *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.intercept != nil && s.intercept(w, r) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/api":
+		writeJSON(w, http.StatusOK, map[string]any{"kind": "APIVersions", "versions": []string{"v1"}})
+
+		return
+	case "/apis":
+		var groups []any
+
+		for _, res := range fakeResources {
+			if res.group == "" || !s.served(res) {
+				continue
+			}
+
+			gv := map[string]any{"groupVersion": res.group + "/" + res.version, "version": res.version}
+
+			groups = append(groups, map[string]any{"name": res.group, "versions": []any{gv}, "preferredVersion": gv})
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{"kind": "APIGroupList", "apiVersion": "v1", "groups": groups})
+
+		return
+	}
+
+	var resources []any
+
+	for _, res := range fakeResources {
+		if r.URL.Path == res.prefix() && s.served(res) {
+			resources = append(resources, map[string]any{
+				"name": res.resource, "singularName": "", "namespaced": res.namespaced, "kind": res.kind,
+				"verbs": []string{"get", "list", "create", "update", "patch", "delete"},
+			})
+		}
+	}
+
+	if resources != nil {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"kind": "APIResourceList", "apiVersion": "v1", "groupVersion": strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/"), "/apis/"),
+			"resources": resources,
+		})
+
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body) //nolint:errcheck
+	dryRun := r.URL.Query().Get("dryRun") != ""
+
+	if r.Method != http.MethodGet {
+		s.requests = append(s.requests, fakeRequest{Method: r.Method, Path: r.URL.Path, DryRun: dryRun, Body: string(body)})
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if list := s.list(r.URL.Path); list != nil {
+			writeJSON(w, http.StatusOK, list)
+
+			return
+		}
+
+		obj, ok := s.objects[r.URL.Path]
+		if !ok {
+			writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound)
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, obj)
+	case http.MethodPost:
+		var obj map[string]any
+
+		if err := json.Unmarshal(body, &obj); err != nil {
+			writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest)
+
+			return
+		}
+
+		name, _, _ := unstructured.NestedString(obj, "metadata", "name") //nolint:errcheck
+		path := r.URL.Path + "/" + name
+
+		if _, ok := s.objects[path]; ok {
+			writeStatus(w, http.StatusConflict, metav1.StatusReasonAlreadyExists)
+
+			return
+		}
+
+		s.write(path, obj, dryRun)
+		unstructured.SetNestedField(obj, "uid-"+name, "metadata", "uid") //nolint:errcheck
+
+		writeJSON(w, http.StatusCreated, obj)
+	case http.MethodPut, http.MethodPatch:
+		current, ok := s.objects[r.URL.Path]
+		if !ok {
+			writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound)
+
+			return
+		}
+
+		obj, err := applyPatch(r.Header.Get("Content-Type"), current, body)
+		if err != nil {
+			writeStatus(w, http.StatusUnprocessableEntity, metav1.StatusReasonInvalid)
+
+			return
+		}
+
+		s.write(r.URL.Path, obj, dryRun)
+
+		writeJSON(w, http.StatusOK, obj)
+	case http.MethodDelete:
+		if _, ok := s.objects[r.URL.Path]; !ok {
+			writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound)
+
+			return
+		}
+
+		if !dryRun {
+			delete(s.objects, r.URL.Path)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{"kind": "Status", "apiVersion": "v1", "status": metav1.StatusSuccess})
+	default:
+		writeStatus(w, http.StatusMethodNotAllowed, metav1.StatusReasonMethodNotAllowed)
+	}
+}
+
+// list returns the list of the stored objects if the path is a collection, the selectors are ignored.
+func (s *fakeAPIServer) list(path string) map[string]any {
+	for _, res := range fakeResources {
+		if !strings.HasPrefix(path, res.prefix()+"/") || !strings.HasSuffix(path, "/"+res.resource) {
+			continue
+		}
+
+		items := []any{}
+
+		for _, objPath := range slices.Sorted(maps.Keys(s.objects)) {
+			if name, ok := strings.CutPrefix(objPath, path+"/"); ok && !strings.Contains(name, "/") {
+				items = append(items, s.objects[objPath])
+			}
+		}
+
+		return map[string]any{"kind": res.kind + "List", "apiVersion": strings.TrimPrefix(res.group+"/"+res.version, "/"), "items": items}
+	}
+
+	return nil
+}
+
+// write stores the object bumping its resource version, the object is updated in place.
+func (s *fakeAPIServer) write(path string, obj map[string]any, dryRun bool) {
+	s.resourceVersion++
+
+	unstructured.SetNestedField(obj, strconv.Itoa(s.resourceVersion), "metadata", "resourceVersion") //nolint:errcheck
+
+	if !dryRun {
+		s.objects[path] = runtime.DeepCopyJSON(obj)
+	}
+}
+
+// applyPatch applies the update or the patch to the object the way the API server does.
+func applyPatch(contentType string, current map[string]any, body []byte) (map[string]any, error) {
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+
+	switch types.PatchType(contentType) {
+	case types.StrategicMergePatchType:
+		apiVersion, _, _ := unstructured.NestedString(current, "apiVersion") //nolint:errcheck
+		kind, _, _ := unstructured.NestedString(current, "kind")             //nolint:errcheck
+
+		typed, err := scheme.Scheme.New(schema.FromAPIVersionAndKind(apiVersion, kind))
+		if err != nil {
+			return nil, err
+		}
+
+		if patched, err = strategicpatch.StrategicMergePatch(currentJSON, body, typed); err != nil {
+			return nil, err
+		}
+	case types.MergePatchType:
+		var patch map[string]any
+
+		if err = json.Unmarshal(body, &patch); err != nil {
+			return nil, err
+		}
+
+		return mergePatch(runtime.DeepCopyJSON(current), patch), nil
+	default:
+		patched = body
+	}
+
+	var obj map[string]any
+
+	return obj, json.Unmarshal(patched, &obj)
+}
+
+// mergePatch applies the JSON merge patch (RFC 7386).
+func mergePatch(dst, patch map[string]any) map[string]any {
+	for k, v := range patch {
+		switch v := v.(type) {
+		case nil:
+			delete(dst, k)
+		case map[string]any:
+			dstMap, _ := dst[k].(map[string]any) //nolint:errcheck
+			if dstMap == nil {
+				dstMap = map[string]any{}
+			}
+
+			dst[k] = mergePatch(dstMap, v)
+		default:
+			dst[k] = v
+		}
+	}
+
+	return dst
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	json.NewEncoder(w).Encode(v) //nolint:errcheck,errchkjson
+}
+
+func writeStatus(w http.ResponseWriter, code int, reason metav1.StatusReason) {
+	writeJSON(w, code, map[string]any{
+		"kind": "Status", "apiVersion": "v1", "status": metav1.StatusFailure, "code": code, "reason": reason, "message": string(reason),
+	})
+}
+
+// syncObjects runs Sync collecting the results.
+func syncObjects(ctx context.Context, config *rest.Config, objects []manifests.Manifest, dryRun bool, opts ...manifests.SyncOption) ([]manifests.SyncResult, error) {
+	resultCh := make(chan manifests.SyncResult)
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- manifests.Sync(ctx, objects, config, dryRun, resultCh, opts...)
+	}()
+
+	var results []manifests.SyncResult
+
+	for {
+		select {
+		case result := <-resultCh:
+			results = append(results, result)
+		case err := <-errCh:
+			return results, err
+		}
+	}
+}
+
+// parseManifests parses the YAML manifests failing the test on error.
+func parseManifests(t *testing.T, data string) []manifests.Manifest {
+	t.Helper()
+
+	objects, err := manifests.Parse([]byte(data))
+	require.NoError(t, err)
+
+	return objects
+}
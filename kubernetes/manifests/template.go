@@ -0,0 +1,138 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+
+	k8syaml "sigs.k8s.io/yaml"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+)
+
+// ClusterFacts describes the cluster the manifests are rendered for.
+type ClusterFacts struct {
+	ClusterName       string
+	PodCIDRs          []string
+	ServiceCIDRs      []string
+	KubernetesVersion compatibility.Version
+}
+
+// Render executes the manifest template substituting the cluster facts.
+//
+// The template is a Go text/template with a subset of sprig functions
+// which have no side effects (no environment, filesystem or randomness).
+// Referencing a missing key is an error.
+func Render(name, text string, facts ClusterFacts) ([]byte, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(templateFuncs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template %q: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+
+	if err = tmpl.Execute(&buf, facts); err != nil {
+		return nil, fmt.Errorf("error rendering template %q: %w", name, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// RenderManifests renders the manifest template and parses the result.
+func RenderManifests(name, text string, facts ClusterFacts) ([]Manifest, error) {
+	data, err := Render(name, text, facts)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"default":    defaultValue,
+		"empty":      isEmpty,
+		"required":   required,
+		"quote":      func(s any) string { return fmt.Sprintf("%q", fmt.Sprint(s)) },
+		"squote":     func(s any) string { return "'" + fmt.Sprint(s) + "'" },
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"join":       func(sep string, elems []string) string { return strings.Join(elems, sep) },
+		"indent":     indent,
+		"nindent":    func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+		"toYaml":     toYAML,
+		"toJson":     toJSON,
+	}
+}
+
+func defaultValue(def, value any) any {
+	if isEmpty(value) {
+		return def
+	}
+
+	return value
+}
+
+func isEmpty(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return rv.IsZero()
+	}
+}
+
+func required(msg string, value any) (any, error) {
+	if isEmpty(value) {
+		return nil, errors.New(msg)
+	}
+
+	return value, nil
+}
+
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+func toYAML(value any) (string, error) {
+	out, err := k8syaml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func toJSON(value any) (string, error) {
+	out, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,65 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+func TestRenderManifests(t *testing.T) {
+	facts := manifests.ClusterFacts{
+		ClusterName:       "talos-default",
+		PodCIDRs:          []string{"10.244.0.0/16", "fd00:10:244::/56"},
+		ServiceCIDRs:      []string{"10.96.0.0/12"},
+		KubernetesVersion: compatibility.Version{Major: 1, Minor: 31, Patch: 2},
+	}
+
+	objects, err := manifests.RenderManifests("test", `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .ClusterName | lower }}-config
+  namespace: {{ default "kube-system" "" }}
+data:
+  version: {{ .KubernetesVersion | quote }}
+  podCIDRs: {{ join "," .PodCIDRs | quote }}
+  authz: {{ .KubernetesVersion.KubeAPIServerSupportsAuthorizationConfigFile | quote }}
+---
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: {{ required "cluster name is required" .ClusterName }}
+`, facts)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+
+	assert.Equal(t, "talos-default-config", objects[0].GetName())
+	assert.Equal(t, "kube-system", objects[0].GetNamespace())
+	assert.Equal(t, map[string]any{
+		"version":  "1.31.2",
+		"podCIDRs": "10.244.0.0/16,fd00:10:244::/56",
+		"authz":    "true",
+	}, objects[0].Object["data"])
+
+	assert.Equal(t, "Namespace", objects[1].GetKind())
+	assert.Equal(t, "talos-default", objects[1].GetName())
+}
+
+func TestRenderErrors(t *testing.T) {
+	_, err := manifests.Render("missing", `{{ .Unknown }}`, manifests.ClusterFacts{})
+	require.Error(t, err)
+
+	_, err = manifests.Render("required", `{{ required "cluster name is required" .ClusterName }}`, manifests.ClusterFacts{})
+	require.ErrorContains(t, err, "cluster name is required")
+}
@@ -0,0 +1,246 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	memory "k8s.io/client-go/discovery/cached"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes"
+)
+
+// validateFieldManager is the field manager used for the dry-run validation requests.
+const validateFieldManager = "manifests-validate"
+
+// ValidationProblem describes a single problem found by Validate.
+type ValidationProblem struct {
+	Path    string
+	Message string
+}
+
+// ValidationError is returned by Validate listing all found problems.
+type ValidationError struct {
+	Problems []ValidationProblem
+}
+
+// Error implements error interface.
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%d manifest validation problem(s):", len(e.Problems))
+
+	for _, problem := range e.Problems {
+		fmt.Fprintf(&sb, "\n  %s: %s", problem.Path, problem.Message)
+	}
+
+	return sb.String()
+}
+
+// Validate checks the objects before they are synced to the cluster.
+//
+// The following checks are performed, and all found problems are returned as ValidationError:
+//   - required metadata (apiVersion, kind, name, namespace for namespaced kinds);
+//   - duplicate objects (same group, kind, namespace and name) in the set;
+//   - kinds which are not served by the cluster and not defined by a CRD in the set;
+//   - schema validation against the cluster OpenAPI (server-side dry-run with strict field validation).
+func Validate(ctx context.Context, objects []Manifest, config *rest.Config) error {
+	config = rest.CopyConfig(config)
+
+	dialer := kubernetes.NewDialer()
+	config.Dial = dialer.DialContext
+
+	defer dialer.CloseAll()
+
+	k8sClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	dc, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
+
+	return validate(ctx, objects, k8sClient, mapper)
+}
+
+func validate(ctx context.Context, objects []Manifest, k8sClient dynamic.Interface, mapper meta.RESTMapper) error {
+	problems := validateMetadata(objects)
+	definedKinds := crdDefinedKinds(objects)
+	namespaces := definedNamespaces(objects)
+
+	for _, obj := range objects {
+		if obj.GetKind() == "" || obj.GetAPIVersion() == "" || obj.GetName() == "" {
+			// already reported
+			continue
+		}
+
+		gvk := obj.GroupVersionKind()
+
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			if !meta.IsNoMatchError(err) {
+				return err
+			}
+
+			if _, ok := definedKinds[gvk.GroupKind()]; !ok {
+				problems = append(problems, ValidationProblem{Path: manifestPath(obj), Message: fmt.Sprintf("kind %s is not served by the cluster", gvk)})
+			}
+
+			continue
+		}
+
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace && obj.GetNamespace() == "" {
+			problems = append(problems, ValidationProblem{Path: manifestPath(obj), Message: "namespace is required for a namespaced kind"})
+
+			continue
+		}
+
+		problem, err := validateSchema(ctx, k8sClient, mapping, obj, namespaces)
+		if err != nil {
+			return err
+		}
+
+		if problem != "" {
+			problems = append(problems, ValidationProblem{Path: manifestPath(obj), Message: problem})
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+// validateMetadata checks the required metadata and duplicate objects.
+func validateMetadata(objects []Manifest) []ValidationProblem {
+	var problems []ValidationProblem
+
+	seen := make(map[string]struct{}, len(objects))
+
+	for i, obj := range objects {
+		path := manifestPath(obj)
+
+		var missing []string
+
+		for _, field := range []struct {
+			name  string
+			value string
+		}{
+			{"apiVersion", obj.GetAPIVersion()},
+			{"kind", obj.GetKind()},
+			{"metadata.name", obj.GetName()},
+		} {
+			if field.value == "" {
+				missing = append(missing, field.name)
+			}
+		}
+
+		if len(missing) > 0 {
+			problems = append(problems, ValidationProblem{Path: fmt.Sprintf("#%d %s", i, path), Message: "missing required fields: " + strings.Join(missing, ", ")})
+
+			continue
+		}
+
+		key := objectKey(obj)
+
+		if _, ok := seen[key]; ok {
+			problems = append(problems, ValidationProblem{Path: path, Message: "duplicate object"})
+
+			continue
+		}
+
+		seen[key] = struct{}{}
+	}
+
+	return problems
+}
+
+// crdDefinedKinds returns the kinds defined by the CRDs in the set.
+func crdDefinedKinds(objects []Manifest) map[schema.GroupKind]struct{} {
+	kinds := map[schema.GroupKind]struct{}{}
+
+	for _, obj := range objects {
+		if !isCRD(obj) {
+			continue
+		}
+
+		group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+
+		kinds[schema.GroupKind{Group: group, Kind: kind}] = struct{}{}
+	}
+
+	return kinds
+}
+
+// definedNamespaces returns the names of the Namespace objects in the set.
+func definedNamespaces(objects []Manifest) []string {
+	var namespaces []string
+
+	for _, obj := range objects {
+		if obj.GetKind() == "Namespace" && obj.GroupVersionKind().Group == "" {
+			namespaces = append(namespaces, obj.GetName())
+		}
+	}
+
+	return namespaces
+}
+
+// validateSchema runs a server-side apply with strict field validation in dry-run mode.
+//
+// It returns a problem description if the object is rejected by the validation.
+func validateSchema(ctx context.Context, k8sClient dynamic.Interface, mapping *meta.RESTMapping, obj Manifest, definedNamespaces []string) (string, error) {
+	var dr dynamic.ResourceInterface = k8sClient.Resource(mapping.Resource)
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		dr = k8sClient.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+	}
+
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+
+	force := true
+
+	_, err = dr.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+		DryRun:          []string{metav1.DryRunAll},
+		Force:           &force,
+		FieldManager:    validateFieldManager,
+		FieldValidation: metav1.FieldValidationStrict,
+	})
+
+	switch {
+	case err == nil:
+		return "", nil
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return err.Error(), nil
+	case apierrors.IsNotFound(err) && slices.Contains(definedNamespaces, obj.GetNamespace()):
+		// the namespace doesn't exist yet, but it is created by the set
+		return "", nil
+	case apierrors.IsNotFound(err):
+		return fmt.Sprintf("namespace %q doesn't exist", obj.GetNamespace()), nil
+	default:
+		return "", err
+	}
+}
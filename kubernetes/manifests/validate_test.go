@@ -0,0 +1,101 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+func configMap(namespace string) manifests.Manifest {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":      "cm",
+			"namespace": namespace,
+		},
+	}}
+}
+
+func namespace(name string) manifests.Manifest {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata": map[string]any{
+			"name": name,
+		},
+	}}
+}
+
+func TestValidateNamespaces(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+
+	for _, test := range []struct {
+		name    string
+		objects []manifests.Manifest
+
+		expectedProblems []manifests.ValidationProblem
+	}{
+		{
+			name:    "existing namespace",
+			objects: []manifests.Manifest{configMap("default")},
+		},
+		{
+			name:    "namespace in the set",
+			objects: []manifests.Manifest{namespace("apps"), configMap("apps")},
+		},
+		{
+			name:    "missing namespace",
+			objects: []manifests.Manifest{namespace("apps"), configMap("missing")},
+
+			expectedProblems: []manifests.ValidationProblem{
+				{Path: "v1.ConfigMap/missing/cm", Message: `namespace "missing" doesn't exist`},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			k8sClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+			k8sClient.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if ns := action.GetNamespace(); ns != "" && ns != "default" {
+					return true, nil, apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, ns)
+				}
+
+				return true, &unstructured.Unstructured{}, nil
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			t.Cleanup(cancel)
+
+			err := manifests.ValidateWithClients(ctx, test.objects, k8sClient, mapper)
+
+			if test.expectedProblems == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			var validationErr *manifests.ValidationError
+
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, test.expectedProblems, validationErr.Problems)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests
+
+import (
+	"slices"
+	"sync"
+)
+
+// warningCollector collects the warnings returned by the API server.
+//
+// It implements rest.WarningHandler.
+type warningCollector struct {
+	warnings []string
+	mu       sync.Mutex
+}
+
+// HandleWarningHeader implements rest.WarningHandler.
+func (c *warningCollector) HandleWarningHeader(code int, _, message string) {
+	if code != 299 || message == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !slices.Contains(c.warnings, message) {
+		c.warnings = append(c.warnings, message)
+	}
+}
+
+func (c *warningCollector) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.warnings = nil
+}
+
+func (c *warningCollector) get() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return slices.Clone(c.warnings)
+}
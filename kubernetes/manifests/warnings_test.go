@@ -0,0 +1,71 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package manifests_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/manifests"
+)
+
+func TestSyncWarnings(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		dryRun bool
+	}{
+		{
+			name: "apply",
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, ctxCancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer ctxCancel()
+
+			fake, config := newFakeAPIServer(t)
+
+			fake.intercept = func(w http.ResponseWriter, r *http.Request) bool {
+				if r.URL.Path == "/api/v1/namespaces/default/configmaps/config-1" {
+					// repeated warnings are reported once, warnings with other codes are ignored
+					w.Header().Add("Warning", `299 - "data key is deprecated"`)
+					w.Header().Add("Warning", `299 - "data key is deprecated"`)
+					w.Header().Add("Warning", `199 - "miscellaneous warning"`)
+				}
+
+				return false
+			}
+
+			results, err := syncObjects(ctx, config, configMaps(3), test.dryRun, manifests.WithConcurrency(3))
+			require.NoError(t, err)
+			require.Len(t, results, 3)
+
+			for i, result := range results {
+				if i == 1 {
+					assert.Equal(t, []string{"data key is deprecated"}, result.Warnings)
+				} else {
+					assert.Empty(t, result.Warnings, "unexpected warnings for %s", result.Path)
+				}
+
+				if test.dryRun {
+					assert.Empty(t, result.ResourceVersion)
+					assert.Empty(t, result.UID)
+				} else {
+					assert.NotEmpty(t, result.ResourceVersion)
+					assert.Equal(t, types.UID("uid-"+result.Object.GetName()), result.UID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/siderolabs/go-retry/retry"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/internal/healthz"
+)
+
+// WaitForAPIServerReady polls kube-apiserver /readyz endpoint until it reports ready, or the timeout expires.
+//
+// Authentication and authorization errors are returned immediately, as they don't go away with retries.
+// On timeout the error includes the readiness checks which were failing on the last attempt.
+func WaitForAPIServerReady(ctx context.Context, config *rest.Config, timeout time.Duration) error {
+	client, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return fmt.Errorf("error building discovery client: %w", err)
+	}
+
+	var failedChecks []string
+
+	err = retry.Constant(timeout, retry.WithUnits(time.Second)).RetryWithContext(ctx, func(ctx context.Context) error {
+		body, err := client.RESTClient().Get().AbsPath("/readyz").Param("verbose", "").Do(ctx).Raw()
+		if err == nil {
+			return nil
+		}
+
+		if apierrors.IsUnauthorized(err) || apierrors.IsForbidden(err) {
+			return err
+		}
+
+		// keep the checks from the last response with a body, transport errors have none
+		if len(body) > 0 {
+			failedChecks = healthz.FailedChecks(body)
+		}
+
+		return retry.ExpectedError(err)
+	})
+	if err != nil {
+		if len(failedChecks) > 0 {
+			return fmt.Errorf("kube-apiserver is not ready (failed checks: %s): %w", strings.Join(failedChecks, ", "), err)
+		}
+
+		return fmt.Errorf("kube-apiserver is not ready: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubernetes_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes"
+)
+
+const notReadyOutput = `[+]ping ok
+[+]log ok
+[-]etcd failed: reason withheld
+[+]poststarthook/start-apiserver-admission-initializer ok
+[-]poststarthook/rbac/bootstrap-roles failed: reason withheld
+readyz check failed
+`
+
+func TestWaitForAPIServerReady(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/readyz" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		if requests.Add(1) < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(notReadyOutput)) //nolint:errcheck
+
+			return
+		}
+
+		w.Write([]byte("ok")) //nolint:errcheck
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+
+	require.NoError(t, kubernetes.WaitForAPIServerReady(ctx, &rest.Config{Host: srv.URL}, 10*time.Second))
+	assert.EqualValues(t, 2, requests.Load())
+}
+
+func TestWaitForAPIServerReadyTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(notReadyOutput)) //nolint:errcheck
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+
+	err := kubernetes.WaitForAPIServerReady(ctx, &rest.Config{Host: srv.URL}, 2*time.Second)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "failed checks: etcd failed: reason withheld, poststarthook/rbac/bootstrap-roles failed: reason withheld")
+}
+
+func TestWaitForAPIServerReadyConnectionError(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(notReadyOutput)) //nolint:errcheck
+
+			return
+		}
+
+		// the following attempts fail without a response
+		conn, _, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+
+		conn.Close() //nolint:errcheck
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+
+	// the failed checks of the last response are reported
+	err := kubernetes.WaitForAPIServerReady(ctx, &rest.Config{Host: srv.URL}, 3*time.Second)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "failed checks: etcd failed: reason withheld, poststarthook/rbac/bootstrap-roles failed: reason withheld")
+	assert.Greater(t, requests.Load(), int32(1))
+}
+
+func TestWaitForAPIServerReadyUnauthorized(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+
+	start := time.Now()
+
+	require.Error(t, kubernetes.WaitForAPIServerReady(ctx, &rest.Config{Host: srv.URL}, 10*time.Second))
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,173 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/siderolabs/gen/xslices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// apiResourcesPageSize is the number of objects fetched per list request.
+	apiResourcesPageSize = 500
+	// apiResourceSamples is the number of object names reported for each resource.
+	apiResourceSamples = 5
+)
+
+// apiResourceCount is the number of objects of an API resource with some of the object names.
+type apiResourceCount struct {
+	samples []string
+	count   int
+}
+
+// countAPIResources returns the number of objects for each of the API resources which are served.
+//
+// The resources which are not served (according to the discovery) are not listed at all.
+// Only the object metadata is fetched in pages, and the pages are not fetched at all if the API server
+// reports the number of the remaining objects.
+func countAPIResources(ctx context.Context, k8sConfig *rest.Config, resources []string) (map[string]apiResourceCount, error) {
+	if len(resources) == 0 || k8sConfig == nil {
+		return map[string]apiResourceCount{}, nil
+	}
+
+	k8sClient, dc, err := newMetadataClients(k8sConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]apiResourceCount, len(resources))
+	served := map[schema.GroupVersion][]string{}
+
+	for _, resource := range resources {
+		gvr, _ := schema.ParseResourceArg(resource)
+
+		if gvr == nil {
+			return nil, fmt.Errorf("failed to parse group version resource %s", resource)
+		}
+
+		servedResources, ok := served[gvr.GroupVersion()]
+		if !ok {
+			servedResources, err = servedGroupVersionResources(dc, gvr.GroupVersion())
+			if err != nil {
+				return nil, err
+			}
+
+			served[gvr.GroupVersion()] = servedResources
+		}
+
+		if !slices.Contains(servedResources, gvr.Resource) {
+			continue
+		}
+
+		count, err := countAPIResource(ctx, k8sClient, *gvr)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		counts[resource] = count
+	}
+
+	return counts, nil
+}
+
+// newMetadataClients builds the metadata and discovery clients, the deprecation warnings are discarded.
+func newMetadataClients(k8sConfig *rest.Config) (metadata.Interface, discovery.DiscoveryInterface, error) {
+	// copy the config to avoid mutating input argument
+	k8sConfigCopy := *k8sConfig
+	k8sConfigCopy.WarningHandler = rest.NewWarningWriter(io.Discard, rest.WarningWriterOptions{})
+
+	k8sClient, err := metadata.NewForConfig(&k8sConfigCopy)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building kubernetes client: %w", err)
+	}
+
+	dc, err := discovery.NewDiscoveryClientForConfig(&k8sConfigCopy)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building discovery client: %w", err)
+	}
+
+	return k8sClient, dc, nil
+}
+
+// servedGroupVersionResources returns the resources served by the cluster for the group version.
+func servedGroupVersionResources(dc discovery.DiscoveryInterface, gv schema.GroupVersion) ([]string, error) {
+	list, err := dc.ServerResourcesForGroupVersion(gv.String())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return []string{}, nil
+		}
+
+		return nil, fmt.Errorf("error discovering resources for %s: %w", gv, err)
+	}
+
+	return xslices.Map(list.APIResources, func(r metav1.APIResource) string { return r.Name }), nil
+}
+
+func countAPIResource(ctx context.Context, k8sClient metadata.Interface, gvr schema.GroupVersionResource) (apiResourceCount, error) {
+	var result apiResourceCount
+
+	opts := metav1.ListOptions{Limit: apiResourcesPageSize}
+
+	for {
+		list, err := k8sClient.Resource(gvr).List(ctx, opts)
+		if err != nil {
+			return result, err
+		}
+
+		result.count += len(list.Items)
+
+		for _, item := range list.Items {
+			if len(result.samples) == apiResourceSamples {
+				break
+			}
+
+			name := item.Name
+
+			if item.Namespace != "" {
+				name = item.Namespace + "/" + name
+			}
+
+			result.samples = append(result.samples, name)
+		}
+
+		if list.RemainingItemCount != nil {
+			// the count is an estimate, but it's good enough for the report
+			result.count += int(*list.RemainingItemCount)
+
+			return result, nil
+		}
+
+		if list.Continue == "" {
+			return result, nil
+		}
+
+		opts.Continue = list.Continue
+	}
+}
+
+// formatSamples formats the object names as a message suffix.
+func formatSamples(samples []string) string {
+	if len(samples) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" (e.g. %s)", strings.Join(samples, ", "))
+}
@@ -0,0 +1,163 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+)
+
+// lastAppliedManager is the field manager reported for the kubectl client-side apply annotation.
+const lastAppliedManager = "kubectl (last-applied-configuration)"
+
+// appliedAPIVersion is the usage of a removed API version by the field managers of the objects.
+type appliedAPIVersion struct {
+	managers []string
+	samples  []string
+	count    int
+}
+
+// PopulateAppliedAPIVersions reports the objects which were applied using the removed API resource versions.
+//
+// The API server migrates the stored objects to the new API versions, but the manifests which
+// created them still use the removed versions and fail to re-apply after the upgrade.
+// The API version is taken from the managed fields and from the kubectl last-applied-configuration annotation,
+// the field managers which used the removed version are reported as warnings.
+func (e *ComponentRemovedItemsError) PopulateAppliedAPIVersions(ctx context.Context, k8sConfig *rest.Config, removedAPIResources []string) error {
+	if len(removedAPIResources) == 0 || k8sConfig == nil {
+		return nil
+	}
+
+	k8sClient, dc, err := newMetadataClients(k8sConfig)
+	if err != nil {
+		return err
+	}
+
+	served := map[schema.GroupVersion][]string{}
+
+	for _, resource := range removedAPIResources {
+		gvr, _ := schema.ParseResourceArg(resource)
+
+		if gvr == nil {
+			return fmt.Errorf("failed to parse group version resource %s", resource)
+		}
+
+		servedResources, ok := served[gvr.GroupVersion()]
+		if !ok {
+			servedResources, err = servedGroupVersionResources(dc, gvr.GroupVersion())
+			if err != nil {
+				return err
+			}
+
+			served[gvr.GroupVersion()] = servedResources
+		}
+
+		if !slices.Contains(servedResources, gvr.Resource) {
+			continue
+		}
+
+		usage, err := findAppliedAPIVersion(ctx, k8sClient, *gvr)
+		if err != nil {
+			if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+				continue
+			}
+
+			return err
+		}
+
+		if usage.count == 0 {
+			continue
+		}
+
+		e.Findings = append(e.Findings, Finding{
+			Check: CheckAppliedAPIVersions,
+			Message: fmt.Sprintf("%d object(s) of %s were applied using %s by %s%s", usage.count, gvr.GroupResource(), gvr.GroupVersion(),
+				strings.Join(usage.managers, ", "), formatSamples(usage.samples)),
+			Remediation: fmt.Sprintf("update the manifests applied by %s to a supported API version of %s", strings.Join(usage.managers, ", "), gvr.GroupResource()),
+			DocURL:      deprecationGuideURL,
+			Severity:    SeverityWarning,
+			Item:        resource,
+		})
+	}
+
+	return nil
+}
+
+// findAppliedAPIVersion lists the objects metadata in pages, and collects the objects applied using the resource version.
+func findAppliedAPIVersion(ctx context.Context, k8sClient metadata.Interface, gvr schema.GroupVersionResource) (appliedAPIVersion, error) {
+	var result appliedAPIVersion
+
+	apiVersion := gvr.GroupVersion().String()
+	opts := metav1.ListOptions{Limit: apiResourcesPageSize}
+
+	for {
+		list, err := k8sClient.Resource(gvr).List(ctx, opts)
+		if err != nil {
+			return result, err
+		}
+
+		for _, item := range list.Items {
+			managers := appliedManagers(&item.ObjectMeta, apiVersion)
+			if len(managers) == 0 {
+				continue
+			}
+
+			result.count++
+			result.managers = append(result.managers, managers...)
+
+			if len(result.samples) < apiResourceSamples {
+				name := item.Name
+
+				if item.Namespace != "" {
+					name = item.Namespace + "/" + name
+				}
+
+				result.samples = append(result.samples, name)
+			}
+		}
+
+		if list.Continue == "" {
+			break
+		}
+
+		opts.Continue = list.Continue
+	}
+
+	slices.Sort(result.managers)
+	result.managers = slices.Compact(result.managers)
+
+	return result, nil
+}
+
+// appliedManagers returns the field managers which applied the object using the API version.
+func appliedManagers(meta *metav1.ObjectMeta, apiVersion string) []string {
+	var managers []string
+
+	for _, entry := range meta.ManagedFields {
+		if entry.APIVersion == apiVersion {
+			managers = append(managers, entry.Manager)
+		}
+	}
+
+	if lastApplied, ok := meta.Annotations[v1.LastAppliedConfigAnnotation]; ok {
+		var typeMeta metav1.TypeMeta
+
+		if json.Unmarshal([]byte(lastApplied), &typeMeta) == nil && typeMeta.APIVersion == apiVersion {
+			managers = append(managers, lastAppliedManager)
+		}
+	}
+
+	return managers
+}
@@ -0,0 +1,359 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/siderolabs/talos/pkg/machinery/client"
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Identifiers of the built-in checks, to be used with WithSkipChecks and WithOnlyChecks.
+const (
+	CheckControlPlaneHealth      = "control-plane-health"
+	CheckRemovedComponentItems   = "removed-component-items"
+	CheckRemovedAPIResources     = "removed-api"
+	CheckDeprecatedAPIResources  = "deprecated-api"
+	CheckDrainBlockers           = "pdb-drain-blocker"
+	CheckAdmissionWebhooks       = "admission-webhook"
+	CheckKubeProxy               = "kube-proxy"
+	CheckKubeProxyMode           = "kube-proxy-mode"
+	CheckVersionSkew             = "version-skew"
+	CheckRequestedDeprecatedAPIs = "requested-deprecated-api"
+	CheckRollback                = "rollback"
+	CheckInTreeCloudProvider     = "in-tree-cloud-provider"
+	CheckNodeOS                  = "node-os"
+	CheckRemovedVolumePlugins    = "removed-volume-plugin"
+	CheckAppliedAPIVersions      = "applied-api-version"
+	CheckStaticPodDrift          = "static-pod-drift"
+	CheckNodeReadiness           = "node-readiness"
+	CheckNodeCapacity            = "node-capacity"
+	CheckNodeRuntime             = "node-runtime"
+)
+
+// builtinCheck is a built-in check run by Checks.
+type builtinCheck struct {
+	run         func(ctx context.Context, report *ComponentRemovedItemsError) error
+	name        string
+	description string
+	// needsClient is true if the check is skipped without Kubernetes client config
+	needsClient bool
+	// live is true if the check inspects the running cluster, so it is skipped in offline mode
+	live bool
+}
+
+// builtinChecks returns the built-in checks in the order they are run.
+func (checks *Checks) builtinChecks(clientset kubernetes.Interface) []builtinCheck {
+	k8sComponentChecks, supported := checks.upgradeVersionCheck[checks.upgradePath]
+
+	return []builtinCheck{
+		{
+			name:        CheckRollback,
+			description: "checking for Kubernetes rollback issues",
+			run: func(_ context.Context, report *ComponentRemovedItemsError) error {
+				report.PopulateRollback(checks.path)
+
+				return nil
+			},
+		},
+		{
+			name:        CheckControlPlaneHealth,
+			description: "checking control plane health",
+			live:        true,
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				for _, node := range checks.controlPlaneNodes {
+					if err := checks.checkNode(ctx, CheckControlPlaneHealth, node, report, func(ctx context.Context) error {
+						return report.PopulateStaticPodHealth(ctx, checks.state, node)
+					}); err != nil {
+						return err
+					}
+				}
+
+				if clientset == nil {
+					return nil
+				}
+
+				return report.PopulateAPIServerHealth(ctx, clientset)
+			},
+		},
+		{
+			name:        CheckNodeReadiness,
+			description: "checking that the nodes are ready",
+			needsClient: true,
+			live:        true,
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				return report.PopulateNodeReadiness(ctx, clientset)
+			},
+		},
+		{
+			name:        CheckNodeCapacity,
+			description: "checking control plane nodes capacity",
+			live:        true,
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				for _, node := range checks.controlPlaneNodes {
+					if err := checks.checkNode(ctx, CheckNodeCapacity, node, report, func(ctx context.Context) error {
+						return report.PopulateNodeCapacity(ctx, checks.state, node)
+					}); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			},
+		},
+		{
+			name:        CheckStaticPodDrift,
+			description: "checking that control plane static pods are rolled out",
+			needsClient: true,
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				for _, node := range checks.controlPlaneNodes {
+					if err := checks.checkNode(ctx, CheckStaticPodDrift, node, report, func(ctx context.Context) error {
+						return report.PopulateStaticPodDrift(ctx, clientset, checks.state, node)
+					}); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			},
+		},
+		{
+			name:        CheckRemovedComponentItems,
+			description: "checking for removed Kubernetes component flags",
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				if !supported {
+					return nil
+				}
+
+				return checks.populateRemovedComponentItems(ctx, report, k8sComponentChecks)
+			},
+		},
+		{
+			name:        CheckInTreeCloudProvider,
+			description: "checking for in-tree cloud provider usage",
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				for _, node := range append(append([]string(nil), checks.controlPlaneNodes...), checks.workerNodes...) {
+					if err := checks.checkNode(ctx, CheckInTreeCloudProvider, node, report, func(ctx context.Context) error {
+						return checks.populateInTreeCloudProvider(ctx, report, node)
+					}); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			},
+		},
+		{
+			name:        CheckNodeOS,
+			description: "checking node operating system requirements",
+			needsClient: true,
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				for _, node := range append(append([]string(nil), checks.controlPlaneNodes...), checks.workerNodes...) {
+					if err := checks.checkNode(ctx, CheckNodeOS, node, report, func(ctx context.Context) error {
+						return checks.populateNodeOSRequirements(ctx, clientset, report, node)
+					}); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			},
+		},
+		{
+			name:        CheckNodeRuntime,
+			description: "checking node container runtime and Talos versions",
+			needsClient: true,
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				return report.PopulateNodeRuntime(ctx, clientset, checks.path)
+			},
+		},
+		{
+			name:        CheckRemovedAPIResources,
+			description: "checking for removed Kubernetes API resource versions",
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				return report.PopulateRemovedAPIResources(ctx, checks.k8sConfig, k8sComponentChecks.kubeAPIServerChecks.removedAPIResources)
+			},
+		},
+		{
+			name:        CheckAppliedAPIVersions,
+			description: "checking for objects applied using removed Kubernetes API resource versions",
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				return report.PopulateAppliedAPIVersions(ctx, checks.k8sConfig, k8sComponentChecks.kubeAPIServerChecks.removedAPIResources)
+			},
+		},
+		{
+			name:        CheckDeprecatedAPIResources,
+			description: "checking for deprecated Kubernetes API resource versions",
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				return report.PopulateDeprecatedAPIResources(ctx, checks.k8sConfig, checks.path, k8sComponentChecks.kubeAPIServerChecks.deprecatedAPIResources)
+			},
+		},
+		{
+			name:        CheckRemovedVolumePlugins,
+			description: "checking for removed in-tree volume plugins",
+			needsClient: true,
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				return report.PopulateRemovedVolumePlugins(ctx, clientset, checks.path)
+			},
+		},
+		{
+			name:        CheckDrainBlockers,
+			description: "checking for PodDisruptionBudgets blocking node drains",
+			needsClient: true,
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				return report.PopulateDrainBlockers(ctx, clientset)
+			},
+		},
+		{
+			name:        CheckAdmissionWebhooks,
+			description: "checking admission webhooks",
+			needsClient: true,
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				return report.PopulateWebhooks(ctx, clientset)
+			},
+		},
+		{
+			name:        CheckKubeProxy,
+			description: "checking kube-proxy configuration",
+			needsClient: true,
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				return report.PopulateKubeProxy(ctx, clientset,
+					k8sComponentChecks.kubeProxyChecks.removedFlags, k8sComponentChecks.removedFeatureGates)
+			},
+		},
+		{
+			name:        CheckKubeProxyMode,
+			description: "checking kube-proxy proxy mode",
+			needsClient: true,
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				return report.PopulateKubeProxyMode(ctx, clientset, checks.path)
+			},
+		},
+		{
+			name:        CheckVersionSkew,
+			description: "checking Kubernetes version skew policy",
+			needsClient: true,
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				return report.PopulateVersionSkew(ctx, clientset, checks.path)
+			},
+		},
+		{
+			name:        CheckRequestedDeprecatedAPIs,
+			description: "checking for deprecated Kubernetes API versions requested by the clients",
+			needsClient: true,
+			run: func(ctx context.Context, report *ComponentRemovedItemsError) error {
+				return report.PopulateRequestedDeprecatedAPIs(ctx, checks.k8sConfig, checks.path)
+			},
+		},
+	}
+}
+
+func (checks *Checks) populate(ctx context.Context, k8sComponentCheck *ComponentRemovedItemsError) error {
+	var clientset kubernetes.Interface
+
+	if checks.k8sConfig != nil {
+		var err error
+
+		clientset, err = kubernetes.NewForConfig(checks.k8sConfig)
+		if err != nil {
+			return fmt.Errorf("error building kubernetes client: %w", err)
+		}
+	}
+
+	for _, check := range checks.builtinChecks(clientset) {
+		if !checks.options.enabled(check.name) || (check.needsClient && clientset == nil) || (check.live && checks.offline) {
+			continue
+		}
+
+		if err := checks.runCheck(ctx, check.name, check.description, k8sComponentCheck, func(ctx context.Context) error {
+			return check.run(ctx, k8sComponentCheck)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return checks.runCustomChecks(ctx, k8sComponentCheck)
+}
+
+func (checks *Checks) populateRemovedComponentItems(ctx context.Context, k8sComponentCheck *ComponentRemovedItemsError, k8sComponentChecks componentChecks) error {
+	for _, node := range append(append([]string(nil), checks.controlPlaneNodes...), checks.workerNodes...) {
+		if err := checks.checkNode(ctx, CheckRemovedComponentItems, node, k8sComponentCheck, func(ctx context.Context) error {
+			return checks.populateRemovedNodeItems(ctx, k8sComponentCheck, k8sComponentChecks, node)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (checks *Checks) populateRemovedNodeItems(ctx context.Context, k8sComponentCheck *ComponentRemovedItemsError, k8sComponentChecks componentChecks, node string) error {
+	if slices.Contains(checks.controlPlaneNodes, node) {
+		if err := checks.populateRemovedStaticPodItems(ctx, k8sComponentCheck, k8sComponentChecks, node); err != nil {
+			return err
+		}
+	}
+
+	kubeletSpec, err := safe.StateGet[*k8s.KubeletSpec](client.WithNode(ctx, node), checks.state, k8s.NewKubeletSpec(k8s.NamespaceName, k8s.KubeletID).Metadata())
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	k8sComponentCheck.PopulateRemovedCLIFlags(node, k8s.KubeletID, kubeletSpec.TypedSpec().Args, k8sComponentChecks.kubeletChecks.removedFlags)
+	k8sComponentCheck.PopulateRemovedFeatureGates(node, k8s.KubeletID, kubeletSpec.TypedSpec().Args, k8sComponentChecks.removedFeatureGates)
+	k8sComponentCheck.PopulateRemovedKubeletConfigFields(node, kubeletSpec.TypedSpec().Config, k8sComponentChecks.kubeletChecks.removedConfigFields)
+	k8sComponentCheck.PopulateRemovedKubeletConfigFeatureGates(node, kubeletSpec.TypedSpec().Config, k8sComponentChecks.removedFeatureGates)
+	k8sComponentCheck.PopulateLockedFeatureGates(node, k8s.KubeletID, kubeletSpec.TypedSpec().Args, k8sComponentChecks.lockedFeatureGates)
+	k8sComponentCheck.PopulateLockedKubeletConfigFeatureGates(node, kubeletSpec.TypedSpec().Config, k8sComponentChecks.lockedFeatureGates)
+	k8sComponentCheck.PopulateKubeletCredentialProviders(node, kubeletSpec.TypedSpec().Args, kubeletSpec.TypedSpec().CredentialProviderConfig,
+		k8sComponentChecks.kubeletChecks.removedCredentialProviderAPIVersions)
+
+	return nil
+}
+
+func (checks *Checks) populateRemovedStaticPodItems(ctx context.Context, k8sComponentCheck *ComponentRemovedItemsError, k8sComponentChecks componentChecks, node string) error {
+	for _, id := range []string{k8s.APIServerID, k8s.ControllerManagerID, k8s.SchedulerID} {
+		staticPod, err := safe.StateGet[*k8s.StaticPod](client.WithNode(ctx, node), checks.state, k8s.NewStaticPod(k8s.NamespaceName, id).Metadata())
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				continue
+			}
+
+			return err
+		}
+
+		pod, err := staticPodTypedResourceToK8sPodSpec(staticPod)
+		if err != nil {
+			return err
+		}
+
+		switch id {
+		case k8s.APIServerID:
+			k8sComponentCheck.PopulateRemovedAdmissionPlugins(node, id, pod.Spec.Containers[0].Command, k8sComponentChecks.kubeAPIServerChecks.removedAdmissionPlugins)
+			k8sComponentCheck.PopulateChangedAdmissionPlugins(node, id, pod.Spec.Containers[0].Command, k8sComponentChecks.kubeAPIServerChecks.changedAdmissionPlugins)
+			k8sComponentCheck.PopulateEncryptionConfig(node, pod.Spec.Containers[0].Command, checks.encryptionConfigs[node], checks.path)
+			k8sComponentCheck.PopulateRemovedCLIFlags(node, id, pod.Spec.Containers[0].Command, k8sComponentChecks.kubeAPIServerChecks.componentCheck.removedFlags)
+		case k8s.ControllerManagerID:
+			k8sComponentCheck.PopulateRemovedCLIFlags(node, id, pod.Spec.Containers[0].Command, k8sComponentChecks.kubeControllerManagerChecks.removedFlags)
+		case k8s.SchedulerID:
+			k8sComponentCheck.PopulateRemovedCLIFlags(node, id, pod.Spec.Containers[0].Command, k8sComponentChecks.kubeSchedulerChecks.removedFlags)
+		}
+
+		k8sComponentCheck.PopulateRemovedFeatureGates(node, id, pod.Spec.Containers[0].Command, k8sComponentChecks.removedFeatureGates)
+		k8sComponentCheck.PopulateLockedFeatureGates(node, id, pod.Spec.Containers[0].Command, k8sComponentChecks.lockedFeatureGates)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/siderolabs/gen/xslices"
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+)
+
+// CheckData describes what changes in the upgrade path, as checked by Checks.
+//
+// CheckData can be marshaled to JSON or YAML.
+type CheckData struct {
+	// Path is the upgrade path, e.g. 1.30->1.31.
+	Path string `json:"path" yaml:"path"`
+	// Supported is true if the upgrade path is supported.
+	Supported bool `json:"supported" yaml:"supported"`
+	// RemovedFeatureGates are common to kube-apiserver, kube-controller-manager, kube-scheduler, kubelet and kube-proxy.
+	RemovedFeatureGates []string `json:"removedFeatureGates,omitempty" yaml:"removedFeatureGates,omitempty"`
+	// LockedFeatureGates are the feature gates locked to the value in the upgrade version, setting them to the other value fails.
+	LockedFeatureGates map[string]bool `json:"lockedFeatureGates,omitempty" yaml:"lockedFeatureGates,omitempty"`
+	// RemovedFlags are the removed flags for each component.
+	RemovedFlags map[string][]string `json:"removedFlags,omitempty" yaml:"removedFlags,omitempty"`
+	// RemovedAdmissionPlugins are the removed kube-apiserver admission plugins.
+	RemovedAdmissionPlugins []string `json:"removedAdmissionPlugins,omitempty" yaml:"removedAdmissionPlugins,omitempty"`
+	// ChangedAdmissionPlugins are kube-apiserver admission plugins whose default behavior changes, with the description of the change.
+	ChangedAdmissionPlugins map[string]string `json:"changedAdmissionPlugins,omitempty" yaml:"changedAdmissionPlugins,omitempty"`
+	// RemovedAPIResources are the removed API resource versions (e.g. podsecuritypolicies.v1beta1.policy).
+	RemovedAPIResources []string `json:"removedAPIResources,omitempty" yaml:"removedAPIResources,omitempty"`
+	// DeprecatedAPIResources are the API resource versions deprecated in the upgrade version and removed later.
+	DeprecatedAPIResources []string `json:"deprecatedAPIResources,omitempty" yaml:"deprecatedAPIResources,omitempty"`
+	// RemovedKubeletConfigFields are the removed KubeletConfiguration fields (dot-separated paths).
+	RemovedKubeletConfigFields []string `json:"removedKubeletConfigFields,omitempty" yaml:"removedKubeletConfigFields,omitempty"`
+	// RemovedCredentialProviderAPIVersions are the removed kubelet credential provider API versions.
+	RemovedCredentialProviderAPIVersions []string `json:"removedCredentialProviderAPIVersions,omitempty" yaml:"removedCredentialProviderAPIVersions,omitempty"`
+}
+
+// ChecksForPath returns the data of the checks which apply to the upgrade path, without running the checks.
+func ChecksForPath(path *Path) CheckData {
+	data := CheckData{
+		Path:      path.String(),
+		Supported: path.IsSupported(),
+	}
+
+	checks, ok := upgradeVersionChecks[path.String()]
+	if !ok {
+		return data
+	}
+
+	data.RemovedFeatureGates = slices.Clone(checks.removedFeatureGates)
+	data.LockedFeatureGates = maps.Clone(checks.lockedFeatureGates)
+	data.RemovedAdmissionPlugins = slices.Clone(checks.kubeAPIServerChecks.removedAdmissionPlugins)
+	data.RemovedAPIResources = slices.Clone(checks.kubeAPIServerChecks.removedAPIResources)
+	data.DeprecatedAPIResources = slices.Clone(checks.kubeAPIServerChecks.deprecatedAPIResources)
+	data.RemovedKubeletConfigFields = slices.Clone(checks.kubeletChecks.removedConfigFields)
+	data.RemovedCredentialProviderAPIVersions = slices.Clone(checks.kubeletChecks.removedCredentialProviderAPIVersions)
+
+	data.ChangedAdmissionPlugins = xslices.ToMap(checks.kubeAPIServerChecks.changedAdmissionPlugins, func(c admissionPluginChange) (string, string) {
+		return c.name, c.change
+	})
+
+	for component, flags := range map[string][]string{
+		k8s.APIServerID:         checks.kubeAPIServerChecks.removedFlags,
+		k8s.ControllerManagerID: checks.kubeControllerManagerChecks.removedFlags,
+		k8s.SchedulerID:         checks.kubeSchedulerChecks.removedFlags,
+		k8s.KubeletID:           checks.kubeletChecks.removedFlags,
+		kubeProxyID:             checks.kubeProxyChecks.removedFlags,
+	} {
+		if len(flags) == 0 {
+			continue
+		}
+
+		if data.RemovedFlags == nil {
+			data.RemovedFlags = map[string][]string{}
+		}
+
+		data.RemovedFlags[component] = slices.Clone(flags)
+	}
+
+	return data
+}
+
+// BuiltinChecks returns the identifiers of the built-in checks in the order they are run.
+func BuiltinChecks() []string {
+	return xslices.Map((&Checks{}).builtinChecks(nil), func(check builtinCheck) string { return check.name })
+}
@@ -0,0 +1,107 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/upgrade"
+)
+
+func TestChecksForPath(t *testing.T) {
+	path, err := upgrade.NewPath("1.24.3", "1.25.0")
+	require.NoError(t, err)
+
+	data := upgrade.ChecksForPath(path)
+
+	assert.Equal(t, "1.24->1.25", data.Path)
+	assert.True(t, data.Supported)
+	assert.Contains(t, data.RemovedAPIResources, "podsecuritypolicies.v1beta1.policy")
+	assert.Contains(t, data.RemovedAdmissionPlugins, "PodSecurityPolicy")
+	assert.Contains(t, data.RemovedFeatureGates, "CSIVolumeFSGroupPolicy")
+	assert.Contains(t, data.RemovedFlags["kube-apiserver"], "service-account-api-audiences")
+
+	path, err = upgrade.NewPath("1.29.3", "1.30.0")
+	require.NoError(t, err)
+
+	data = upgrade.ChecksForPath(path)
+
+	assert.Equal(t, map[string]string{
+		"ValidatingAdmissionPolicy": "is enabled by default, existing ValidatingAdmissionPolicyBindings are enforced",
+	}, data.ChangedAdmissionPlugins)
+
+	path, err = upgrade.NewPath("1.32.2", "1.33.0")
+	require.NoError(t, err)
+
+	data = upgrade.ChecksForPath(path)
+
+	assert.Contains(t, data.RemovedFeatureGates, "CPUManager")
+	assert.Equal(t, []string{"cloud-provider", "cloud-config"}, data.RemovedFlags["kube-apiserver"])
+
+	path, err = upgrade.NewPath("1.33.1", "1.34.0")
+	require.NoError(t, err)
+
+	data = upgrade.ChecksForPath(path)
+
+	assert.Contains(t, data.RemovedFeatureGates, "PodDisruptionConditions")
+	assert.NotContains(t, data.RemovedFeatureGates, "CPUManager")
+	assert.Contains(t, data.RemovedAPIResources, "resourceclaims.v1alpha3.resource.k8s.io")
+	assert.Equal(t, []string{"cloud-config", "register-schedulable"}, data.RemovedFlags["kubelet"])
+
+	path, err = upgrade.NewPath("1.34.0", "1.35.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, upgrade.CheckData{Path: "1.34->1.35"}, upgrade.ChecksForPath(path))
+}
+
+// TestUpstreamFeatureGates fails when the generated feature gates data is missing from the checks data.
+func TestUpstreamFeatureGates(t *testing.T) {
+	path, err := upgrade.NewPath("1.24.3", "1.25.0")
+	require.NoError(t, err)
+
+	data := upgrade.ChecksForPath(path)
+
+	// not listed in the curated data, only in featuregates_generated.go
+	assert.Contains(t, data.RemovedFeatureGates, "WindowsEndpointSliceProxying")
+	assert.True(t, data.LockedFeatureGates["EphemeralContainers"])
+
+	path, err = upgrade.NewPath("1.32.0", "1.33.0")
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, upgrade.ChecksForPath(path).LockedFeatureGates)
+}
+
+// TestChecksDataCoverage fails when the supported versions are extended without the checks data for the new upgrade path.
+//
+// Run `go generate` and review the Kubernetes changelog to add the data.
+func TestChecksDataCoverage(t *testing.T) {
+	// the checks data is maintained starting with 1.24->1.25
+	const firstPath = "1.24"
+
+	versions := upgrade.SupportedVersions()
+	start := slices.Index(versions, firstPath)
+	require.NotEqual(t, -1, start)
+
+	for i := start + 1; i < len(versions); i++ {
+		path, err := upgrade.NewPath(versions[i-1]+".0", versions[i]+".0")
+		require.NoError(t, err)
+
+		data := upgrade.ChecksForPath(path)
+
+		assert.NotEqual(t, upgrade.CheckData{Path: data.Path, Supported: true}, data, "no checks data for %s", data.Path)
+	}
+}
+
+func TestBuiltinChecks(t *testing.T) {
+	checks := upgrade.BuiltinChecks()
+
+	assert.Contains(t, checks, upgrade.CheckRemovedComponentItems)
+	assert.Contains(t, checks, upgrade.CheckRemovedAPIResources)
+	assert.Equal(t, upgrade.CheckRollback, checks[0])
+}
@@ -8,24 +8,61 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"slices"
 	"strings"
 	"text/tabwriter"
+	"time"
 
-	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/blang/semver/v4"
 	"github.com/cosi-project/runtime/pkg/state"
 	"github.com/siderolabs/gen/xslices"
-	"github.com/siderolabs/talos/pkg/machinery/client"
 	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
 	v1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/dynamic"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/rest"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
 )
 
+// ChecksOptions configures Checks.
+type ChecksOptions struct {
+	// Skip lists the identifiers of the checks to skip.
+	Skip []string
+	// Only lists the identifiers of the checks to run, all other checks are skipped if set.
+	Only []string
+	// ProgressCh receives the progress of the checks.
+	ProgressCh chan<- CheckEvent
+	// CheckTimeout is the timeout of each check.
+	CheckTimeout time.Duration
+	// NodeTimeout is the timeout of checking each node.
+	NodeTimeout time.Duration
+}
+
+// ChecksOption modifies ChecksOptions.
+type ChecksOption func(*ChecksOptions)
+
+// WithSkipChecks skips the checks with the given identifiers (built-in Check* constants or custom check names).
+func WithSkipChecks(ids ...string) ChecksOption {
+	return func(o *ChecksOptions) {
+		o.Skip = append(o.Skip, ids...)
+	}
+}
+
+// WithOnlyChecks runs only the checks with the given identifiers (built-in Check* constants or custom check names).
+func WithOnlyChecks(ids ...string) ChecksOption {
+	return func(o *ChecksOptions) {
+		o.Only = append(o.Only, ids...)
+	}
+}
+
+func (o ChecksOptions) enabled(id string) bool {
+	if slices.Contains(o.Skip, id) {
+		return false
+	}
+
+	return len(o.Only) == 0 || slices.Contains(o.Only, id)
+}
+
 // Checks is a set of checks to run before upgrading k8s components.
 type Checks struct { //nolint:govet
 	state             state.State
@@ -34,28 +71,45 @@ type Checks struct { //nolint:govet
 	workerNodes       []string
 	log               func(string, ...any)
 
+	path                *Path
 	upgradePath         string
 	upgradeVersionCheck map[string]componentChecks
+	customChecks        []customCheck
+	options             ChecksOptions
+	// encryptionConfigs are the kube-apiserver encryption configurations by node (offline mode)
+	encryptionConfigs map[string]map[string]any
+	// offline is true if the checks run against the component configuration without a live cluster
+	offline bool
 }
 
 // ComponentRemovedItemsError is an error type for removed items.
 type ComponentRemovedItemsError struct { //nolint:govet,recvcheck
-	AdmissionFlags []ComponentItem
-	CLIFlags       []ComponentItem
-	FeatureGates   []ComponentItem
-	APIResources   map[string]int
+	AdmissionFlags []ComponentItem `json:"admissionFlags,omitempty" yaml:"admissionFlags,omitempty"`
+	CLIFlags       []ComponentItem `json:"cliFlags,omitempty" yaml:"cliFlags,omitempty"`
+	FeatureGates   []ComponentItem `json:"featureGates,omitempty" yaml:"featureGates,omitempty"`
+	ConfigFields   []ComponentItem `json:"configFields,omitempty" yaml:"configFields,omitempty"`
+	APIResources   map[string]int  `json:"apiResources,omitempty" yaml:"apiResources,omitempty"`
+	// APIResourceSamples are the names of some of the objects of the removed API resources.
+	APIResourceSamples map[string][]string `json:"apiResourceSamples,omitempty" yaml:"apiResourceSamples,omitempty"`
+	// Findings are reported by the checks other than removed items, Checks.Run only returns the blocking findings.
+	Findings []Finding `json:"findings,omitempty" yaml:"findings,omitempty"`
 }
 
 // ComponentItem represents a component item.
 type ComponentItem struct {
-	Node      string
-	Component string
-	Value     string
+	Node      string `json:"node,omitempty" yaml:"node,omitempty"`
+	Component string `json:"component" yaml:"component"`
+	Value     string `json:"value" yaml:"value"`
+	// FlagValue is the full value of the list flag the item is set in (--feature-gates, --enable-admission-plugins).
+	FlagValue string `json:"flagValue,omitempty" yaml:"flagValue,omitempty"`
 }
 
 type componentChecks struct {
 	// feature gates are common to kube-apiserver, kube-controller-manager and kube-scheduler
 	removedFeatureGates []string
+	// lockedFeatureGates are the feature gates locked to the value (usually on GA) in the upgrade version,
+	// components fail to start if the gate is set to the other value
+	lockedFeatureGates map[string]bool
 	// checks specific to kube-apiserver
 	kubeAPIServerChecks apiServerCheck
 	// checks specific to kube-controller-manager
@@ -63,14 +117,35 @@ type componentChecks struct {
 	// checks specific to kube-scheduler
 	kubeSchedulerChecks componentCheck
 	// checks specific to kubelet
-	kubeletChecks componentCheck
+	kubeletChecks kubeletCheck
+	// checks specific to kube-proxy
+	kubeProxyChecks componentCheck
 }
 
 type apiServerCheck struct {
 	// removedAPIResources represent the Kuberenetes API resources that are removed in the upgrade version
 	removedAPIResources []string
+	// deprecatedAPIResources represent the Kubernetes API resources that are deprecated in the upgrade version (removed later)
+	deprecatedAPIResources []string
 	// removedAdmissionPlugins represent the Kuberenetes Admission Plugins that are removed in the upgrade version
 	removedAdmissionPlugins []string
+	// changedAdmissionPlugins represent the Kubernetes Admission Plugins whose default behavior changes in the upgrade version
+	changedAdmissionPlugins []admissionPluginChange
+	componentCheck
+}
+
+type admissionPluginChange struct {
+	// name is the name of the admission plugin
+	name string
+	// change describes the new default behavior
+	change string
+}
+
+type kubeletCheck struct {
+	// removedConfigFields represent the KubeletConfiguration fields (dot-separated paths) that are removed in the upgrade version
+	removedConfigFields []string
+	// removedCredentialProviderAPIVersions represent the kubelet credential provider API versions that are removed in the upgrade version
+	removedCredentialProviderAPIVersions []string
 	componentCheck
 }
 
@@ -79,248 +154,360 @@ type componentCheck struct {
 	removedFlags []string
 }
 
-// NewChecks initializes and returns Checks.
-func NewChecks(path *Path, state state.State, k8sConfig *rest.Config, controlPlaneNodes, workerNodes []string, logFunc func(string, ...any)) (*Checks, error) {
-	return &Checks{
-		state:             state,
-		k8sConfig:         k8sConfig,
-		log:               logFunc,
-		upgradePath:       path.String(),
-		controlPlaneNodes: controlPlaneNodes,
-		workerNodes:       workerNodes,
-		// https://kubernetes.io/docs/reference/using-api/deprecation-guide/
-		upgradeVersionCheck: map[string]componentChecks{
-			"1.24->1.25": {
-				kubeAPIServerChecks: apiServerCheck{
-					removedAPIResources: []string{
-						"podsecuritypolicies.v1beta1.policy",
-					},
-					componentCheck: componentCheck{
-						removedFlags: []string{
-							"service-account-api-audiences",
-						},
-					},
-					removedAdmissionPlugins: []string{
-						"PodSecurityPolicy",
-					},
-				},
-				kubeControllerManagerChecks: componentCheck{
-					removedFlags: []string{
-						"deleting-pods-qps",
-						"deleting-pods-burst",
-						"register-retry-count",
-					},
-				},
-				// https://kubernetes.io/docs/reference/command-line-tools-reference/feature-gates-removed/
-				removedFeatureGates: []string{
-					"CSIVolumeFSGroupPolicy",
-					"ConfigurableFSGroupPolicy",
-					"PodDisruptionBudget",
-					"SelectorIndex",
+// upgradeVersionChecks are the checks data for each upgrade path.
+//
+// The feature gates scraped from the Kubernetes repository are merged into the curated data,
+// bump the -to version below along with MaxSupportedVersion and run `go generate` to update them
+// when a new Kubernetes minor version is released.
+// The removed command line flags come from the compatibility package.
+//
+// https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+//
+//go:generate go run ../internal/gendata -format upgrade -to 1.34 -out featuregates_generated.go
+var upgradeVersionChecks = withRemovedFlags(withUpstreamFeatureGates(map[string]componentChecks{
+	// https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG/CHANGELOG-1.23.md
+	"1.22->1.23": {
+		kubeAPIServerChecks: apiServerCheck{
+			changedAdmissionPlugins: []admissionPluginChange{
+				{
+					name:   "PodSecurity",
+					change: "is enabled by default, pod-security.kubernetes.io labels on namespaces are enforced",
 				},
 			},
-			"1.25->1.26": {
-				kubeAPIServerChecks: apiServerCheck{
-					componentCheck: componentCheck{
-						removedFlags: []string{
-							"master-service-namespace",
-						},
-					},
-				},
-				removedFeatureGates: []string{
-					"DynamicKubeletConfig",
-				},
+		},
+	},
+	"1.24->1.25": {
+		kubeAPIServerChecks: apiServerCheck{
+			removedAPIResources: []string{
+				"podsecuritypolicies.v1beta1.policy",
 			},
-			// https://kubernetes.io/blog/2023/03/17/upcoming-changes-in-kubernetes-v1-27/
-			"1.26->1.27": {
-				kubeControllerManagerChecks: componentCheck{
-					removedFlags: []string{
-						"enable-taint-manager",
-						"pod-eviction-timeout",
-					},
-				},
-				kubeletChecks: componentCheck{
-					removedFlags: []string{
-						"container-runtime",
-						"master-service-namespace",
-					},
-				},
-				removedFeatureGates: []string{
-					"ExpandCSIVolumes",
-					"ExpandInUsePersistentVolumes",
-					"ExpandPersistentVolumes",
-					"ControllerManagerLeaderMigration",
-					"CSIMigration",
-					"CSIInlineVolume",
-					"EphemeralContainers",
-					"LocalStorageCapacityIsolation",
-					"NetworkPolicyEndPort",
-					"StatefulSetMinReadySeconds",
-					"IdentifyPodOS",
-					"DaemonSetUpdateSurge",
-				},
+			removedAdmissionPlugins: []string{
+				"PodSecurityPolicy",
 			},
-			// https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG/CHANGELOG-1.28.md
-			"1.27->1.28": {
-				removedFeatureGates: []string{
-					"AdvancedAuditing",
-					"DelegateFSGroupToCSIDriver",
-					"DevicePlugins",
-					"DisableAcceleratorUsageMetrics",
-					"EndpointSliceTerminatingCondition",
-					"CSIStorageCapacity",
-					"CSIMigrationGCE",
-					"KubeletCredentialProviders",
-					"MixedProtocolLBService",
-					"ServiceInternalTrafficPolicy",
-					"ServiceIPStaticSubrange",
-					"WindowsHostProcessContainers",
-				},
+		},
+		// https://kubernetes.io/docs/reference/command-line-tools-reference/feature-gates-removed/
+		removedFeatureGates: []string{
+			"CSIVolumeFSGroupPolicy",
+			"ConfigurableFSGroupPolicy",
+			"PodDisruptionBudget",
+			"SelectorIndex",
+		},
+	},
+	"1.25->1.26": {
+		removedFeatureGates: []string{
+			"DynamicKubeletConfig",
+		},
+		kubeletChecks: kubeletCheck{
+			// https://github.com/kubernetes/enhancements/tree/master/keps/sig-node/2133-kubelet-credential-providers
+			removedCredentialProviderAPIVersions: []string{
+				"kubelet.config.k8s.io/v1alpha1",
+				"credentialprovider.kubelet.k8s.io/v1alpha1",
 			},
-			// https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG/CHANGELOG-1.29.md
-			"1.28->1.29": {
-				kubeAPIServerChecks: apiServerCheck{
-					removedAPIResources: []string{
-						"clustercidrs.v1alpha1.networking.k8s.io", // https://github.com/kubernetes/kubernetes/pull/121229
-					},
-				},
+		},
+	},
+	// https://kubernetes.io/blog/2023/03/17/upcoming-changes-in-kubernetes-v1-27/
+	"1.26->1.27": {
+		lockedFeatureGates: map[string]bool{
+			"CronJobTimeZone":           true,
+			"DownwardAPIHugePages":      true,
+			"ServerSideFieldValidation": true,
+		},
+		removedFeatureGates: []string{
+			"ExpandCSIVolumes",
+			"ExpandInUsePersistentVolumes",
+			"ExpandPersistentVolumes",
+			"ControllerManagerLeaderMigration",
+			"CSIMigration",
+			"CSIInlineVolume",
+			"EphemeralContainers",
+			"LocalStorageCapacityIsolation",
+			"NetworkPolicyEndPort",
+			"StatefulSetMinReadySeconds",
+			"IdentifyPodOS",
+			"DaemonSetUpdateSurge",
+		},
+	},
+	// https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG/CHANGELOG-1.28.md
+	"1.27->1.28": {
+		lockedFeatureGates: map[string]bool{
+			"NodeOutOfServiceVolumeDetach": true,
+		},
+		removedFeatureGates: []string{
+			"AdvancedAuditing",
+			"DelegateFSGroupToCSIDriver",
+			"DevicePlugins",
+			"DisableAcceleratorUsageMetrics",
+			"EndpointSliceTerminatingCondition",
+			"CSIStorageCapacity",
+			"CSIMigrationGCE",
+			"KubeletCredentialProviders",
+			"MixedProtocolLBService",
+			"ServiceInternalTrafficPolicy",
+			"ServiceIPStaticSubrange",
+			"WindowsHostProcessContainers",
+		},
+	},
+	// https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG/CHANGELOG-1.29.md
+	"1.28->1.29": {
+		lockedFeatureGates: map[string]bool{
+			"APIListChunking":        true,
+			"APIPriorityAndFairness": true,
+			"JobReadyPods":           true,
+			"KMSv2":                  true,
+			"KMSv2KDF":               true,
+			"ReadWriteOncePod":       true,
+		},
+		kubeAPIServerChecks: apiServerCheck{
+			removedAPIResources: []string{
+				"clustercidrs.v1alpha1.networking.k8s.io", // https://github.com/kubernetes/kubernetes/pull/121229
 			},
-			// https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG/CHANGELOG-1.30.md
-			"1.29->1.30": {
-				removedFeatureGates: []string{
-					"ExpandedDNSConfig",
-					"ExperimentalHostUserNamespaceDefaultingGate",
-					"IPTablesOwnershipCleanup",
-					"KubeletPodResources",
-					"KubeletPodResourcesGetAllocatable",
-					"MinimizeIPTablesRestore",
-					"ProxyTerminatingEndpoints",
-					"RemoveSelfLink",
-				},
-				kubeAPIServerChecks: apiServerCheck{
-					removedAdmissionPlugins: []string{
-						"SecurityContextDeny", // https://github.com/kubernetes/kubernetes/pull/122612
-					},
-				},
+			// flowcontrol.apiserver.k8s.io/v1beta3 is deprecated in favor of v1, and removed in 1.32
+			deprecatedAPIResources: []string{
+				"flowschemas.v1beta3.flowcontrol.apiserver.k8s.io",
+				"prioritylevelconfigurations.v1beta3.flowcontrol.apiserver.k8s.io",
 			},
-			// https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG/CHANGELOG-1.31.md
-			"1.30->1.31": {
-				removedFeatureGates: []string{
-					"APIPriorityAndFairness", // https://github.com/kubernetes/kubernetes/pull/125846
-					"CSINodeExpandSecret",
-					"ConsistentHTTPGetHandlers",
-					"DefaultHostNetworkHostPortsInPodTemplates",
-					"ServiceNodePortStaticSubrange",
-					"SkipReadOnlyValidationGCE",
-				},
-				kubeletChecks: componentCheck{
-					removedFlags: []string{
-						"keep-terminated-pod-volumes", // https://github.com/kubernetes/kubernetes/pull/122082
-						"iptables-masquerade-bit",
-						"iptables-drop-bit", // https://github.com/kubernetes/kubernetes/pull/122363
-					},
-				},
-				kubeControllerManagerChecks: componentCheck{
-					removedFlags: []string{
-						"volume-host-cidr-denylist",
-						"volume-host-allow-local-loopback", // https://github.com/kubernetes/kubernetes/pull/124017
-						"horizontal-pod-autoscaler-upscale-delay",
-						"horizontal-pod-autoscaler-downscale-delay", // https://github.com/kubernetes/kubernetes/pull/124948
-					},
-				},
+		},
+	},
+	// https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG/CHANGELOG-1.30.md
+	"1.29->1.30": {
+		lockedFeatureGates: map[string]bool{
+			"AdmissionWebhookMatchConditions":     true,
+			"AggregatedDiscoveryEndpoint":         true,
+			"CloudDualStackNodeIPs":               true,
+			"HPAContainerMetrics":                 true,
+			"LegacyServiceAccountTokenCleanUp":    true,
+			"MinDomainsInPodTopologySpread":       true,
+			"NewVolumeManagerReconstruction":      true,
+			"PodHostIPs":                          true,
+			"PodSchedulingReadiness":              true,
+			"StableLoadBalancerNodeSet":           true,
+			"ValidatingAdmissionPolicy":           true,
+			"ZeroLimitedNominalConcurrencyShares": true,
+		},
+		removedFeatureGates: []string{
+			"ExpandedDNSConfig",
+			"ExperimentalHostUserNamespaceDefaultingGate",
+			"IPTablesOwnershipCleanup",
+			"KubeletPodResources",
+			"KubeletPodResourcesGetAllocatable",
+			"MinimizeIPTablesRestore",
+			"ProxyTerminatingEndpoints",
+			"RemoveSelfLink",
+		},
+		kubeAPIServerChecks: apiServerCheck{
+			removedAdmissionPlugins: []string{
+				"SecurityContextDeny", // https://github.com/kubernetes/kubernetes/pull/122612
 			},
-			// https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG/CHANGELOG-1.32.md
-			"1.31->1.32": {
-				removedFeatureGates: []string{
-					"AllowServiceLBStatusOnNonLB",         // https://github.com/kubernetes/kubernetes/pull/126786
-					"CloudDualStackNodeIPs",               // https://github.com/kubernetes/kubernetes/pull/126840
-					"DRAControlPlaneController",           // https://github.com/kubernetes/kubernetes/pull/128003
-					"HPAContainerMetrics",                 // https://github.com/kubernetes/kubernetes/pull/126862
-					"KMSv2",                               // https://github.com/kubernetes/kubernetes/pull/126698
-					"KMSv2KDF",                            // https://github.com/kubernetes/kubernetes/pull/126698
-					"LegacyServiceAccountTokenCleanUp",    // https://github.com/kubernetes/kubernetes/pull/126839
-					"MinDomainsInPodTopologySpread",       // https://github.com/kubernetes/kubernetes/pull/126863
-					"NewVolumeManagerReconstruction",      // https://github.com/kubernetes/kubernetes/pull/126775
-					"NodeOutOfServiceVolumeDetach",        // https://github.com/kubernetes/kubernetes/pull/127019
-					"ServerSideApply",                     // https://github.com/kubernetes/kubernetes/pull/127058
-					"ServerSideFieldValidation",           // https://github.com/kubernetes/kubernetes/pull/127058
-					"StableLoadBalancerNodeSet",           // https://github.com/kubernetes/kubernetes/pull/126841
-					"ValidatingAdmissionPolicy",           // https://github.com/kubernetes/kubernetes/pull/126645
-					"ZeroLimitedNominalConcurrencyShares", // https://github.com/kubernetes/kubernetes/pull/126894
-				},
-				kubeAPIServerChecks: apiServerCheck{
-					removedAPIResources: []string{
-						"podschedulingcontexts.v1alpha3.resource.k8s.io", // https://github.com/kubernetes/kubernetes/pull/128003
-					},
+			changedAdmissionPlugins: []admissionPluginChange{
+				{
+					name:   "ValidatingAdmissionPolicy",
+					change: "is enabled by default, existing ValidatingAdmissionPolicyBindings are enforced",
 				},
 			},
 		},
+	},
+	// https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG/CHANGELOG-1.31.md
+	"1.30->1.31": {
+		lockedFeatureGates: map[string]bool{
+			"AppArmor":                true,
+			"JobPodFailurePolicy":     true,
+			"PodDisruptionConditions": true,
+		},
+		removedFeatureGates: []string{
+			"APIPriorityAndFairness", // https://github.com/kubernetes/kubernetes/pull/125846
+			"CSINodeExpandSecret",
+			"ConsistentHTTPGetHandlers",
+			"DefaultHostNetworkHostPortsInPodTemplates",
+			"ServiceNodePortStaticSubrange",
+			"SkipReadOnlyValidationGCE",
+		},
+	},
+	// https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG/CHANGELOG-1.32.md
+	"1.31->1.32": {
+		removedFeatureGates: []string{
+			"AllowServiceLBStatusOnNonLB",         // https://github.com/kubernetes/kubernetes/pull/126786
+			"CloudDualStackNodeIPs",               // https://github.com/kubernetes/kubernetes/pull/126840
+			"DRAControlPlaneController",           // https://github.com/kubernetes/kubernetes/pull/128003
+			"HPAContainerMetrics",                 // https://github.com/kubernetes/kubernetes/pull/126862
+			"KMSv2",                               // https://github.com/kubernetes/kubernetes/pull/126698
+			"KMSv2KDF",                            // https://github.com/kubernetes/kubernetes/pull/126698
+			"LegacyServiceAccountTokenCleanUp",    // https://github.com/kubernetes/kubernetes/pull/126839
+			"MinDomainsInPodTopologySpread",       // https://github.com/kubernetes/kubernetes/pull/126863
+			"NewVolumeManagerReconstruction",      // https://github.com/kubernetes/kubernetes/pull/126775
+			"NodeOutOfServiceVolumeDetach",        // https://github.com/kubernetes/kubernetes/pull/127019
+			"ServerSideApply",                     // https://github.com/kubernetes/kubernetes/pull/127058
+			"ServerSideFieldValidation",           // https://github.com/kubernetes/kubernetes/pull/127058
+			"StableLoadBalancerNodeSet",           // https://github.com/kubernetes/kubernetes/pull/126841
+			"ValidatingAdmissionPolicy",           // https://github.com/kubernetes/kubernetes/pull/126645
+			"ZeroLimitedNominalConcurrencyShares", // https://github.com/kubernetes/kubernetes/pull/126894
+		},
+		kubeAPIServerChecks: apiServerCheck{
+			removedAPIResources: []string{
+				"podschedulingcontexts.v1alpha3.resource.k8s.io", // https://github.com/kubernetes/kubernetes/pull/128003
+			},
+		},
+	},
+	// https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG/CHANGELOG-1.33.md
+	"1.32->1.33": {
+		lockedFeatureGates: map[string]bool{
+			"SidecarContainers": true, // https://github.com/kubernetes/kubernetes/pull/129731
+		},
+		kubeAPIServerChecks: apiServerCheck{
+			removedAPIResources: []string{
+				"validatingadmissionpolicies.v1alpha1.admissionregistration.k8s.io",       // https://github.com/kubernetes/kubernetes/pull/129207
+				"validatingadmissionpolicybindings.v1alpha1.admissionregistration.k8s.io", // https://github.com/kubernetes/kubernetes/pull/129207
+			},
+			// resource.k8s.io/v1beta1 is deprecated in favor of v1beta2, and removed in 1.36
+			// https://github.com/kubernetes/kubernetes/pull/129970
+			deprecatedAPIResources: []string{
+				"deviceclasses.v1beta1.resource.k8s.io",
+				"resourceclaims.v1beta1.resource.k8s.io",
+				"resourceclaimtemplates.v1beta1.resource.k8s.io",
+				"resourceslices.v1beta1.resource.k8s.io",
+			},
+		},
+	},
+	// https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG/CHANGELOG-1.34.md
+	"1.33->1.34": {
+		kubeAPIServerChecks: apiServerCheck{
+			// resource.k8s.io/v1alpha3 only contains DeviceTaintRule
+			// https://github.com/kubernetes/kubernetes/pull/132000
+			removedAPIResources: []string{
+				"deviceclasses.v1alpha3.resource.k8s.io",
+				"resourceclaims.v1alpha3.resource.k8s.io",
+				"resourceclaimtemplates.v1alpha3.resource.k8s.io",
+				"resourceslices.v1alpha3.resource.k8s.io",
+			},
+		},
+	},
+}))
+
+// withRemovedFlags fills in the removed command line flags of the components from the compatibility data.
+func withRemovedFlags(checks map[string]componentChecks) map[string]componentChecks {
+	for path, pathChecks := range checks {
+		_, to, _ := strings.Cut(path, "->")
+
+		version, err := semver.ParseTolerant(to)
+		if err != nil {
+			continue
+		}
+
+		target := compatibility.Version(version)
+
+		pathChecks.kubeAPIServerChecks.removedFlags = target.FlagsRemovedIn(compatibility.ComponentKubeAPIServer)
+		pathChecks.kubeControllerManagerChecks.removedFlags = target.FlagsRemovedIn(compatibility.ComponentKubeControllerManager)
+		pathChecks.kubeSchedulerChecks.removedFlags = target.FlagsRemovedIn(compatibility.ComponentKubeScheduler)
+		pathChecks.kubeletChecks.removedFlags = target.FlagsRemovedIn(compatibility.ComponentKubelet)
+		pathChecks.kubeProxyChecks.removedFlags = target.FlagsRemovedIn(compatibility.ComponentKubeProxy)
+
+		checks[path] = pathChecks
+	}
+
+	return checks
+}
+
+// NewChecks initializes and returns Checks.
+func NewChecks(path *Path, state state.State, k8sConfig *rest.Config, controlPlaneNodes, workerNodes []string, logFunc func(string, ...any), opts ...ChecksOption) (*Checks, error) {
+	options := ChecksOptions{
+		CheckTimeout: DefaultCheckTimeout,
+		NodeTimeout:  DefaultNodeTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &Checks{
+		options:             options,
+		state:               state,
+		k8sConfig:           k8sConfig,
+		log:                 logFunc,
+		path:                path,
+		upgradePath:         path.String(),
+		controlPlaneNodes:   controlPlaneNodes,
+		workerNodes:         workerNodes,
+		upgradeVersionCheck: upgradeVersionChecks,
 	}, nil
 }
 
+// RunOptions configures Checks.Run.
+type RunOptions struct {
+	// BlockingSeverities are the severities of the findings which fail the checks.
+	BlockingSeverities []Severity
+}
+
+// RunOption modifies RunOptions.
+type RunOption func(*RunOptions)
+
+// WithBlockingSeverities sets the severities of the findings which block the upgrade (default is SeverityError).
+//
+// Findings of other severities are only logged, e.g. passing no severities allows to "warn but proceed".
+func WithBlockingSeverities(severities ...Severity) RunOption {
+	return func(o *RunOptions) {
+		o.BlockingSeverities = severities
+	}
+}
+
 // Run executes the checks.
 //
-//nolint:gocognit
-func (checks *Checks) Run(ctx context.Context) error {
+// The blocking findings are returned as ComponentRemovedItemsError, other findings are logged.
+func (checks *Checks) Run(ctx context.Context, opts ...RunOption) error {
+	options := RunOptions{
+		BlockingSeverities: []Severity{SeverityError},
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	var k8sComponentCheck ComponentRemovedItemsError
 
-	if k8sComponentChecks, ok := checks.upgradeVersionCheck[checks.upgradePath]; ok {
-		checks.log("checking for removed Kubernetes component flags")
-
-		for _, node := range checks.controlPlaneNodes {
-			for _, id := range []string{k8s.APIServerID, k8s.ControllerManagerID, k8s.SchedulerID} {
-				staticPod, err := safe.StateGet[*k8s.StaticPod](client.WithNode(ctx, node), checks.state, k8s.NewStaticPod(k8s.NamespaceName, id).Metadata())
-				if err != nil {
-					if state.IsNotFoundError(err) {
-						continue
-					}
-
-					return err
-				}
-
-				pod, err := staticPodTypedResourceToK8sPodSpec(staticPod)
-				if err != nil {
-					return err
-				}
-
-				switch id {
-				case k8s.APIServerID:
-					k8sComponentCheck.PopulateRemovedAdmissionPlugins(node, id, pod.Spec.Containers[0].Command, k8sComponentChecks.kubeAPIServerChecks.removedAdmissionPlugins)
-					k8sComponentCheck.PopulateRemovedCLIFlags(node, id, pod.Spec.Containers[0].Command, k8sComponentChecks.kubeAPIServerChecks.componentCheck.removedFlags)
-				case k8s.ControllerManagerID:
-					k8sComponentCheck.PopulateRemovedCLIFlags(node, id, pod.Spec.Containers[0].Command, k8sComponentChecks.kubeControllerManagerChecks.removedFlags)
-				case k8s.SchedulerID:
-					k8sComponentCheck.PopulateRemovedCLIFlags(node, id, pod.Spec.Containers[0].Command, k8sComponentChecks.kubeSchedulerChecks.removedFlags)
-				}
-
-				k8sComponentCheck.PopulateRemovedFeatureGates(node, id, pod.Spec.Containers[0].Command, k8sComponentChecks.removedFeatureGates)
-			}
+	if err := checks.populate(ctx, &k8sComponentCheck); err != nil {
+		return err
+	}
+
+	for _, finding := range k8sComponentCheck.Report().Findings {
+		if !slices.Contains(options.BlockingSeverities, finding.Severity) {
+			checks.log("%s: %s", finding.Severity, finding.Message)
 		}
+	}
 
-		for _, node := range append(append([]string(nil), checks.controlPlaneNodes...), checks.workerNodes...) {
-			kubeletSpec, err := safe.StateGet[*k8s.KubeletSpec](client.WithNode(ctx, node), checks.state, k8s.NewKubeletSpec(k8s.NamespaceName, k8s.KubeletID).Metadata())
-			if err != nil {
-				if state.IsNotFoundError(err) {
-					continue
-				}
+	// built-in removed items are always errors
+	result := ComponentRemovedItemsError{}
 
-				return err
-			}
+	if slices.Contains(options.BlockingSeverities, SeverityError) {
+		result = k8sComponentCheck
+		result.Findings = nil
+	}
 
-			k8sComponentCheck.PopulateRemovedCLIFlags(node, k8s.KubeletID, kubeletSpec.TypedSpec().Args, k8sComponentChecks.kubeletChecks.removedFlags)
+	for _, finding := range k8sComponentCheck.Findings {
+		if slices.Contains(options.BlockingSeverities, finding.Severity) {
+			result.Findings = append(result.Findings, finding)
 		}
+	}
+
+	return result.ErrorOrNil()
+}
 
-		checks.log("checking for removed Kubernetes API resource versions")
+// RunReport executes the checks returning all findings as a Report, including the non-blocking ones.
+//
+// The error is returned only if the checks failed to run.
+func (checks *Checks) RunReport(ctx context.Context) (*Report, error) {
+	var k8sComponentCheck ComponentRemovedItemsError
 
-		if err := k8sComponentCheck.PopulateRemovedAPIResources(ctx, checks.k8sConfig, k8sComponentChecks.kubeAPIServerChecks.removedAPIResources); err != nil {
-			return err
-		}
+	if err := checks.populate(ctx, &k8sComponentCheck); err != nil {
+		return nil, err
 	}
 
-	return k8sComponentCheck.ErrorOrNil()
+	report := k8sComponentCheck.Report()
+	report.Path = checks.upgradePath
+
+	return &report, nil
 }
 
 // PopulateRemovedCLIFlags populates the removed flags.
+//
+//nolint:gocognit
 func (e *ComponentRemovedItemsError) PopulateRemovedCLIFlags(node, component string, cliFlags []string, removedFlags []string) {
 	for _, removedFlag := range removedFlags {
 		if slices.ContainsFunc(cliFlags, func(s string) bool {
@@ -344,7 +531,8 @@ func (e *ComponentRemovedItemsError) PopulateRemovedFeatureGates(node, component
 	})
 
 	if len(featureGateFlags) > 0 {
-		featureGates := strings.Split(strings.TrimPrefix(featureGateFlags[0], "--feature-gates="), ",")
+		flagValue := strings.TrimPrefix(featureGateFlags[0], "--feature-gates=")
+		featureGates := strings.Split(flagValue, ",")
 
 		for _, removedFeatureGate := range removedFeatureGates {
 			if slices.ContainsFunc(featureGates, func(s string) bool {
@@ -354,12 +542,73 @@ func (e *ComponentRemovedItemsError) PopulateRemovedFeatureGates(node, component
 					Node:      node,
 					Component: component,
 					Value:     removedFeatureGate,
+					FlagValue: flagValue,
 				})
 			}
 		}
 	}
 }
 
+// PopulateRemovedKubeletConfigFields populates the removed fields set in the kubelet configuration.
+func (e *ComponentRemovedItemsError) PopulateRemovedKubeletConfigFields(node string, config map[string]any, removedFields []string) {
+	for _, removedField := range removedFields {
+		if _, found, _ := unstructured.NestedFieldNoCopy(config, strings.Split(removedField, ".")...); found {
+			e.ConfigFields = append(e.ConfigFields, ComponentItem{
+				Node:      node,
+				Component: k8s.KubeletID,
+				Value:     removedField,
+			})
+		}
+	}
+}
+
+// PopulateRemovedKubeletConfigFeatureGates populates the removed feature gates set in the kubelet configuration.
+func (e *ComponentRemovedItemsError) PopulateRemovedKubeletConfigFeatureGates(node string, config map[string]any, removedFeatureGates []string) {
+	e.populateConfigFeatureGates(node, k8s.KubeletID, config, removedFeatureGates)
+}
+
+// populateConfigFeatureGates populates the removed feature gates set in the component configuration featureGates map.
+func (e *ComponentRemovedItemsError) populateConfigFeatureGates(node, component string, config map[string]any, removedFeatureGates []string) {
+	featureGates, _, _ := unstructured.NestedMap(config, "featureGates") //nolint:errcheck
+
+	for _, removedFeatureGate := range removedFeatureGates {
+		if _, ok := featureGates[removedFeatureGate]; ok {
+			e.FeatureGates = append(e.FeatureGates, ComponentItem{
+				Node:      node,
+				Component: component,
+				Value:     removedFeatureGate,
+			})
+		}
+	}
+}
+
+// PopulateChangedAdmissionPlugins reports the admission plugins whose default behavior changes in the upgrade version.
+//
+// The plugins explicitly enabled or disabled with the component flags are not reported, as the default doesn't apply to them.
+func (e *ComponentRemovedItemsError) PopulateChangedAdmissionPlugins(node, component string, cliFlags []string, changedAdmissionPlugins []admissionPluginChange) {
+	configured := append(
+		strings.Split(flagValue(cliFlags, "enable-admission-plugins"), ","),
+		strings.Split(flagValue(cliFlags, "disable-admission-plugins"), ",")...,
+	)
+
+	for _, plugin := range changedAdmissionPlugins {
+		if slices.Contains(configured, plugin.name) {
+			continue
+		}
+
+		e.Findings = append(e.Findings, Finding{
+			Check:       findingChangedAdmissionPlugin,
+			Node:        node,
+			Component:   component,
+			Message:     fmt.Sprintf("admission plugin %s %s", plugin.name, plugin.change),
+			Remediation: fmt.Sprintf("review the workloads affected by %s, or disable it with --disable-admission-plugins in %s", plugin.name, machineConfigArgs(component)),
+			DocURL:      admissionPluginsURL,
+			Severity:    SeverityWarning,
+			Item:        plugin.name,
+		})
+	}
+}
+
 // PopulateRemovedAdmissionPlugins populates the removed admission plugins.
 func (e *ComponentRemovedItemsError) PopulateRemovedAdmissionPlugins(node, component string, cliFlags []string, removedAdmissionPlugins []string) {
 	admissionFlags := xslices.Filter(cliFlags, func(s string) bool {
@@ -367,7 +616,8 @@ func (e *ComponentRemovedItemsError) PopulateRemovedAdmissionPlugins(node, compo
 	})
 
 	if len(admissionFlags) > 0 {
-		admissionPlugins := strings.Split(strings.TrimPrefix(admissionFlags[0], "--enable-admission-plugins="), ",")
+		flagValue := strings.TrimPrefix(admissionFlags[0], "--enable-admission-plugins=")
+		admissionPlugins := strings.Split(flagValue, ",")
 
 		for _, removedAdmissionPlugin := range removedAdmissionPlugins {
 			if slices.ContainsFunc(admissionPlugins, func(s string) bool {
@@ -377,6 +627,7 @@ func (e *ComponentRemovedItemsError) PopulateRemovedAdmissionPlugins(node, compo
 					Node:      node,
 					Component: component,
 					Value:     removedAdmissionPlugin,
+					FlagValue: flagValue,
 				})
 			}
 		}
@@ -385,43 +636,44 @@ func (e *ComponentRemovedItemsError) PopulateRemovedAdmissionPlugins(node, compo
 
 // PopulateRemovedAPIResources populates the removed API resources.
 func (e *ComponentRemovedItemsError) PopulateRemovedAPIResources(ctx context.Context, k8sConfig *rest.Config, removedAPIResources []string) error {
-	if len(removedAPIResources) == 0 || k8sConfig == nil {
-		return nil
-	}
-
-	// copy the config to avoid mutating input argument
-	k8sConfigCopy := *k8sConfig
-	k8sConfigCopy.WarningHandler = rest.NewWarningWriter(io.Discard, rest.WarningWriterOptions{})
-
-	k8sClient, err := dynamic.NewForConfig(&k8sConfigCopy)
+	counts, err := countAPIResources(ctx, k8sConfig, removedAPIResources)
 	if err != nil {
-		return fmt.Errorf("error building kubernetes client: %w", err)
+		return err
 	}
 
 	for _, resource := range removedAPIResources {
-		gvr, _ := schema.ParseResourceArg(resource)
-
-		if gvr == nil {
-			return fmt.Errorf("failed to parse group version resource %s", resource)
-		}
-
-		res, err := k8sClient.Resource(*gvr).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			if apierrors.IsNotFound(err) {
-				continue
+		if count := counts[resource]; count.count > 0 {
+			if e.APIResources == nil {
+				e.APIResources = make(map[string]int)
+				e.APIResourceSamples = make(map[string][]string)
 			}
 
-			return err
+			e.APIResources[resource] = count.count
+			e.APIResourceSamples[resource] = count.samples
 		}
+	}
 
-		count := len(res.Items)
+	return nil
+}
 
-		if count > 0 {
-			if e.APIResources == nil {
-				e.APIResources = make(map[string]int)
-			}
+// PopulateDeprecatedAPIResources populates the API resources deprecated in the upgrade version as warning findings.
+func (e *ComponentRemovedItemsError) PopulateDeprecatedAPIResources(ctx context.Context, k8sConfig *rest.Config, path *Path, deprecatedAPIResources []string) error {
+	counts, err := countAPIResources(ctx, k8sConfig, deprecatedAPIResources)
+	if err != nil {
+		return err
+	}
 
-			e.APIResources[resource] = count
+	for _, resource := range deprecatedAPIResources {
+		if count := counts[resource]; count.count > 0 {
+			e.Findings = append(e.Findings, Finding{
+				Check:     CheckDeprecatedAPIResources,
+				Component: k8s.APIServerID,
+				Message: fmt.Sprintf("%s is deprecated in %d.%d and will be removed in a future release, %d object(s) found%s",
+					resource, path.to.Major, path.to.Minor, count.count, formatSamples(count.samples)),
+				Remediation: "migrate the manifests and clients to the supported API version",
+				DocURL:      deprecationGuideURL,
+				Severity:    SeverityWarning,
+			})
 		}
 	}
 
@@ -442,43 +694,60 @@ func staticPodTypedResourceToK8sPodSpec(staticPod *k8s.StaticPod) (*v1.Pod, erro
 }
 
 // Error returns the error message.
+//
+// The same items found on multiple nodes are reported once, with the list of the nodes, see Details for the full list.
 func (e ComponentRemovedItemsError) Error() string {
+	return e.render(true)
+}
+
+// Details returns the error message listing each item found on each node separately.
+func (e ComponentRemovedItemsError) Details() string {
+	return e.render(false)
+}
+
+func (e ComponentRemovedItemsError) render(grouped bool) string {
 	var buf strings.Builder
 
 	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
 
-	if len(e.AdmissionFlags) > 0 {
-		fmt.Fprintf(w, "\nNODE\tCOMPONENT\tREMOVED ADMISSION PLUGIN\n") //nolint:errcheck
-
-		for _, item := range e.AdmissionFlags {
-			fmt.Fprintf(w, "%s\t%s\t%s\n", item.Node, item.Component, item.Value) //nolint:errcheck
+	for _, section := range []struct {
+		header string
+		items  []ComponentItem
+	}{
+		{"REMOVED ADMISSION PLUGIN", e.AdmissionFlags},
+		{"REMOVED FEATURE GATE", e.FeatureGates},
+		{"REMOVED FLAG", e.CLIFlags},
+		{"REMOVED CONFIG FIELD", e.ConfigFields},
+	} {
+		if len(section.items) == 0 {
+			continue
 		}
-	}
 
-	if len(e.FeatureGates) > 0 {
-		fmt.Fprintf(w, "\nNODE\tCOMPONENT\tREMOVED FEATURE GATE\n") //nolint:errcheck
+		fmt.Fprintf(w, "\nNODE\tCOMPONENT\t%s\n", section.header) //nolint:errcheck
 
-		for _, item := range e.FeatureGates {
-			fmt.Fprintf(w, "%s\t%s\t%s\n", item.Node, item.Component, item.Value) //nolint:errcheck
+		for _, item := range groupItems(section.items, grouped) {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", formatNodes(item.Nodes), item.Component, item.Value) //nolint:errcheck
 		}
 	}
 
-	if len(e.CLIFlags) > 0 {
-		fmt.Fprintf(w, "\nNODE\tCOMPONENT\tREMOVED FLAG\n") //nolint:errcheck
+	if len(e.APIResources) > 0 {
+		fmt.Fprintf(w, "\nREMOVED RESOURCE\tCOUNT\tEXAMPLES\n") //nolint:errcheck
 
-		for _, item := range e.CLIFlags {
-			fmt.Fprintf(w, "%s\t%s\t%s\n", item.Node, item.Component, item.Value) //nolint:errcheck
+		for apiVersion, count := range e.APIResources {
+			fmt.Fprintf(w, "%s\t%d\t%s\n", apiVersion, count, strings.Join(e.APIResourceSamples[apiVersion], ", ")) //nolint:errcheck
 		}
 	}
 
-	if len(e.APIResources) > 0 {
-		fmt.Fprintf(w, "\nREMOVED RESOURCE\tCOUNT\t\n") //nolint:errcheck
+	if len(e.Findings) > 0 {
+		fmt.Fprintf(w, "\nNODE\tCOMPONENT\tCHECK\tFINDING\n") //nolint:errcheck
 
-		for apiVersion, count := range e.APIResources {
-			fmt.Fprintf(w, "%s\t%d\t\n", apiVersion, count) //nolint:errcheck
+		for _, item := range groupFindings(e.Findings, grouped) {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", formatNodes(item.Nodes), item.Component, item.Check, item.Message) //nolint:errcheck
 		}
 	}
 
+	writeRemediations(w, e.Report().Findings, grouped)
+
 	//nolint:errcheck
 	w.Flush()
 
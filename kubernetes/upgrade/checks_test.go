@@ -6,16 +6,28 @@ package upgrade_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/state"
 	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
 	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/siderolabs/gen/xslices"
 	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+	"github.com/siderolabs/talos/pkg/machinery/resources/perf"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"k8s.io/client-go/rest"
 
 	"github.com/siderolabs/go-kubernetes/kubernetes/upgrade"
 )
@@ -41,6 +53,8 @@ func TestK8sComponentRemovedItemsNoError(t *testing.T) {
 		}
 
 		require.NoError(t, resourceState.Create(ctx, cfg))
+
+		createStaticPodStatus(ctx, t, resourceState, id, "Running", "True")
 	}
 
 	path, err := upgrade.NewPath("1.24.3", "1.25.0")
@@ -97,6 +111,7 @@ func TestK8sComponentRemovedItemsWithError(t *testing.T) {
 				Node:      "10.5.0.2",
 				Component: "kube-apiserver",
 				Value:     "PodSecurityPolicy",
+				FlagValue: "NodeRestriction,PodSecurityPolicy",
 			},
 		},
 		CLIFlags: []upgrade.ComponentItem{
@@ -116,16 +131,19 @@ func TestK8sComponentRemovedItemsWithError(t *testing.T) {
 				Node:      "10.5.0.2",
 				Component: "kube-apiserver",
 				Value:     "CSIVolumeFSGroupPolicy",
+				FlagValue: "RotateKubeletServerCertificate=true,CSIVolumeFSGroupPolicy=true",
 			},
 			{
 				Node:      "10.5.0.2",
 				Component: "kube-controller-manager",
 				Value:     "CSIVolumeFSGroupPolicy",
+				FlagValue: "RotateKubeletServerCertificate=true,CSIVolumeFSGroupPolicy=true",
 			},
 			{
 				Node:      "10.5.0.2",
 				Component: "kube-scheduler",
 				Value:     "CSIVolumeFSGroupPolicy",
+				FlagValue: "RotateKubeletServerCertificate=true,CSIVolumeFSGroupPolicy=true",
 			},
 		},
 	}
@@ -143,6 +161,8 @@ func TestK8sComponentRemovedItemsWithError(t *testing.T) {
 		}
 
 		require.NoError(t, resourceState.Create(ctx, cfg))
+
+		createStaticPodStatus(ctx, t, resourceState, id, "Running", "True")
 	}
 
 	path, err := upgrade.NewPath("1.24.3", "1.25.0")
@@ -160,6 +180,10 @@ func TestK8sComponentRemovedItemsWithError(t *testing.T) {
 	}
 
 	assert.Equal(t, expected, removedItemsError)
+
+	assert.Contains(t, checkErrors.Error(), "SUGGESTED FIX")
+	assert.Contains(t, checkErrors.Error(), "migrate PodSecurityPolicy objects to Pod Security Admission")
+	assert.Contains(t, checkErrors.Error(), "remove CSIVolumeFSGroupPolicy from --feature-gates in cluster.controllerManager.extraArgs")
 }
 
 func TestK8sComponentRemovedItemsWithKubeletError(t *testing.T) {
@@ -237,31 +261,37 @@ func TestK8sComponentRemovedItemsWithKubeletError(t *testing.T) {
 				Node:      "10.5.0.2",
 				Component: "kube-apiserver",
 				Value:     "ExpandCSIVolumes",
+				FlagValue: "ExpandCSIVolumes=true,StatefulSetMinReadySeconds=true",
 			},
 			{
 				Node:      "10.5.0.2",
 				Component: "kube-apiserver",
 				Value:     "StatefulSetMinReadySeconds",
+				FlagValue: "ExpandCSIVolumes=true,StatefulSetMinReadySeconds=true",
 			},
 			{
 				Node:      "10.5.0.2",
 				Component: "kube-controller-manager",
 				Value:     "ExpandCSIVolumes",
+				FlagValue: "ExpandCSIVolumes=true,StatefulSetMinReadySeconds=true",
 			},
 			{
 				Node:      "10.5.0.2",
 				Component: "kube-controller-manager",
 				Value:     "StatefulSetMinReadySeconds",
+				FlagValue: "ExpandCSIVolumes=true,StatefulSetMinReadySeconds=true",
 			},
 			{
 				Node:      "10.5.0.2",
 				Component: "kube-scheduler",
 				Value:     "ExpandCSIVolumes",
+				FlagValue: "ExpandCSIVolumes=true,StatefulSetMinReadySeconds=true",
 			},
 			{
 				Node:      "10.5.0.2",
 				Component: "kube-scheduler",
 				Value:     "StatefulSetMinReadySeconds",
+				FlagValue: "ExpandCSIVolumes=true,StatefulSetMinReadySeconds=true",
 			},
 		},
 	}
@@ -279,6 +309,8 @@ func TestK8sComponentRemovedItemsWithKubeletError(t *testing.T) {
 		}
 
 		require.NoError(t, resourceState.Create(ctx, cfg))
+
+		createStaticPodStatus(ctx, t, resourceState, id, "Running", "True")
 	}
 
 	cfg := k8s.NewKubeletSpec(k8s.NamespaceName, k8s.KubeletID)
@@ -309,3 +341,1997 @@ func TestK8sComponentRemovedItemsWithKubeletError(t *testing.T) {
 
 	assert.Equal(t, expected, removedItemsError)
 }
+
+func TestCustomChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.30.1", "1.31.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, nil, []string{"10.5.0.2"}, []string{"10.5.0.3"}, t.Logf)
+	require.NoError(t, err)
+
+	checks.Register("nodes", func(_ context.Context, env upgrade.CheckEnv) ([]upgrade.Finding, error) {
+		assert.Equal(t, "1.30->1.31", env.Path.String())
+		assert.Equal(t, []string{"10.5.0.3"}, env.WorkerNodes)
+
+		return []upgrade.Finding{
+			{
+				Node:      "10.5.0.3",
+				Component: "kubelet",
+				Message:   "node is not ready",
+			},
+		}, nil
+	})
+
+	checkErrors := checks.Run(ctx)
+
+	var removedItemsError upgrade.ComponentRemovedItemsError
+
+	require.ErrorAs(t, checkErrors, &removedItemsError)
+
+	assert.Equal(t, []upgrade.Finding{
+		{
+			Check:     "nodes",
+			Node:      "10.5.0.3",
+			Component: "kubelet",
+			Message:   "node is not ready",
+		},
+	}, removedItemsError.Findings)
+	assert.Contains(t, checkErrors.Error(), "node is not ready")
+
+	// registering with the same name replaces the check
+	checks.Register("nodes", func(context.Context, upgrade.CheckEnv) ([]upgrade.Finding, error) {
+		return nil, errors.New("failed")
+	})
+
+	require.ErrorContains(t, checks.Run(ctx), `error running check "nodes": failed`)
+}
+
+func TestWarningFindings(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.28.3", "1.29.0")
+	require.NoError(t, err)
+
+	var logged []string
+
+	checks, err := upgrade.NewChecks(path, resourceState, nil, []string{"10.5.0.2"}, nil, func(format string, args ...any) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})
+	require.NoError(t, err)
+
+	checks.Register("soon-removed", func(context.Context, upgrade.CheckEnv) ([]upgrade.Finding, error) {
+		return []upgrade.Finding{
+			{
+				Component: "kube-apiserver",
+				Message:   "flag is deprecated",
+				Severity:  upgrade.SeverityWarning,
+			},
+		}, nil
+	})
+
+	assert.NoError(t, checks.Run(ctx))
+	assert.Contains(t, logged, "warning: flag is deprecated")
+}
+
+func TestRequestedDeprecatedAPIs(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		//nolint:errcheck
+		w.Write([]byte(`# HELP apiserver_requested_deprecated_apis [STABLE] Gauge of deprecated APIs that have been requested, broken out by API group, version, resource, subresource, and removed_release.
+# TYPE apiserver_requested_deprecated_apis gauge
+apiserver_requested_deprecated_apis{group="flowcontrol.apiserver.k8s.io",removed_release="1.32",resource="flowschemas",subresource="",version="v1beta3"} 1
+apiserver_requested_deprecated_apis{group="resource.k8s.io",removed_release="",resource="resourceslices",subresource="",version="v1beta1"} 1
+apiserver_request_total{code="200"} 100
+`))
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.31.3", "1.32.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, nil, t.Logf)
+	require.NoError(t, err)
+
+	checkErrors := checks.Run(ctx)
+
+	var removedItemsError upgrade.ComponentRemovedItemsError
+
+	require.ErrorAs(t, checkErrors, &removedItemsError)
+
+	require.Len(t, removedItemsError.Findings, 1)
+
+	assert.Equal(t, "requested-deprecated-api", removedItemsError.Findings[0].Check)
+	assert.Equal(t, "deprecated API flowschemas.v1beta3.flowcontrol.apiserver.k8s.io is requested by the clients, it will be removed in 1.32",
+		removedItemsError.Findings[0].Message)
+	assert.Equal(t, upgrade.SeverityError, removedItemsError.Findings[0].Severity)
+
+	// warnings block the upgrade as well
+	checkErrors = checks.Run(ctx, upgrade.WithBlockingSeverities(upgrade.SeverityError, upgrade.SeverityWarning))
+
+	require.ErrorAs(t, checkErrors, &removedItemsError)
+	require.Len(t, removedItemsError.Findings, 2)
+
+	assert.Equal(t, "deprecated API resourceslices.v1beta1.resource.k8s.io is requested by the clients", removedItemsError.Findings[1].Message)
+	assert.Equal(t, upgrade.SeverityWarning, removedItemsError.Findings[1].Severity)
+
+	// warn but proceed
+	assert.NoError(t, checks.Run(ctx, upgrade.WithBlockingSeverities()))
+}
+
+func TestReport(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	cfg := k8s.NewKubeletSpec(k8s.NamespaceName, k8s.KubeletID)
+	cfg.TypedSpec().Args = []string{
+		"--container-runtime=containerd",
+	}
+
+	require.NoError(t, resourceState.Create(ctx, cfg))
+
+	path, err := upgrade.NewPath("1.26.3", "1.27.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, nil, nil, []string{"10.5.0.3"}, t.Logf)
+	require.NoError(t, err)
+
+	checks.Register("custom", func(context.Context, upgrade.CheckEnv) ([]upgrade.Finding, error) {
+		return []upgrade.Finding{
+			{
+				Message:  "something to look at",
+				Severity: upgrade.SeverityWarning,
+			},
+		}, nil
+	})
+
+	report, err := checks.RunReport(ctx)
+	require.NoError(t, err)
+
+	assert.True(t, report.HasErrors())
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"path": "1.26->1.27",
+		"findings": [
+			{
+				"check": "removed-flag",
+				"node": "10.5.0.3",
+				"component": "kubelet",
+				"message": "flag --container-runtime is removed",
+				"remediation": "remove --container-runtime from machine.kubelet.extraArgs in the machine config",
+				"item": "container-runtime",
+				"severity": "error"
+			},
+			{
+				"check": "custom",
+				"message": "something to look at",
+				"severity": "warning"
+			}
+		]
+	}`, string(data))
+
+	var decoded upgrade.Report
+
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, *report, decoded)
+}
+
+func TestKubeletConfigChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	cfg := k8s.NewKubeletSpec(k8s.NamespaceName, k8s.KubeletID)
+	cfg.TypedSpec().Args = []string{
+		"--feature-gates=CSIMigration=true",
+	}
+	cfg.TypedSpec().Config = map[string]any{
+		"featureGates": map[string]any{
+			"ExpandCSIVolumes":               true,
+			"GracefulNodeShutdown":           true,
+			"DaemonSetUpdateSurge":           false,
+			"RotateKubeletServerCertificate": true,
+		},
+	}
+
+	require.NoError(t, resourceState.Create(ctx, cfg))
+
+	path, err := upgrade.NewPath("1.26.3", "1.27.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, nil, nil, []string{"10.5.0.3"}, t.Logf)
+	require.NoError(t, err)
+
+	var removedItemsError upgrade.ComponentRemovedItemsError
+
+	require.ErrorAs(t, checks.Run(ctx), &removedItemsError)
+
+	assert.Equal(t, []upgrade.ComponentItem{
+		{Node: "10.5.0.3", Component: "kubelet", Value: "CSIMigration", FlagValue: "CSIMigration=true"},
+		{Node: "10.5.0.3", Component: "kubelet", Value: "ExpandCSIVolumes"},
+		{Node: "10.5.0.3", Component: "kubelet", Value: "DaemonSetUpdateSurge"},
+	}, removedItemsError.FeatureGates)
+
+	var configFieldsError upgrade.ComponentRemovedItemsError
+
+	configFieldsError.PopulateRemovedKubeletConfigFields("10.5.0.3", map[string]any{
+		"logging": map[string]any{
+			"format": "json",
+		},
+		"address": "0.0.0.0",
+	}, []string{"logging.format", "logging.flushFrequency", "address", "port"})
+
+	assert.Equal(t, []upgrade.ComponentItem{
+		{Node: "10.5.0.3", Component: "kubelet", Value: "logging.format"},
+		{Node: "10.5.0.3", Component: "kubelet", Value: "address"},
+	}, configFieldsError.ConfigFields)
+	assert.Contains(t, configFieldsError.Error(), "REMOVED CONFIG FIELD")
+}
+
+func TestKubeProxyChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/apis/apps/v1/namespaces/kube-system/daemonsets/kube-proxy":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"apps/v1","kind":"DaemonSet","metadata":{"name":"kube-proxy","namespace":"kube-system"},
+"spec":{"template":{"spec":{"containers":[{"name":"kube-proxy","command":["/usr/local/bin/kube-proxy"],
+"args":["--cluster-cidr=10.244.0.0/16","--feature-gates=CPUManager=true"]}]}}}}`))
+		case "/api/v1/namespaces/kube-system/configmaps/kube-proxy":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"kube-proxy","namespace":"kube-system"},
+"data":{"config.conf":"apiVersion: kubeproxy.config.k8s.io/v1alpha1\nkind: KubeProxyConfiguration\nmode: iptables\nfeatureGates:\n  KubeProxyDrainingTerminatingNodes: true\n"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.32.1", "1.33.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, nil, t.Logf)
+	require.NoError(t, err)
+
+	report, err := checks.RunReport(ctx)
+	require.NoError(t, err)
+
+	var gates, modes []upgrade.Finding
+
+	for _, finding := range report.Findings {
+		switch finding.Check {
+		case "removed-feature-gate":
+			gates = append(gates, finding)
+		case upgrade.CheckKubeProxyMode:
+			modes = append(modes, finding)
+		}
+	}
+
+	require.Len(t, gates, 2)
+
+	for _, finding := range gates {
+		assert.Equal(t, "kube-proxy", finding.Component)
+		assert.Equal(t, upgrade.SeverityError, finding.Severity)
+	}
+
+	require.Len(t, modes, 1)
+	assert.Equal(t, upgrade.SeverityInfo, modes[0].Severity)
+
+	// informational findings don't block the upgrade
+	checkErrors := checks.Run(ctx)
+
+	var removedItemsError upgrade.ComponentRemovedItemsError
+
+	require.ErrorAs(t, checkErrors, &removedItemsError)
+	assert.Len(t, removedItemsError.FeatureGates, 2)
+	assert.Empty(t, removedItemsError.Findings)
+
+	// the proxy mode check can be skipped on its own
+	checks, err = upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, nil, t.Logf,
+		upgrade.WithSkipChecks(upgrade.CheckKubeProxyMode))
+	require.NoError(t, err)
+
+	report, err = checks.RunReport(ctx)
+	require.NoError(t, err)
+
+	for _, finding := range report.Findings {
+		assert.NotEqual(t, upgrade.CheckKubeProxyMode, finding.Check)
+	}
+}
+
+func TestForbiddenChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/apis/admissionregistration.k8s.io/v1/validatingwebhookconfigurations":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"admissionregistration.k8s.io/v1","kind":"ValidatingWebhookConfigurationList","items":[
+{"metadata":{"name":"policy"},"webhooks":[
+ {"name":"pods.policy.example.com","clientConfig":{"service":{"namespace":"policy","name":"down"}},
+  "rules":[{"apiGroups":[""],"apiVersions":["v1"],"resources":["pods"],"operations":["CREATE"]}]}]}]}`))
+		case "/apis/admissionregistration.k8s.io/v1/mutatingwebhookconfigurations":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"admissionregistration.k8s.io/v1","kind":"MutatingWebhookConfigurationList","items":[]}`))
+		case "/apis/apps/v1/namespaces/kube-system/daemonsets/kube-proxy",
+			"/api/v1/namespaces/kube-system/configmaps/kube-proxy",
+			"/apis/discovery.k8s.io/v1/namespaces/policy/endpointslices":
+			w.WriteHeader(http.StatusForbidden)
+
+			//nolint:errcheck
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.32.1", "1.33.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, nil, t.Logf,
+		upgrade.WithOnlyChecks(upgrade.CheckKubeProxy, upgrade.CheckKubeProxyMode, upgrade.CheckAdmissionWebhooks))
+	require.NoError(t, err)
+
+	// the checks are skipped if the objects can't be accessed
+	report, err := checks.RunReport(ctx)
+	require.NoError(t, err)
+
+	assert.Empty(t, report.Findings)
+}
+
+func TestKubeProxyRemovedFlagChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/apis/apps/v1/namespaces/kube-system/daemonsets/kube-proxy":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"apps/v1","kind":"DaemonSet","metadata":{"name":"kube-proxy","namespace":"kube-system"},
+"spec":{"template":{"spec":{"containers":[{"name":"kube-proxy","command":["/usr/local/bin/kube-proxy"],
+"args":["--cluster-cidr=10.244.0.0/16","--healthz-port=10256","--metrics-port=10249"]}]}}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.31.4", "1.32.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, nil, t.Logf,
+		upgrade.WithOnlyChecks(upgrade.CheckKubeProxy))
+	require.NoError(t, err)
+
+	var removedItemsError upgrade.ComponentRemovedItemsError
+
+	require.ErrorAs(t, checks.Run(ctx), &removedItemsError)
+	assert.Equal(t, []upgrade.ComponentItem{
+		{Component: "kube-proxy", Value: "healthz-port"},
+		{Component: "kube-proxy", Value: "metrics-port"},
+	}, removedItemsError.CLIFlags)
+}
+
+func TestVersionSkewChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/nodes":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"v1","kind":"NodeList","items":[
+{"metadata":{"name":"cp1"},"status":{"nodeInfo":{"kubeletVersion":"v1.33.1"}}},
+{"metadata":{"name":"worker1"},"status":{"nodeInfo":{"kubeletVersion":"v1.31.4"}}},
+{"metadata":{"name":"worker2"},"status":{"nodeInfo":{"kubeletVersion":"v1.30.2"}}}]}`))
+		case "/api/v1/namespaces/kube-system/pods":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"v1","kind":"PodList","items":[
+{"metadata":{"name":"kube-apiserver-cp1","labels":{"k8s-app":"kube-apiserver"}},
+ "spec":{"nodeName":"cp1","containers":[{"name":"kube-apiserver","image":"registry.k8s.io/kube-apiserver:v1.33.1"}]}},
+{"metadata":{"name":"kube-apiserver-cp2","labels":{"component":"kube-apiserver"}},
+ "spec":{"nodeName":"cp2","containers":[{"name":"kube-apiserver","image":"registry.k8s.io/kube-apiserver:v1.32.3@sha256:0000"}]}},
+{"metadata":{"name":"kube-scheduler-cp1","labels":{"k8s-app":"kube-scheduler"}},
+ "spec":{"nodeName":"cp1","containers":[{"name":"kube-scheduler","image":"localhost:5000/kube-scheduler:v1.33.1"}]}},
+{"metadata":{"name":"coredns","labels":{"k8s-app":"kube-dns"}},
+ "spec":{"nodeName":"cp1","containers":[{"name":"coredns","image":"registry.k8s.io/coredns/coredns:v1.11.1"}]}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.33.1", "1.34.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, nil, t.Logf, upgrade.WithSkipChecks(upgrade.CheckNodeReadiness))
+	require.NoError(t, err)
+
+	checkErrors := checks.Run(ctx)
+
+	var removedItemsError upgrade.ComponentRemovedItemsError
+
+	require.ErrorAs(t, checkErrors, &removedItemsError)
+	require.Len(t, removedItemsError.Findings, 2)
+
+	assert.Equal(t, "version-skew", removedItemsError.Findings[0].Check)
+	assert.Equal(t, "worker2", removedItemsError.Findings[0].Node)
+	assert.Equal(t, "kubelet", removedItemsError.Findings[0].Component)
+	assert.Equal(t, "kubelet 1.30.2 is more than 3 minor version(s) older than the upgrade version 1.34", removedItemsError.Findings[0].Message)
+
+	assert.Equal(t, "cp2", removedItemsError.Findings[1].Node)
+	assert.Equal(t, "kube-apiserver", removedItemsError.Findings[1].Component)
+	assert.Equal(t, "kube-apiserver 1.32.3 is more than 1 minor version(s) older than the upgrade version 1.34", removedItemsError.Findings[1].Message)
+	assert.Equal(t, upgrade.SeverityError, removedItemsError.Findings[1].Severity)
+}
+
+func createStaticPodStatus(ctx context.Context, t *testing.T, st state.State, id, phase, ready string) {
+	t.Helper()
+
+	status := k8s.NewStaticPodStatus(k8s.NamespaceName, "kube-system/"+id+"-talos-default-controlplane-1")
+	status.TypedSpec().PodStatus = map[string]any{
+		"phase": phase,
+		"conditions": []any{
+			map[string]any{
+				"type":   "Ready",
+				"status": ready,
+			},
+		},
+	}
+
+	require.NoError(t, st.Create(ctx, status))
+}
+
+func TestControlPlaneHealthChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/readyz":
+			w.WriteHeader(http.StatusInternalServerError)
+
+			//nolint:errcheck
+			w.Write([]byte("[+]ping ok\n[-]etcd failed: reason withheld\n[+]log ok\nreadyz check failed\n"))
+		case "/livez":
+			//nolint:errcheck
+			w.Write([]byte("ok"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	for _, id := range []string{k8s.APIServerID, k8s.ControllerManagerID, k8s.SchedulerID} {
+		cfg := k8s.NewStaticPod(k8s.NamespaceName, id)
+		cfg.TypedSpec().Pod = map[string]any{
+			"spec": map[string]any{
+				"containers": []any{
+					map[string]any{
+						"image": "registry.k8s.io/" + id + ":v1.31.1",
+						"command": []string{
+							"/usr/local/bin/" + id,
+						},
+					},
+				},
+			},
+		}
+
+		require.NoError(t, resourceState.Create(ctx, cfg))
+	}
+
+	createStaticPodStatus(ctx, t, resourceState, k8s.APIServerID, "Running", "True")
+	createStaticPodStatus(ctx, t, resourceState, k8s.SchedulerID, "Running", "False")
+
+	path, err := upgrade.NewPath("1.31.1", "1.32.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, []string{"10.5.0.2"}, nil, t.Logf)
+	require.NoError(t, err)
+
+	checkErrors := checks.Run(ctx)
+
+	var removedItemsError upgrade.ComponentRemovedItemsError
+
+	require.ErrorAs(t, checkErrors, &removedItemsError)
+	require.Len(t, removedItemsError.Findings, 3)
+
+	for _, finding := range removedItemsError.Findings {
+		assert.Equal(t, "control-plane-health", finding.Check)
+	}
+
+	assert.Equal(t, "kube-controller-manager static pod is not running", removedItemsError.Findings[0].Message)
+	assert.Equal(t, "10.5.0.2", removedItemsError.Findings[0].Node)
+	assert.Equal(t, "kube-scheduler static pod is not ready (/readyz health check is failing)", removedItemsError.Findings[1].Message)
+	assert.Equal(t, "kube-apiserver /readyz check failed: etcd failed: reason withheld", removedItemsError.Findings[2].Message)
+}
+
+func TestDrainBlockerChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/apis/policy/v1/poddisruptionbudgets":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"policy/v1","kind":"PodDisruptionBudgetList","items":[
+{"metadata":{"name":"web","namespace":"default"},"spec":{"selector":{"matchLabels":{"app":"web"}}},
+ "status":{"disruptionsAllowed":0,"currentHealthy":2,"desiredHealthy":2,"expectedPods":2}},
+{"metadata":{"name":"db","namespace":"default"},"spec":{"selector":{"matchLabels":{"app":"db"}}},
+ "status":{"disruptionsAllowed":1,"currentHealthy":3,"desiredHealthy":2,"expectedPods":3}},
+{"metadata":{"name":"unused","namespace":"default"},"spec":{"selector":{"matchLabels":{"app":"unused"}}},
+ "status":{"disruptionsAllowed":0,"currentHealthy":0,"desiredHealthy":1,"expectedPods":0}}]}`))
+		case "/api/v1/namespaces/default/pods":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"v1","kind":"PodList","items":[
+{"metadata":{"name":"web-5d4f8-a","namespace":"default","ownerReferences":[{"apiVersion":"apps/v1","kind":"ReplicaSet","name":"web-5d4f8","uid":"1","controller":true}]}},
+{"metadata":{"name":"web-5d4f8-b","namespace":"default","ownerReferences":[{"apiVersion":"apps/v1","kind":"ReplicaSet","name":"web-5d4f8","uid":"1","controller":true}]}}]}`))
+		case "/apis/apps/v1/namespaces/default/replicasets/web-5d4f8":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"apps/v1","kind":"ReplicaSet","metadata":{"name":"web-5d4f8","namespace":"default",
+"ownerReferences":[{"apiVersion":"apps/v1","kind":"Deployment","name":"web","uid":"2","controller":true}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.31.1", "1.32.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, nil, t.Logf)
+	require.NoError(t, err)
+
+	report, err := checks.RunReport(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, "pdb-drain-blocker", report.Findings[0].Check)
+	assert.Equal(t, "PodDisruptionBudget default/web allows no disruptions, it blocks node drains (workloads: Deployment/web)", report.Findings[0].Message)
+	assert.Equal(t, upgrade.SeverityWarning, report.Findings[0].Severity)
+
+	// warnings don't block the upgrade by default
+	assert.NoError(t, checks.Run(ctx))
+}
+
+func TestWebhookChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/apis/admissionregistration.k8s.io/v1/validatingwebhookconfigurations":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"admissionregistration.k8s.io/v1","kind":"ValidatingWebhookConfigurationList","items":[
+{"metadata":{"name":"policy"},"webhooks":[
+ {"name":"pods.policy.example.com","clientConfig":{"service":{"namespace":"policy","name":"down"}},
+  "rules":[{"apiGroups":[""],"apiVersions":["v1"],"resources":["pods"],"operations":["CREATE"]}]},
+ {"name":"crds.policy.example.com","clientConfig":{"service":{"namespace":"policy","name":"down"}},"failurePolicy":"Ignore",
+  "rules":[{"apiGroups":["example.com"],"apiVersions":["v1"],"resources":["*"],"operations":["CREATE"]}]}]}]}`))
+		case "/apis/admissionregistration.k8s.io/v1/mutatingwebhookconfigurations":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"admissionregistration.k8s.io/v1","kind":"MutatingWebhookConfigurationList","items":[
+{"metadata":{"name":"inject"},"webhooks":[
+ {"name":"inject.example.com","clientConfig":{"service":{"namespace":"inject","name":"up"}},"failurePolicy":"Fail",
+  "rules":[{"apiGroups":["*"],"apiVersions":["*"],"resources":["*"],"operations":["*"]}]},
+ {"name":"external.example.com","clientConfig":{"url":"https://example.com/mutate"},"failurePolicy":"Ignore",
+  "rules":[{"apiGroups":[""],"apiVersions":["v1"],"resources":["pods"],"operations":["CREATE"]}]}]}]}`))
+		case "/apis/discovery.k8s.io/v1/namespaces/policy/endpointslices":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"discovery.k8s.io/v1","kind":"EndpointSliceList","items":[
+{"metadata":{"name":"down-1"},"addressType":"IPv4","endpoints":[{"addresses":["10.0.0.1"],"conditions":{"ready":false}}]}]}`))
+		case "/apis/discovery.k8s.io/v1/namespaces/inject/endpointslices":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"discovery.k8s.io/v1","kind":"EndpointSliceList","items":[
+{"metadata":{"name":"up-1"},"addressType":"IPv4","endpoints":[{"addresses":["10.0.0.2"],"conditions":{"ready":true}}]}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.31.1", "1.32.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, nil, t.Logf)
+	require.NoError(t, err)
+
+	report, err := checks.RunReport(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, report.Findings, 3)
+
+	for _, finding := range report.Findings {
+		assert.Equal(t, "admission-webhook", finding.Check)
+	}
+
+	assert.Equal(t, "ValidatingWebhookConfiguration policy webhook pods.policy.example.com: service policy/down has no ready endpoints", report.Findings[0].Message)
+	assert.Equal(t, upgrade.SeverityError, report.Findings[0].Severity)
+	assert.Equal(t, "ValidatingWebhookConfiguration policy webhook crds.policy.example.com: service policy/down has no ready endpoints", report.Findings[1].Message)
+	assert.Equal(t, upgrade.SeverityWarning, report.Findings[1].Severity)
+	assert.Equal(t, "MutatingWebhookConfiguration inject webhook inject.example.com has failure policy Fail for core API resources", report.Findings[2].Message)
+	assert.Equal(t, upgrade.SeverityWarning, report.Findings[2].Severity)
+
+	var removedItemsError upgrade.ComponentRemovedItemsError
+
+	require.ErrorAs(t, checks.Run(ctx), &removedItemsError)
+	assert.Len(t, removedItemsError.Findings, 1)
+}
+
+func TestChecksOptions(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	cfg := k8s.NewKubeletSpec(k8s.NamespaceName, k8s.KubeletID)
+	cfg.TypedSpec().Args = []string{
+		"--container-runtime=containerd",
+	}
+
+	require.NoError(t, resourceState.Create(ctx, cfg))
+
+	path, err := upgrade.NewPath("1.26.3", "1.27.0")
+	require.NoError(t, err)
+
+	nodesCheck := func(context.Context, upgrade.CheckEnv) ([]upgrade.Finding, error) {
+		return []upgrade.Finding{
+			{
+				Message: "node is not ready",
+			},
+		}, nil
+	}
+
+	for _, test := range []struct {
+		name string
+		opts []upgrade.ChecksOption
+
+		expectedCLIFlags int
+		expectedFindings int
+	}{
+		{
+			name:             "all",
+			expectedCLIFlags: 1,
+			expectedFindings: 1,
+		},
+		{
+			name:             "skip",
+			opts:             []upgrade.ChecksOption{upgrade.WithSkipChecks(upgrade.CheckRemovedComponentItems)},
+			expectedFindings: 1,
+		},
+		{
+			name:             "only",
+			opts:             []upgrade.ChecksOption{upgrade.WithOnlyChecks(upgrade.CheckRemovedComponentItems)},
+			expectedCLIFlags: 1,
+		},
+		{
+			name:             "only custom",
+			opts:             []upgrade.ChecksOption{upgrade.WithOnlyChecks("nodes", upgrade.CheckRemovedAPIResources)},
+			expectedFindings: 1,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			checks, err := upgrade.NewChecks(path, resourceState, nil, nil, []string{"10.5.0.3"}, t.Logf, test.opts...)
+			require.NoError(t, err)
+
+			checks.Register("nodes", nodesCheck)
+
+			var removedItemsError upgrade.ComponentRemovedItemsError
+
+			require.ErrorAs(t, checks.Run(ctx), &removedItemsError)
+
+			assert.Len(t, removedItemsError.CLIFlags, test.expectedCLIFlags)
+			assert.Len(t, removedItemsError.Findings, test.expectedFindings)
+		})
+	}
+}
+
+func TestCheckProgress(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.26.3", "1.27.0")
+	require.NoError(t, err)
+
+	eventCh := make(chan upgrade.CheckEvent, 100)
+
+	checks, err := upgrade.NewChecks(path, resourceState, nil, []string{"10.5.0.2"}, []string{"10.5.0.3"}, t.Logf,
+		upgrade.WithOnlyChecks(upgrade.CheckRemovedComponentItems, "nodes"),
+		upgrade.WithProgress(eventCh),
+	)
+	require.NoError(t, err)
+
+	checks.Register("nodes", func(context.Context, upgrade.CheckEnv) ([]upgrade.Finding, error) {
+		return []upgrade.Finding{{Message: "node is not ready"}}, nil
+	})
+
+	require.Error(t, checks.Run(ctx))
+
+	close(eventCh)
+
+	type event struct {
+		check    string
+		node     string
+		findings int
+		typ      upgrade.CheckEventType
+	}
+
+	var events []event
+
+	for e := range eventCh {
+		events = append(events, event{check: e.Check, node: e.Node, findings: e.Findings, typ: e.Type})
+
+		if e.Type == upgrade.CheckEventFinished {
+			assert.NotZero(t, e.Duration)
+		}
+	}
+
+	assert.Equal(t, []event{
+		{check: upgrade.CheckRemovedComponentItems, typ: upgrade.CheckEventStarted},
+		{check: upgrade.CheckRemovedComponentItems, node: "10.5.0.2", typ: upgrade.CheckEventNode},
+		{check: upgrade.CheckRemovedComponentItems, node: "10.5.0.3", typ: upgrade.CheckEventNode},
+		{check: upgrade.CheckRemovedComponentItems, typ: upgrade.CheckEventFinished},
+		{check: "nodes", typ: upgrade.CheckEventStarted},
+		{check: "nodes", findings: 1, typ: upgrade.CheckEventFinished},
+	}, events)
+}
+
+func TestRemovedAPIResourcesPagination(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	var requests []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/apis/policy/v1beta1":
+			//nolint:errcheck
+			w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"policy/v1beta1",
+"resources":[{"name":"podsecuritypolicies","singularName":"","namespaced":false,"kind":"PodSecurityPolicy","verbs":["list"]}]}`))
+		case "/apis/policy/v1beta1/podsecuritypolicies":
+			requests = append(requests, r.URL.RawQuery)
+
+			if r.URL.Query().Get("continue") == "" {
+				//nolint:errcheck
+				w.Write([]byte(`{"apiVersion":"meta.k8s.io/v1","kind":"PartialObjectMetadataList","metadata":{"continue":"next"},
+"items":[{"metadata":{"name":"privileged"}},{"metadata":{"name":"restricted"}}]}`))
+
+				return
+			}
+
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"meta.k8s.io/v1","kind":"PartialObjectMetadataList","metadata":{},
+"items":[{"metadata":{"name":"baseline"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.24.3", "1.25.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, nil, t.Logf,
+		upgrade.WithOnlyChecks(upgrade.CheckRemovedAPIResources),
+	)
+	require.NoError(t, err)
+
+	var removedItemsError upgrade.ComponentRemovedItemsError
+
+	require.ErrorAs(t, checks.Run(ctx), &removedItemsError)
+
+	assert.Equal(t, map[string]int{"podsecuritypolicies.v1beta1.policy": 3}, removedItemsError.APIResources)
+	assert.Equal(t, map[string][]string{"podsecuritypolicies.v1beta1.policy": {"privileged", "restricted", "baseline"}}, removedItemsError.APIResourceSamples)
+	assert.Equal(t, []string{"limit=500", "continue=next&limit=500"}, requests)
+}
+
+func TestDeprecatedAPIResourcesRemainingCount(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/apis/flowcontrol.apiserver.k8s.io/v1beta3":
+			//nolint:errcheck
+			w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"flowcontrol.apiserver.k8s.io/v1beta3",
+"resources":[{"name":"flowschemas","singularName":"","namespaced":false,"kind":"FlowSchema","verbs":["list"]}]}`))
+		case "/apis/flowcontrol.apiserver.k8s.io/v1beta3/flowschemas":
+			assert.Empty(t, r.URL.Query().Get("continue"))
+
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"meta.k8s.io/v1","kind":"PartialObjectMetadataList","metadata":{"continue":"next","remainingItemCount":1200},
+"items":[{"metadata":{"name":"a"}},{"metadata":{"name":"b"}},{"metadata":{"name":"c"}},{"metadata":{"name":"d"}},{"metadata":{"name":"e"}},{"metadata":{"name":"f"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.28.3", "1.29.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, nil, t.Logf)
+	require.NoError(t, err)
+
+	report, err := checks.RunReport(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, "flowschemas.v1beta3.flowcontrol.apiserver.k8s.io is deprecated in 1.29 and will be removed in a future release, 1206 object(s) found (e.g. a, b, c, d, e)",
+		report.Findings[0].Message)
+}
+
+func TestRemovedAPIResourcesNotServed(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	var listed []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/apis/flowcontrol.apiserver.k8s.io/v1beta3":
+			// the group version is served, but not all the resources
+			//nolint:errcheck
+			w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"flowcontrol.apiserver.k8s.io/v1beta3",
+"resources":[{"name":"flowschemas","singularName":"","namespaced":false,"kind":"FlowSchema","verbs":["list"]}]}`))
+		case "/apis/flowcontrol.apiserver.k8s.io/v1beta3/flowschemas":
+			listed = append(listed, r.URL.Path)
+
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"meta.k8s.io/v1","kind":"PartialObjectMetadataList","metadata":{},"items":[]}`))
+		case "/apis/networking.k8s.io/v1alpha1", "/apis/networking.k8s.io/v1alpha1/clustercidrs":
+			listed = append(listed, r.URL.Path)
+
+			fallthrough
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.28.3", "1.29.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, nil, t.Logf,
+		upgrade.WithSkipChecks(upgrade.CheckAppliedAPIVersions),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, checks.Run(ctx))
+
+	// only discovery is queried for clustercidrs, prioritylevelconfigurations are not listed
+	assert.Equal(t, []string{"/apis/networking.k8s.io/v1alpha1", "/apis/flowcontrol.apiserver.k8s.io/v1beta3/flowschemas"}, listed)
+}
+
+// slowState blocks reading the resources of the node until the context is canceled.
+type slowState struct {
+	state.State
+
+	node string
+}
+
+func (st slowState) Get(ctx context.Context, ptr resource.Pointer, opts ...state.GetOption) (resource.Resource, error) {
+	if md, _ := metadata.FromOutgoingContext(ctx); slices.Contains(md.Get("node"), st.node) {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+
+	return st.State.Get(ctx, ptr, opts...)
+}
+
+func TestCheckTimeouts(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	resourceState := slowState{
+		State: state.WrapCore(namespaced.NewState(inmem.Build)),
+		node:  "10.5.0.4",
+	}
+
+	cfg := k8s.NewKubeletSpec(k8s.NamespaceName, k8s.KubeletID)
+	cfg.TypedSpec().Args = []string{
+		"--container-runtime=containerd",
+	}
+
+	require.NoError(t, resourceState.Create(ctx, cfg))
+
+	path, err := upgrade.NewPath("1.26.3", "1.27.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, nil, nil, []string{"10.5.0.3", "10.5.0.4"}, t.Logf,
+		upgrade.WithOnlyChecks(upgrade.CheckRemovedComponentItems, "stuck"),
+		upgrade.WithNodeTimeout(100*time.Millisecond),
+		upgrade.WithCheckTimeout(200*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	checks.Register("stuck", func(ctx context.Context, _ upgrade.CheckEnv) ([]upgrade.Finding, error) {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	})
+
+	var removedItemsError upgrade.ComponentRemovedItemsError
+
+	require.ErrorAs(t, checks.Run(ctx), &removedItemsError)
+
+	// the other node is still checked
+	assert.Equal(t, []upgrade.ComponentItem{
+		{Node: "10.5.0.3", Component: "kubelet", Value: "container-runtime"},
+	}, removedItemsError.CLIFlags)
+
+	require.Len(t, removedItemsError.Findings, 2)
+
+	assert.Equal(t, upgrade.CheckRemovedComponentItems, removedItemsError.Findings[0].Check)
+	assert.Equal(t, "10.5.0.4", removedItemsError.Findings[0].Node)
+	assert.Equal(t, "node could not be checked in 100ms: context deadline exceeded", removedItemsError.Findings[0].Message)
+
+	assert.Equal(t, "stuck", removedItemsError.Findings[1].Check)
+	assert.Equal(t, `check did not complete in 200ms: error running check "stuck": context deadline exceeded`, removedItemsError.Findings[1].Message)
+}
+
+func TestRollbackChecks(t *testing.T) {
+	for _, test := range []struct {
+		from, to         string
+		expectedFindings int
+	}{
+		{from: "1.31.0", to: "1.32.0"},
+		{from: "1.32.3", to: "1.32.1"},
+		{from: "1.32.3", to: "1.31.5", expectedFindings: 3},
+	} {
+		t.Run(test.from+"->"+test.to, func(t *testing.T) {
+			path, err := upgrade.NewPath(test.from, test.to)
+			require.NoError(t, err)
+
+			var report upgrade.ComponentRemovedItemsError
+
+			report.PopulateRollback(path)
+
+			require.Len(t, report.Findings, test.expectedFindings)
+
+			for _, finding := range report.Findings {
+				assert.Equal(t, upgrade.CheckRollback, finding.Check)
+				assert.Equal(t, upgrade.SeverityWarning, finding.Severity)
+				assert.Contains(t, finding.Message, "1.32")
+				assert.Contains(t, finding.Remediation, "1.31")
+			}
+		})
+	}
+}
+
+func TestInTreeCloudProviderChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	staticPod := k8s.NewStaticPod(k8s.NamespaceName, k8s.ControllerManagerID)
+	staticPod.TypedSpec().Pod = map[string]any{
+		"spec": map[string]any{
+			"containers": []any{
+				map[string]any{
+					"command": []string{
+						"/usr/local/bin/kube-controller-manager",
+						"--cloud-provider=aws",
+						"--cloud-config=/etc/kubernetes/cloud.conf",
+					},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, resourceState.Create(ctx, staticPod))
+
+	kubeletSpec := k8s.NewKubeletSpec(k8s.NamespaceName, k8s.KubeletID)
+	kubeletSpec.TypedSpec().Args = []string{
+		"--cloud-provider=external",
+	}
+
+	require.NoError(t, resourceState.Create(ctx, kubeletSpec))
+
+	for _, test := range []struct {
+		from, to         string
+		expectedFindings []upgrade.Finding
+	}{
+		{
+			from:             "1.29.3",
+			to:               "1.30.0",
+			expectedFindings: []upgrade.Finding{},
+		},
+		{
+			from: "1.30.3",
+			to:   "1.31.0",
+			expectedFindings: []upgrade.Finding{
+				{
+					Check:       upgrade.CheckInTreeCloudProvider,
+					Node:        "10.5.0.2",
+					Component:   k8s.ControllerManagerID,
+					Message:     `in-tree cloud provider "aws" is removed in 1.31`,
+					Remediation: "deploy the external cloud controller manager and set cloud-provider to external in cluster.controllerManager.extraArgs",
+					DocURL:      "https://kubernetes.io/docs/tasks/administer-cluster/running-cloud-controller/",
+					Severity:    upgrade.SeverityError,
+					Item:        "cloud-provider",
+					FlagValue:   "aws",
+				},
+				{
+					Check:       upgrade.CheckInTreeCloudProvider,
+					Node:        "10.5.0.2",
+					Component:   k8s.ControllerManagerID,
+					Message:     "--cloud-config=/etc/kubernetes/cloud.conf is only used by in-tree cloud providers",
+					Remediation: "remove --cloud-config from cluster.controllerManager.extraArgs, and configure the external cloud controller manager instead",
+					DocURL:      "https://kubernetes.io/docs/tasks/administer-cluster/running-cloud-controller/",
+					Severity:    upgrade.SeverityWarning,
+					Item:        "cloud-config",
+					FlagValue:   "/etc/kubernetes/cloud.conf",
+				},
+			},
+		},
+	} {
+		t.Run(test.from+"->"+test.to, func(t *testing.T) {
+			path, err := upgrade.NewPath(test.from, test.to)
+			require.NoError(t, err)
+
+			checks, err := upgrade.NewChecks(path, resourceState, nil, []string{"10.5.0.2"}, nil, t.Logf,
+				upgrade.WithOnlyChecks(upgrade.CheckInTreeCloudProvider),
+			)
+			require.NoError(t, err)
+
+			report, err := checks.RunReport(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedFindings, report.Findings)
+		})
+	}
+}
+
+func TestNodeOSChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/nodes/worker-1":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"v1","kind":"Node","metadata":{"name":"worker-1"},
+"status":{"nodeInfo":{"kernelVersion":"5.4.0-100-generic","operatingSystem":"linux"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	nodename := k8s.NewNodename(k8s.NamespaceName, k8s.NodenameID)
+	nodename.TypedSpec().Nodename = "worker-1"
+
+	require.NoError(t, resourceState.Create(ctx, nodename))
+
+	for _, test := range []struct {
+		from, to         string
+		expectedFindings []upgrade.Finding
+	}{
+		{
+			from:             "1.29.3",
+			to:               "1.30.0",
+			expectedFindings: []upgrade.Finding{},
+		},
+		{
+			from: "1.30.3",
+			to:   "1.31.0",
+			expectedFindings: []upgrade.Finding{
+				{
+					Check:     upgrade.CheckNodeOS,
+					Node:      "10.5.0.3",
+					Component: k8s.KubeletID,
+					Message: "node kernel 5.4.0-100-generic is older than 5.8.0: cgroup v1 support is in maintenance mode since Kubernetes 1.31, " +
+						"cgroup v2 requires Linux kernel 5.8 or later",
+					Remediation: "upgrade the node operating system to a version with Linux kernel 5.8.0 or later before upgrading kubelet",
+					DocURL:      "https://kubernetes.io/docs/concepts/architecture/cgroups/",
+					Severity:    upgrade.SeverityWarning,
+				},
+			},
+		},
+	} {
+		t.Run(test.from+"->"+test.to, func(t *testing.T) {
+			path, err := upgrade.NewPath(test.from, test.to)
+			require.NoError(t, err)
+
+			checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, []string{"10.5.0.3"}, t.Logf,
+				upgrade.WithOnlyChecks(upgrade.CheckNodeOS),
+			)
+			require.NoError(t, err)
+
+			report, err := checks.RunReport(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedFindings, report.Findings)
+		})
+	}
+}
+
+func TestRemovedVolumePluginChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/persistentvolumes":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"v1","kind":"PersistentVolumeList","items":[
+{"metadata":{"name":"pv-ebs"},"spec":{"awsElasticBlockStore":{"volumeID":"vol-1"},"claimRef":{"namespace":"db","name":"data"}}},
+{"metadata":{"name":"pv-gce"},"spec":{"gcePersistentDisk":{"pdName":"disk-1"}}},
+{"metadata":{"name":"pv-csi"},"spec":{"csi":{"driver":"ebs.csi.aws.com","volumeHandle":"vol-2"}}}]}`))
+		case "/api/v1/pods":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"v1","kind":"PodList","items":[
+{"metadata":{"name":"web-1","namespace":"app","ownerReferences":[{"apiVersion":"apps/v1","kind":"ReplicaSet","name":"web-abc","uid":"1","controller":true}]},
+"spec":{"volumes":[{"name":"shared","azureDisk":{"diskName":"disk-2","diskURI":"uri"}},{"name":"old","glusterfs":{"endpoints":"gluster","path":"vol"}},{"name":"tmp","emptyDir":{}}]}},
+{"metadata":{"name":"web-2","namespace":"app","ownerReferences":[{"apiVersion":"apps/v1","kind":"ReplicaSet","name":"web-abc","uid":"1","controller":true}]},
+"spec":{"volumes":[{"name":"shared","azureDisk":{"diskName":"disk-2","diskURI":"uri"}}]}},
+{"metadata":{"name":"cache-1","namespace":"app","ownerReferences":[{"apiVersion":"apps/v1","kind":"ReplicaSet","name":"cache-abc","uid":"3","controller":true}]},
+"spec":{"volumes":[{"name":"tmp","emptyDir":{}}]}},
+{"metadata":{"name":"db-1","namespace":"db","ownerReferences":[{"apiVersion":"apps/v1","kind":"ReplicaSet","name":"db-abc","uid":"4","controller":true}]},
+"spec":{"volumes":[{"name":"data","azureDisk":{"diskName":"disk-3","diskURI":"uri"}}]}}]}`))
+		case "/apis/apps/v1/namespaces/app/replicasets/web-abc":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"apps/v1","kind":"ReplicaSet","metadata":{"name":"web-abc","namespace":"app",
+"ownerReferences":[{"apiVersion":"apps/v1","kind":"Deployment","name":"web","uid":"2","controller":true}]}}`))
+		case "/apis/apps/v1/namespaces/app/replicasets/cache-abc":
+			// the workload of the pods without removed volume plugins is not resolved
+			t.Errorf("unexpected request %s", r.URL.Path)
+
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/apis/apps/v1/namespaces/db/replicasets/db-abc":
+			w.WriteHeader(http.StatusForbidden)
+
+			//nolint:errcheck
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.26.3", "1.27.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, nil, t.Logf,
+		upgrade.WithOnlyChecks(upgrade.CheckRemovedVolumePlugins),
+	)
+	require.NoError(t, err)
+
+	report, err := checks.RunReport(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []upgrade.Finding{
+		{
+			Check:       upgrade.CheckRemovedVolumePlugins,
+			Message:     "PersistentVolume pv-ebs (claim db/data) uses in-tree volume plugin awsElasticBlockStore removed in 1.27",
+			Remediation: "install the ebs.csi.aws.com CSI driver before upgrading, the volumes are migrated to it",
+			DocURL:      "https://kubernetes.io/docs/concepts/storage/volumes/#csi-migration",
+			Severity:    upgrade.SeverityError,
+			Item:        "awsElasticBlockStore",
+		},
+		{
+			Check:       upgrade.CheckRemovedVolumePlugins,
+			Message:     "Deployment/web in namespace app uses in-tree volume plugin azureDisk removed in 1.27",
+			Remediation: "install the disk.csi.azure.com CSI driver before upgrading, the volumes are migrated to it",
+			DocURL:      "https://kubernetes.io/docs/concepts/storage/volumes/#csi-migration",
+			Severity:    upgrade.SeverityError,
+			Item:        "azureDisk",
+		},
+		{
+			Check:       upgrade.CheckRemovedVolumePlugins,
+			Message:     "Pod/db-1 in namespace db uses in-tree volume plugin azureDisk removed in 1.27",
+			Remediation: "install the disk.csi.azure.com CSI driver before upgrading, the volumes are migrated to it",
+			DocURL:      "https://kubernetes.io/docs/concepts/storage/volumes/#csi-migration",
+			Severity:    upgrade.SeverityError,
+			Item:        "azureDisk",
+		},
+	}, report.Findings)
+}
+
+func TestAppliedAPIVersionChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/apis/policy/v1beta1":
+			//nolint:errcheck
+			w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"policy/v1beta1",
+"resources":[{"name":"podsecuritypolicies","singularName":"","namespaced":false,"kind":"PodSecurityPolicy","verbs":["list"]}]}`))
+		case "/apis/policy/v1beta1/podsecuritypolicies":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"meta.k8s.io/v1","kind":"PartialObjectMetadataList","metadata":{},"items":[
+{"metadata":{"name":"restricted","managedFields":[{"manager":"helm","operation":"Update","apiVersion":"policy/v1beta1"}]}},
+{"metadata":{"name":"privileged","annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{\"apiVersion\":\"policy/v1beta1\",\"kind\":\"PodSecurityPolicy\"}"}}},
+{"metadata":{"name":"baseline","managedFields":[{"manager":"kube-controller-manager","operation":"Update","apiVersion":"policy/v1"}]}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	path, err := upgrade.NewPath("1.24.3", "1.25.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, nil, t.Logf,
+		upgrade.WithOnlyChecks(upgrade.CheckAppliedAPIVersions),
+	)
+	require.NoError(t, err)
+
+	report, err := checks.RunReport(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []upgrade.Finding{
+		{
+			Check: upgrade.CheckAppliedAPIVersions,
+			Message: "2 object(s) of podsecuritypolicies.policy were applied using policy/v1beta1 by helm, kubectl (last-applied-configuration) " +
+				"(e.g. restricted, privileged)",
+			Remediation: "update the manifests applied by helm, kubectl (last-applied-configuration) to a supported API version of podsecuritypolicies.policy",
+			DocURL:      "https://kubernetes.io/docs/reference/using-api/deprecation-guide/",
+			Severity:    upgrade.SeverityWarning,
+			Item:        "podsecuritypolicies.v1beta1.policy",
+		},
+	}, report.Findings)
+}
+
+func TestChangedAdmissionPluginChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	for _, test := range []struct {
+		name             string
+		cliFlags         []string
+		expectedFindings []upgrade.Finding
+	}{
+		{
+			name: "default",
+			cliFlags: []string{
+				"/usr/local/bin/kube-apiserver",
+				"--enable-admission-plugins=NodeRestriction",
+			},
+			expectedFindings: []upgrade.Finding{
+				{
+					Check:       "changed-admission-plugin",
+					Node:        "10.5.0.2",
+					Component:   k8s.APIServerID,
+					Message:     "admission plugin ValidatingAdmissionPolicy is enabled by default, existing ValidatingAdmissionPolicyBindings are enforced",
+					Remediation: "review the workloads affected by ValidatingAdmissionPolicy, or disable it with --disable-admission-plugins in cluster.apiServer.extraArgs",
+					DocURL:      "https://kubernetes.io/docs/reference/access-authn-authz/admission-controllers/",
+					Severity:    upgrade.SeverityWarning,
+					Item:        "ValidatingAdmissionPolicy",
+				},
+			},
+		},
+		{
+			name: "disabled",
+			cliFlags: []string{
+				"/usr/local/bin/kube-apiserver",
+				"--enable-admission-plugins=NodeRestriction",
+				"--disable-admission-plugins=ValidatingAdmissionPolicy",
+			},
+			expectedFindings: []upgrade.Finding{},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+			cfg := k8s.NewStaticPod(k8s.NamespaceName, k8s.APIServerID)
+			cfg.TypedSpec().Pod = map[string]any{
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{
+							"command": test.cliFlags,
+						},
+					},
+				},
+			}
+
+			require.NoError(t, resourceState.Create(ctx, cfg))
+
+			path, err := upgrade.NewPath("1.29.3", "1.30.0")
+			require.NoError(t, err)
+
+			checks, err := upgrade.NewChecks(path, resourceState, nil, []string{"10.5.0.2"}, nil, t.Logf,
+				upgrade.WithOnlyChecks(upgrade.CheckRemovedComponentItems),
+			)
+			require.NoError(t, err)
+
+			report, err := checks.RunReport(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedFindings, report.Findings)
+		})
+	}
+}
+
+func TestKubeletCredentialProviderChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	cfg := k8s.NewKubeletSpec(k8s.NamespaceName, k8s.KubeletID)
+	cfg.TypedSpec().Args = []string{
+		"--image-credential-provider-config=/etc/kubernetes/credential-providers.yaml",
+	}
+	cfg.TypedSpec().CredentialProviderConfig = map[string]any{
+		"apiVersion": "kubelet.config.k8s.io/v1alpha1",
+		"kind":       "CredentialProviderConfig",
+		"providers": []any{
+			map[string]any{
+				"name":                 "ecr-credential-provider",
+				"apiVersion":           "credentialprovider.kubelet.k8s.io/v1alpha1",
+				"defaultCacheDuration": "12h",
+				"matchImages":          []any{"*.dkr.ecr.*.amazonaws.com"},
+			},
+			map[string]any{
+				"name":       "gcr-credential-provider",
+				"apiVersion": "credentialprovider.kubelet.k8s.io/v1",
+			},
+		},
+	}
+
+	require.NoError(t, resourceState.Create(ctx, cfg))
+
+	path, err := upgrade.NewPath("1.25.3", "1.26.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, nil, nil, []string{"10.5.0.3"}, t.Logf,
+		upgrade.WithOnlyChecks(upgrade.CheckRemovedComponentItems),
+	)
+	require.NoError(t, err)
+
+	report, err := checks.RunReport(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []upgrade.Finding{
+		{
+			Check:       "kubelet-credential-provider",
+			Node:        "10.5.0.3",
+			Component:   k8s.KubeletID,
+			Message:     "--image-credential-provider-config is set without --image-credential-provider-bin-dir",
+			Remediation: "set both --image-credential-provider-config and --image-credential-provider-bin-dir in machine.kubelet.extraArgs, or remove them",
+			DocURL:      "https://kubernetes.io/docs/tasks/administer-cluster/kubelet-credential-provider/",
+			Severity:    upgrade.SeverityError,
+			Item:        "image-credential-provider-config",
+		},
+		{
+			Check:       "kubelet-credential-provider",
+			Node:        "10.5.0.3",
+			Component:   k8s.KubeletID,
+			Message:     "kubelet credential provider configuration uses removed API version credentialprovider.kubelet.k8s.io/v1alpha1",
+			Remediation: "update the API versions in machine.kubelet.credentialProviderConfig to v1",
+			DocURL:      "https://kubernetes.io/docs/tasks/administer-cluster/kubelet-credential-provider/",
+			Severity:    upgrade.SeverityError,
+			Item:        "credentialprovider.kubelet.k8s.io/v1alpha1",
+		},
+		{
+			Check:       "kubelet-credential-provider",
+			Node:        "10.5.0.3",
+			Component:   k8s.KubeletID,
+			Message:     "kubelet credential provider configuration uses removed API version kubelet.config.k8s.io/v1alpha1",
+			Remediation: "update the API versions in machine.kubelet.credentialProviderConfig to v1",
+			DocURL:      "https://kubernetes.io/docs/tasks/administer-cluster/kubelet-credential-provider/",
+			Severity:    upgrade.SeverityError,
+			Item:        "kubelet.config.k8s.io/v1alpha1",
+		},
+	}, report.Findings)
+}
+
+func TestStaticPodDriftChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/namespaces/kube-system/pods/kube-apiserver-controlplane-1":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"kube-apiserver-controlplane-1","namespace":"kube-system"},
+"spec":{"containers":[{"name":"kube-apiserver","image":"registry.k8s.io/kube-apiserver:v1.30.0","command":["/usr/local/bin/kube-apiserver"]}]}}`))
+		case "/api/v1/namespaces/kube-system/pods/kube-controller-manager-controlplane-1":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"kube-controller-manager-controlplane-1","namespace":"kube-system"},
+"spec":{"containers":[{"name":"kube-controller-manager","image":"registry.k8s.io/kube-controller-manager:v1.30.1",
+"command":["/usr/local/bin/kube-controller-manager","--bind-address=127.0.0.1"]}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	nodename := k8s.NewNodename(k8s.NamespaceName, k8s.NodenameID)
+	nodename.TypedSpec().Nodename = "controlplane-1"
+
+	require.NoError(t, resourceState.Create(ctx, nodename))
+
+	for _, id := range []string{k8s.APIServerID, k8s.ControllerManagerID, k8s.SchedulerID} {
+		cfg := k8s.NewStaticPod(k8s.NamespaceName, id)
+		cfg.TypedSpec().Pod = map[string]any{
+			"spec": map[string]any{
+				"containers": []any{
+					map[string]any{
+						"name":    id,
+						"image":   "registry.k8s.io/" + id + ":v1.30.1",
+						"command": []string{"/usr/local/bin/" + id},
+					},
+				},
+			},
+		}
+
+		require.NoError(t, resourceState.Create(ctx, cfg))
+	}
+
+	path, err := upgrade.NewPath("1.30.1", "1.31.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, []string{"10.5.0.2"}, nil, t.Logf,
+		upgrade.WithOnlyChecks(upgrade.CheckStaticPodDrift),
+	)
+	require.NoError(t, err)
+
+	report, err := checks.RunReport(ctx)
+	require.NoError(t, err)
+
+	messages := xslices.Map(report.Findings, func(f upgrade.Finding) string { return f.Component + ": " + f.Message })
+
+	assert.Equal(t, []string{
+		"kube-apiserver: container kube-apiserver runs image registry.k8s.io/kube-apiserver:v1.30.0, declared registry.k8s.io/kube-apiserver:v1.30.1",
+		"kube-controller-manager: container kube-controller-manager runs with outdated command line",
+		"kube-scheduler: mirror pod kube-system/kube-scheduler-controlplane-1 is not found",
+	}, messages)
+}
+
+func TestGroupedFindings(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	cfg := k8s.NewKubeletSpec(k8s.NamespaceName, k8s.KubeletID)
+	cfg.TypedSpec().Args = []string{
+		"--container-runtime=containerd",
+	}
+
+	require.NoError(t, resourceState.Create(ctx, cfg))
+
+	path, err := upgrade.NewPath("1.26.3", "1.27.0")
+	require.NoError(t, err)
+
+	workers := []string{"10.5.0.3", "10.5.0.4", "10.5.0.5", "10.5.0.6", "10.5.0.7"}
+
+	checks, err := upgrade.NewChecks(path, resourceState, nil, nil, workers, t.Logf,
+		upgrade.WithOnlyChecks(upgrade.CheckRemovedComponentItems),
+	)
+	require.NoError(t, err)
+
+	var removedItemsError upgrade.ComponentRemovedItemsError
+
+	require.ErrorAs(t, checks.Run(ctx), &removedItemsError)
+	require.Len(t, removedItemsError.CLIFlags, 5)
+
+	grouped := removedItemsError.Report().Grouped()
+	require.Len(t, grouped, 1)
+
+	assert.Equal(t, workers, grouped[0].Nodes)
+	assert.Empty(t, grouped[0].Node)
+	assert.Equal(t, "container-runtime", grouped[0].Item)
+
+	summary := removedItemsError.Error()
+
+	// the removed flag and the suggested fix
+	assert.Equal(t, 2, strings.Count(summary, "10.5.0.3, 10.5.0.4, 10.5.0.5 (+2 more)"), summary)
+	assert.NotContains(t, summary, "10.5.0.7")
+
+	details := removedItemsError.Details()
+
+	for _, node := range workers {
+		assert.Equal(t, 2, strings.Count(details, node), details)
+	}
+}
+
+func TestOfflineChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	path, err := upgrade.NewPath("1.24.3", "1.25.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewOfflineChecks(path, []upgrade.NodeConfig{
+		{
+			Node:         "controlplane-1",
+			ControlPlane: true,
+			Args: map[string][]string{
+				k8s.APIServerID: {
+					"--enable-admission-plugins=NodeRestriction,PodSecurityPolicy",
+				},
+				k8s.ControllerManagerID: {
+					"--bind-address=0.0.0.0",
+				},
+			},
+		},
+		{
+			Node: "worker-1",
+			Args: map[string][]string{
+				k8s.KubeletID: {
+					"--feature-gates=CSIVolumeFSGroupPolicy=true",
+				},
+			},
+		},
+		{
+			Node: "worker-2",
+		},
+	}, t.Logf)
+	require.NoError(t, err)
+
+	var removedItemsError upgrade.ComponentRemovedItemsError
+
+	require.ErrorAs(t, checks.Run(ctx), &removedItemsError)
+
+	assert.Equal(t, []upgrade.ComponentItem{
+		{
+			Node:      "controlplane-1",
+			Component: k8s.APIServerID,
+			Value:     "PodSecurityPolicy",
+			FlagValue: "NodeRestriction,PodSecurityPolicy",
+		},
+	}, removedItemsError.AdmissionFlags)
+
+	assert.Equal(t, []upgrade.ComponentItem{
+		{
+			Node:      "worker-1",
+			Component: k8s.KubeletID,
+			Value:     "CSIVolumeFSGroupPolicy",
+			FlagValue: "CSIVolumeFSGroupPolicy=true",
+		},
+	}, removedItemsError.FeatureGates)
+
+	// the control plane health is not checked
+	assert.Empty(t, removedItemsError.Findings)
+
+	_, err = upgrade.NewOfflineChecks(path, []upgrade.NodeConfig{{Node: "worker-1"}, {Node: "worker-1"}}, t.Logf)
+	require.EqualError(t, err, `duplicate node "worker-1"`)
+}
+
+func TestEncryptionConfigChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	encryptionConfig := map[string]any{
+		"apiVersion": "apiserver.config.k8s.io/v1",
+		"kind":       "EncryptionConfiguration",
+		"resources": []any{
+			map[string]any{
+				"resources": []any{"secrets"},
+				"providers": []any{
+					map[string]any{"kms": map[string]any{"name": "vault", "endpoint": "unix:///tmp/vault.sock"}},
+					map[string]any{"kms": map[string]any{"name": "cloud", "apiVersion": "v2", "endpoint": "unix:///tmp/cloud.sock"}},
+					map[string]any{"identity": map[string]any{}},
+				},
+			},
+		},
+	}
+
+	for _, test := range []struct {
+		name             string
+		from, to         string
+		args             []string
+		encryptionConfig map[string]any
+		expectedMessages []string
+	}{
+		{
+			name:             "deprecated",
+			from:             "1.27.3",
+			to:               "1.28.0",
+			args:             []string{"--encryption-provider-config=/var/lib/encryption.yaml"},
+			encryptionConfig: encryptionConfig,
+			expectedMessages: []string{`KMS v1 provider "vault" is deprecated since 1.28`},
+		},
+		{
+			name:             "disabled",
+			from:             "1.28.3",
+			to:               "1.29.0",
+			args:             []string{"--encryption-provider-config=/var/lib/encryption.yaml"},
+			encryptionConfig: encryptionConfig,
+			expectedMessages: []string{`KMS v1 provider "vault" is disabled by default since 1.29`},
+		},
+		{
+			name:             "enabled with feature gate",
+			from:             "1.28.3",
+			to:               "1.29.0",
+			args:             []string{"--encryption-provider-config=/var/lib/encryption.yaml", "--feature-gates=KMSv1=true"},
+			encryptionConfig: encryptionConfig,
+			expectedMessages: []string{`KMS v1 provider "vault" is deprecated since 1.28`},
+		},
+		{
+			name:             "custom config",
+			from:             "1.28.3",
+			to:               "1.29.0",
+			args:             []string{"--encryption-provider-config=/var/lib/encryption.yaml"},
+			expectedMessages: []string{"custom encryption provider configuration /var/lib/encryption.yaml is used, KMS v1 providers are disabled by default since 1.29"},
+		},
+		{
+			name: "talos config",
+			from: "1.28.3",
+			to:   "1.29.0",
+			args: []string{"--encryption-provider-config=/system/secrets/kubernetes/kube-apiserver/encryptionconfig.yaml"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			path, err := upgrade.NewPath(test.from, test.to)
+			require.NoError(t, err)
+
+			checks, err := upgrade.NewOfflineChecks(path, []upgrade.NodeConfig{
+				{
+					Node:             "controlplane-1",
+					ControlPlane:     true,
+					Args:             map[string][]string{k8s.APIServerID: test.args},
+					EncryptionConfig: test.encryptionConfig,
+				},
+			}, t.Logf, upgrade.WithOnlyChecks(upgrade.CheckRemovedComponentItems))
+			require.NoError(t, err)
+
+			report, err := checks.RunReport(ctx)
+			require.NoError(t, err)
+
+			var messages []string
+
+			for _, finding := range report.Findings {
+				if finding.Check == "encryption-provider" {
+					messages = append(messages, finding.Message)
+				}
+			}
+
+			assert.Equal(t, test.expectedMessages, messages)
+		})
+	}
+}
+
+func TestLockedFeatureGateChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	path, err := upgrade.NewPath("1.28.3", "1.29.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewOfflineChecks(path, []upgrade.NodeConfig{
+		{
+			Node:         "controlplane-1",
+			ControlPlane: true,
+			Args: map[string][]string{
+				k8s.APIServerID: {"--feature-gates=KMSv2=false,APIListChunking=true"},
+				k8s.KubeletID:   {"--feature-gates=JobReadyPods=true"},
+			},
+			KubeletConfig: map[string]any{
+				"featureGates": map[string]any{
+					"ReadWriteOncePod": false,
+				},
+			},
+		},
+	}, t.Logf, upgrade.WithOnlyChecks(upgrade.CheckRemovedComponentItems))
+	require.NoError(t, err)
+
+	report, err := checks.RunReport(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []upgrade.Finding{
+		{
+			Check:       "locked-feature-gate",
+			Node:        "controlplane-1",
+			Component:   k8s.APIServerID,
+			Message:     "feature gate KMSv2 is set to false, but it is locked to true in the upgrade version",
+			Remediation: "remove KMSv2 from --feature-gates in cluster.apiServer.extraArgs",
+			DocURL:      "https://kubernetes.io/docs/reference/command-line-tools-reference/feature-gates/",
+			Severity:    upgrade.SeverityError,
+			Item:        "KMSv2",
+			FlagValue:   "KMSv2=false,APIListChunking=true",
+		},
+		{
+			Check:       "locked-feature-gate",
+			Node:        "controlplane-1",
+			Component:   k8s.KubeletID,
+			Message:     "feature gate ReadWriteOncePod is set to false in the kubelet configuration, but it is locked to true in the upgrade version",
+			Remediation: "remove ReadWriteOncePod from machine.kubelet.extraConfig.featureGates",
+			DocURL:      "https://kubernetes.io/docs/reference/command-line-tools-reference/feature-gates/",
+			Severity:    upgrade.SeverityError,
+			Item:        "ReadWriteOncePod",
+		},
+	}, report.Findings)
+
+	patches := upgrade.GenerateRemediationPatches(report.Findings)
+	require.Len(t, patches, 1)
+
+	assert.Equal(t, map[string]any{
+		"cluster": map[string]any{
+			"apiServer": map[string]any{
+				"extraArgs": map[string]any{
+					"feature-gates": "APIListChunking=true",
+				},
+			},
+		},
+		"machine": map[string]any{
+			"kubelet": map[string]any{
+				"extraConfig": map[string]any{
+					"featureGates": map[string]any{
+						"ReadWriteOncePod": map[string]any{"$patch": "delete"},
+					},
+				},
+			},
+		},
+	}, patches[0].Patch)
+}
+
+func TestNodeReadinessChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/nodes":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"v1","kind":"NodeList","items":[
+{"metadata":{"name":"cp-1"},"status":{"conditions":[{"type":"Ready","status":"True"}]}},
+{"metadata":{"name":"worker-1"},"status":{"conditions":[{"type":"Ready","status":"False","message":"container runtime is down"}]}},
+{"metadata":{"name":"worker-2"},"spec":{"unschedulable":true},
+ "status":{"conditions":[{"type":"Ready","status":"True"},{"type":"DiskPressure","status":"True"},{"type":"MemoryPressure","status":"False"}]}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	memory := perf.NewMemory()
+	memory.TypedSpec().MemTotal = 2 * 1024 * 1024
+	memory.TypedSpec().MemAvailable = 256 * 1024
+
+	require.NoError(t, resourceState.Create(ctx, memory))
+
+	path, err := upgrade.NewPath("1.30.3", "1.31.0")
+	require.NoError(t, err)
+
+	checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, []string{"10.5.0.2"}, nil, t.Logf,
+		upgrade.WithOnlyChecks(upgrade.CheckNodeReadiness, upgrade.CheckNodeCapacity),
+	)
+	require.NoError(t, err)
+
+	report, err := checks.RunReport(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []upgrade.Finding{
+		{
+			Check:       upgrade.CheckNodeReadiness,
+			Node:        "worker-1",
+			Component:   k8s.KubeletID,
+			Message:     "node worker-1 is not Ready: container runtime is down",
+			Remediation: "fix the node or remove it from the cluster before upgrading",
+			Severity:    upgrade.SeverityError,
+		},
+		{
+			Check:       upgrade.CheckNodeReadiness,
+			Node:        "worker-2",
+			Component:   k8s.KubeletID,
+			Message:     "node worker-2 reports DiskPressure, new component images might fail to be pulled or started",
+			Remediation: "free up the node resources before upgrading",
+			Severity:    upgrade.SeverityError,
+		},
+		{
+			Check:       upgrade.CheckNodeReadiness,
+			Node:        "worker-2",
+			Component:   k8s.KubeletID,
+			Message:     "node worker-2 is cordoned",
+			Remediation: "uncordon the node with `kubectl uncordon worker-2` if it is not cordoned intentionally",
+			Severity:    upgrade.SeverityWarning,
+		},
+		{
+			Check:       upgrade.CheckNodeCapacity,
+			Node:        "10.5.0.2",
+			Message:     "control plane node has 256 MiB of memory available, at least 512 MiB is recommended for the upgrade",
+			Remediation: "free up the node memory or add more memory to the node before upgrading",
+			Severity:    upgrade.SeverityWarning,
+		},
+	}, report.Findings)
+}
+
+func TestNodeRuntimeChecks(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer ctxCancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/nodes":
+			//nolint:errcheck
+			w.Write([]byte(`{"apiVersion":"v1","kind":"NodeList","items":[
+{"metadata":{"name":"cp-1"},"status":{"nodeInfo":{"osImage":"Talos (v1.9.2)","containerRuntimeVersion":"containerd://2.0.2"}}},
+{"metadata":{"name":"worker-1"},"status":{"nodeInfo":{"osImage":"Talos (v1.8.3)","containerRuntimeVersion":"containerd://1.7.23"}}},
+{"metadata":{"name":"worker-2"},"status":{"nodeInfo":{"osImage":"Ubuntu 20.04.6 LTS","containerRuntimeVersion":"containerd://1.5.9-0ubuntu1"}}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resourceState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	for _, test := range []struct {
+		from, to         string
+		expectedMessages []string
+	}{
+		{
+			from: "1.30.3",
+			to:   "1.31.0",
+			expectedMessages: []string{
+				"node container runtime containerd://1.5.9-0ubuntu1 is older than 1.6.0: " +
+					"kubelet requires CRI v1 API since Kubernetes 1.26, which is supported by containerd 1.6 or later",
+			},
+		},
+		{
+			from: "1.31.3",
+			to:   "1.32.0",
+			expectedMessages: []string{
+				"Talos (v1.8.3) supports Kubernetes up to 1.31",
+				"node container runtime containerd://1.5.9-0ubuntu1 is older than 1.6.0: " +
+					"kubelet requires CRI v1 API since Kubernetes 1.26, which is supported by containerd 1.6 or later",
+			},
+		},
+	} {
+		t.Run(test.from+"->"+test.to, func(t *testing.T) {
+			path, err := upgrade.NewPath(test.from, test.to)
+			require.NoError(t, err)
+
+			checks, err := upgrade.NewChecks(path, resourceState, &rest.Config{Host: srv.URL}, nil, nil, t.Logf,
+				upgrade.WithOnlyChecks(upgrade.CheckNodeRuntime),
+			)
+			require.NoError(t, err)
+
+			report, err := checks.RunReport(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedMessages, xslices.Map(report.Findings, func(f upgrade.Finding) string { return f.Message }))
+		})
+	}
+}
+
+func TestComponentRemovedItemsErrorEncoding(t *testing.T) {
+	removedItemsError := upgrade.ComponentRemovedItemsError{
+		FeatureGates: []upgrade.ComponentItem{
+			{Node: "10.5.0.2", Component: k8s.APIServerID, Value: "CSIMigration"},
+		},
+		APIResources: map[string]int{"podsecuritypolicies.v1beta1.policy": 3},
+		Findings: []upgrade.Finding{
+			{Check: "custom", Message: "something is wrong", Severity: upgrade.SeverityWarning},
+		},
+	}
+
+	assert.Equal(t, upgrade.ItemCounts{
+		FeatureGates: 1,
+		APIResources: 3,
+		Errors:       2,
+		Warnings:     1,
+	}, removedItemsError.Counts())
+
+	data, err := json.Marshal(removedItemsError)
+	require.NoError(t, err)
+
+	var decoded map[string]any
+
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, map[string]any{"podsecuritypolicies.v1beta1.policy": 3.0}, decoded["apiResources"])
+	assert.Len(t, decoded["featureGates"], 1)
+	assert.Len(t, decoded["findings"], 1)
+	assert.Equal(t, 2.0, decoded["counts"].(map[string]any)["errors"]) //nolint:forcetypeassert
+
+	var roundtrip upgrade.ComponentRemovedItemsError
+
+	require.NoError(t, json.Unmarshal(data, &roundtrip))
+	assert.Equal(t, removedItemsError, roundtrip)
+
+	var buf strings.Builder
+
+	slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return attr
+		},
+	})).Info("upgrade checks", "result", removedItemsError)
+
+	assert.Equal(t, "level=INFO msg=\"upgrade checks\" result.featureGates=1 result.apiResources=3 result.errors=2 result.warnings=1\n", buf.String())
+
+	var target *upgrade.ComponentRemovedItemsError
+
+	require.ErrorAs(t, removedItemsError.ErrorOrNil(), &target)
+	assert.Equal(t, removedItemsError.FeatureGates, target.FeatureGates)
+}
@@ -0,0 +1,101 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/blang/semver/v4"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/siderolabs/talos/pkg/machinery/client"
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+)
+
+const cloudControllerManagerDocURL = "https://kubernetes.io/docs/tasks/administer-cluster/running-cloud-controller/"
+
+// inTreeCloudProvidersRemovedVersion is the version the in-tree cloud providers are removed in.
+var inTreeCloudProvidersRemovedVersion = semver.MustParse("1.31.0")
+
+// inTreeCloudProviders are the cloud providers which used to be built into the Kubernetes components.
+var inTreeCloudProviders = []string{"aws", "azure", "gce", "vsphere"}
+
+// PopulateInTreeCloudProvider reports the in-tree cloud provider usage in the component flags.
+//
+// Kubernetes components reject in-tree cloud providers (--cloud-provider=aws, etc.) since 1.31,
+// and --cloud-config is only used by the in-tree cloud providers.
+// Nothing is reported if the upgrade version still supports in-tree cloud providers.
+func (e *ComponentRemovedItemsError) PopulateInTreeCloudProvider(node, component string, cliFlags []string, path *Path) {
+	if path.to.LT(inTreeCloudProvidersRemovedVersion) {
+		return
+	}
+
+	if provider := flagValue(cliFlags, "cloud-provider"); slices.Contains(inTreeCloudProviders, provider) {
+		e.Findings = append(e.Findings, Finding{
+			Check:     CheckInTreeCloudProvider,
+			Node:      node,
+			Component: component,
+			Message: fmt.Sprintf("in-tree cloud provider %q is removed in %d.%d", provider,
+				inTreeCloudProvidersRemovedVersion.Major, inTreeCloudProvidersRemovedVersion.Minor),
+			Remediation: fmt.Sprintf("deploy the external cloud controller manager and set cloud-provider to external in %s", machineConfigArgs(component)),
+			DocURL:      cloudControllerManagerDocURL,
+			Severity:    SeverityError,
+			Item:        "cloud-provider",
+			FlagValue:   provider,
+		})
+	}
+
+	if config := flagValue(cliFlags, "cloud-config"); config != "" {
+		e.Findings = append(e.Findings, Finding{
+			Check:       CheckInTreeCloudProvider,
+			Node:        node,
+			Component:   component,
+			Message:     fmt.Sprintf("--cloud-config=%s is only used by in-tree cloud providers", config),
+			Remediation: fmt.Sprintf("remove --cloud-config from %s, and configure the external cloud controller manager instead", machineConfigArgs(component)),
+			DocURL:      cloudControllerManagerDocURL,
+			Severity:    SeverityWarning,
+			Item:        "cloud-config",
+			FlagValue:   config,
+		})
+	}
+}
+
+// populateInTreeCloudProvider checks the flags of the control plane components and kubelet on the node.
+func (checks *Checks) populateInTreeCloudProvider(ctx context.Context, report *ComponentRemovedItemsError, node string) error {
+	if slices.Contains(checks.controlPlaneNodes, node) {
+		for _, id := range []string{k8s.APIServerID, k8s.ControllerManagerID} {
+			staticPod, err := safe.StateGet[*k8s.StaticPod](client.WithNode(ctx, node), checks.state, k8s.NewStaticPod(k8s.NamespaceName, id).Metadata())
+			if err != nil {
+				if state.IsNotFoundError(err) {
+					continue
+				}
+
+				return err
+			}
+
+			pod, err := staticPodTypedResourceToK8sPodSpec(staticPod)
+			if err != nil {
+				return err
+			}
+
+			report.PopulateInTreeCloudProvider(node, id, pod.Spec.Containers[0].Command, checks.path)
+		}
+	}
+
+	kubeletSpec, err := safe.StateGet[*k8s.KubeletSpec](client.WithNode(ctx, node), checks.state, k8s.NewKubeletSpec(k8s.NamespaceName, k8s.KubeletID).Metadata())
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	report.PopulateInTreeCloudProvider(node, k8s.KubeletID, kubeletSpec.TypedSpec().Args, checks.path)
+
+	return nil
+}
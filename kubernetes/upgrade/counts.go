@@ -0,0 +1,107 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"encoding/json"
+	"log/slog"
+)
+
+// ItemCounts are the numbers of the items reported by ComponentRemovedItemsError per category.
+type ItemCounts struct {
+	AdmissionPlugins int `json:"admissionPlugins" yaml:"admissionPlugins"`
+	FeatureGates     int `json:"featureGates" yaml:"featureGates"`
+	CLIFlags         int `json:"cliFlags" yaml:"cliFlags"`
+	ConfigFields     int `json:"configFields" yaml:"configFields"`
+	// APIResources is the number of objects of the removed API resources.
+	APIResources int `json:"apiResources" yaml:"apiResources"`
+	// Errors, Warnings and Info are the numbers of all findings (including the removed items) by severity.
+	Errors   int `json:"errors" yaml:"errors"`
+	Warnings int `json:"warnings" yaml:"warnings"`
+	Info     int `json:"info" yaml:"info"`
+}
+
+// Counts returns the numbers of the reported items per category.
+func (e ComponentRemovedItemsError) Counts() ItemCounts {
+	counts := ItemCounts{
+		AdmissionPlugins: len(e.AdmissionFlags),
+		FeatureGates:     len(e.FeatureGates),
+		CLIFlags:         len(e.CLIFlags),
+		ConfigFields:     len(e.ConfigFields),
+	}
+
+	for _, count := range e.APIResources {
+		counts.APIResources += count
+	}
+
+	for _, finding := range e.Report().Findings {
+		switch finding.Severity {
+		case SeverityError:
+			counts.Errors++
+		case SeverityWarning:
+			counts.Warnings++
+		case SeverityInfo:
+			counts.Info++
+		}
+	}
+
+	return counts
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// The reported items are marshaled along with their counts, see Counts.
+func (e ComponentRemovedItemsError) MarshalJSON() ([]byte, error) {
+	// items has the same fields, but not the methods, so that MarshalJSON is not called recursively
+	type items ComponentRemovedItemsError
+
+	return json.Marshal(struct {
+		items
+
+		Counts ItemCounts `json:"counts"`
+	}{
+		items:  items(e),
+		Counts: e.Counts(),
+	})
+}
+
+// LogValue implements slog.LogValuer.
+//
+// Only the non-zero counts are logged, the items are available via Report.
+func (e ComponentRemovedItemsError) LogValue() slog.Value {
+	counts := e.Counts()
+
+	var attrs []slog.Attr
+
+	for _, attr := range []slog.Attr{
+		slog.Int("admissionPlugins", counts.AdmissionPlugins),
+		slog.Int("featureGates", counts.FeatureGates),
+		slog.Int("cliFlags", counts.CLIFlags),
+		slog.Int("configFields", counts.ConfigFields),
+		slog.Int("apiResources", counts.APIResources),
+		slog.Int("errors", counts.Errors),
+		slog.Int("warnings", counts.Warnings),
+		slog.Int("info", counts.Info),
+	} {
+		if attr.Value.Int64() != 0 {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// As allows to extract the error with errors.As into a *ComponentRemovedItemsError target.
+//
+// Checks.Run returns the error by value, so errors.As works with both value and pointer targets.
+func (e ComponentRemovedItemsError) As(target any) bool {
+	if target, ok := target.(**ComponentRemovedItemsError); ok {
+		*target = &e
+
+		return true
+	}
+
+	return false
+}
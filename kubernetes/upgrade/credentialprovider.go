@@ -0,0 +1,89 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const credentialProvidersDocURL = "https://kubernetes.io/docs/tasks/administer-cluster/kubelet-credential-provider/"
+
+// Flags of the kubelet credential providers, which should be set together.
+const (
+	credentialProviderConfigFlag = "image-credential-provider-config"
+	credentialProviderBinDirFlag = "image-credential-provider-bin-dir"
+)
+
+// PopulateKubeletCredentialProviders reports the kubelet credential provider configuration which doesn't work in the upgrade version.
+//
+// The credential provider configuration is checked for the API versions removed in the upgrade version
+// (both the configuration itself and the API versions of the providers), and the kubelet flags
+// are checked to be set together.
+func (e *ComponentRemovedItemsError) PopulateKubeletCredentialProviders(node string, cliFlags []string, config map[string]any, removedAPIVersions []string) {
+	configFlag, binDirFlag := flagValue(cliFlags, credentialProviderConfigFlag), flagValue(cliFlags, credentialProviderBinDirFlag)
+
+	if (configFlag == "") != (binDirFlag == "") {
+		missing, set := credentialProviderBinDirFlag, credentialProviderConfigFlag
+
+		if configFlag == "" {
+			missing, set = set, missing
+		}
+
+		e.Findings = append(e.Findings, Finding{
+			Check:       findingCredentialProvider,
+			Node:        node,
+			Component:   k8s.KubeletID,
+			Message:     fmt.Sprintf("--%s is set without --%s", set, missing),
+			Remediation: fmt.Sprintf("set both --%s and --%s in %s, or remove them", set, missing, machineConfigArgs(k8s.KubeletID)),
+			DocURL:      credentialProvidersDocURL,
+			Severity:    SeverityError,
+			Item:        set,
+		})
+	}
+
+	if len(config) == 0 {
+		return
+	}
+
+	apiVersions := []string{}
+
+	if apiVersion, _, _ := unstructured.NestedString(config, "apiVersion"); apiVersion != "" {
+		apiVersions = append(apiVersions, apiVersion)
+	}
+
+	// the configuration comes from YAML, so the values are not deep-copied to JSON types
+	providers, _ := config["providers"].([]any)
+
+	for _, provider := range providers {
+		if provider, ok := provider.(map[string]any); ok {
+			if apiVersion, _, _ := unstructured.NestedString(provider, "apiVersion"); apiVersion != "" {
+				apiVersions = append(apiVersions, apiVersion)
+			}
+		}
+	}
+
+	slices.Sort(apiVersions)
+
+	for _, apiVersion := range slices.Compact(apiVersions) {
+		if !slices.Contains(removedAPIVersions, apiVersion) {
+			continue
+		}
+
+		e.Findings = append(e.Findings, Finding{
+			Check:       findingCredentialProvider,
+			Node:        node,
+			Component:   k8s.KubeletID,
+			Message:     fmt.Sprintf("kubelet credential provider configuration uses removed API version %s", apiVersion),
+			Remediation: "update the API versions in machine.kubelet.credentialProviderConfig to v1",
+			DocURL:      credentialProvidersDocURL,
+			Severity:    SeverityError,
+			Item:        apiVersion,
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/cosi-project/runtime/pkg/state"
+	"k8s.io/client-go/rest"
+)
+
+// CheckFunc is a custom pre-upgrade check.
+//
+// The check returns the problems found as findings, and an error only if the check itself failed to run.
+type CheckFunc func(ctx context.Context, env CheckEnv) ([]Finding, error)
+
+// CheckEnv is the environment passed to the custom checks.
+type CheckEnv struct {
+	State             state.State
+	K8sConfig         *rest.Config
+	Path              *Path
+	ControlPlaneNodes []string
+	WorkerNodes       []string
+	Log               func(string, ...any)
+}
+
+type customCheck struct {
+	fn   CheckFunc
+	name string
+}
+
+// Register adds a custom check which runs after the built-in ones.
+//
+// Findings of the custom checks are reported in the same ComponentRemovedItemsError as the built-in checks.
+// Registering a check with the same name replaces the previous one.
+func (checks *Checks) Register(name string, fn CheckFunc) {
+	if idx := slices.IndexFunc(checks.customChecks, func(c customCheck) bool { return c.name == name }); idx != -1 {
+		checks.customChecks[idx].fn = fn
+
+		return
+	}
+
+	checks.customChecks = append(checks.customChecks, customCheck{name: name, fn: fn})
+}
+
+func (checks *Checks) runCustomChecks(ctx context.Context, report *ComponentRemovedItemsError) error {
+	env := CheckEnv{
+		State:             checks.state,
+		K8sConfig:         checks.k8sConfig,
+		Path:              checks.path,
+		ControlPlaneNodes: checks.controlPlaneNodes,
+		WorkerNodes:       checks.workerNodes,
+		Log:               checks.log,
+	}
+
+	for _, check := range checks.customChecks {
+		if !checks.options.enabled(check.name) {
+			continue
+		}
+
+		if err := checks.runCheck(ctx, check.name, fmt.Sprintf("running check %q", check.name), report, func(ctx context.Context) error {
+			findings, err := check.fn(ctx, env)
+			if err != nil {
+				return fmt.Errorf("error running check %q: %w", check.name, err)
+			}
+
+			for _, finding := range findings {
+				finding.Check = check.name
+
+				report.Findings = append(report.Findings, finding)
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/siderolabs/talos/pkg/machinery/client"
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const staticPodDriftRemediation = "wait for the previous upgrade to finish rolling out on the node (or re-run it) before upgrading"
+
+// PopulateStaticPodDrift compares the static pods declared by Talos on the node with the mirror pods running in the cluster.
+//
+// A mirror pod which is missing, or runs a different image or command than the declared static pod, means that
+// the previous upgrade (or configuration change) didn't roll out to the node.
+func (e *ComponentRemovedItemsError) PopulateStaticPodDrift(ctx context.Context, clientset kubernetes.Interface, st state.State, node string) error {
+	ctx = client.WithNode(ctx, node)
+
+	nodename, err := safe.StateGet[*k8s.Nodename](ctx, st, k8s.NewNodename(k8s.NamespaceName, k8s.NodenameID).Metadata())
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, id := range []string{k8s.APIServerID, k8s.ControllerManagerID, k8s.SchedulerID} {
+		staticPod, err := safe.StateGet[*k8s.StaticPod](ctx, st, k8s.NewStaticPod(k8s.NamespaceName, id).Metadata())
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				// not defined on the node
+				continue
+			}
+
+			return err
+		}
+
+		desired, err := staticPodTypedResourceToK8sPodSpec(staticPod)
+		if err != nil {
+			return err
+		}
+
+		name := id + "-" + nodename.TypedSpec().Nodename
+
+		mirror, err := clientset.CoreV1().Pods(metav1.NamespaceSystem).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsForbidden(err) {
+				return nil
+			}
+
+			if !apierrors.IsNotFound(err) {
+				return fmt.Errorf("error getting pod %s: %w", name, err)
+			}
+
+			e.Findings = append(e.Findings, Finding{
+				Check:       CheckStaticPodDrift,
+				Node:        node,
+				Component:   id,
+				Message:     fmt.Sprintf("mirror pod %s/%s is not found", metav1.NamespaceSystem, name),
+				Remediation: staticPodDriftRemediation,
+				Severity:    SeverityError,
+			})
+
+			continue
+		}
+
+		for _, message := range podSpecDrift(desired.Spec.Containers, mirror.Spec.Containers) {
+			e.Findings = append(e.Findings, Finding{
+				Check:       CheckStaticPodDrift,
+				Node:        node,
+				Component:   id,
+				Message:     message,
+				Remediation: staticPodDriftRemediation,
+				Severity:    SeverityError,
+			})
+		}
+	}
+
+	return nil
+}
+
+// podSpecDrift returns the differences between the declared and the running containers.
+func podSpecDrift(desired, running []v1.Container) []string {
+	var drift []string
+
+	for _, container := range desired {
+		idx := slices.IndexFunc(running, func(c v1.Container) bool { return c.Name == container.Name })
+		if idx == -1 {
+			drift = append(drift, fmt.Sprintf("container %s is not running", container.Name))
+
+			continue
+		}
+
+		if running[idx].Image != container.Image {
+			drift = append(drift, fmt.Sprintf("container %s runs image %s, declared %s", container.Name, running[idx].Image, container.Image))
+		}
+
+		if !slices.Equal(running[idx].Command, container.Command) || !slices.Equal(running[idx].Args, container.Args) {
+			drift = append(drift, fmt.Sprintf("container %s runs with outdated command line", container.Name))
+		}
+	}
+
+	return drift
+}
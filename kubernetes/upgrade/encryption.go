@@ -0,0 +1,136 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/siderolabs/talos/pkg/machinery/constants"
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+)
+
+const (
+	kmsDocURL = "https://kubernetes.io/docs/tasks/administer-cluster/kms-provider/"
+	// kmsv1FeatureGate enables KMS v1 providers after they are disabled by default.
+	kmsv1FeatureGate = "KMSv1"
+)
+
+var (
+	// kmsv1DeprecatedVersion is the version KMS v1 providers are deprecated in.
+	kmsv1DeprecatedVersion = semver.MustParse("1.28.0")
+	// kmsv1DisabledVersion is the version KMS v1 providers are disabled by default in (KMSv1 feature gate).
+	kmsv1DisabledVersion = semver.MustParse("1.29.0")
+)
+
+// talosEncryptionConfigPath is the encryption configuration managed by Talos, which doesn't use KMS providers.
+var talosEncryptionConfigPath = constants.KubernetesAPIServerSecretsDir + "/encryptionconfig.yaml"
+
+// PopulateEncryptionConfig reports the kube-apiserver encryption providers which are not compatible with the upgrade version.
+//
+// KMS v1 providers are deprecated since 1.28, and disabled by default since 1.29 (unless the KMSv1 feature gate is enabled).
+// If the encryption configuration is not available (nil), only the custom encryption configuration flag is reported.
+func (e *ComponentRemovedItemsError) PopulateEncryptionConfig(node string, cliFlags []string, encryptionConfig map[string]any, path *Path) {
+	target := semver.Version{Major: path.to.Major, Minor: path.to.Minor}
+
+	configPath := flagValue(cliFlags, "encryption-provider-config")
+
+	if target.LT(kmsv1DeprecatedVersion) || configPath == "" {
+		return
+	}
+
+	disabled := target.GTE(kmsv1DisabledVersion) && !featureGateEnabled(cliFlags, kmsv1FeatureGate)
+
+	remediation := "migrate the KMS providers to KMS v2 (apiVersion: v2) in the encryption provider configuration"
+
+	if encryptionConfig == nil {
+		if configPath == talosEncryptionConfigPath || !disabled {
+			return
+		}
+
+		e.Findings = append(e.Findings, Finding{
+			Check:     findingEncryptionProvider,
+			Node:      node,
+			Component: k8s.APIServerID,
+			Message: fmt.Sprintf("custom encryption provider configuration %s is used, KMS v1 providers are disabled by default since %d.%d",
+				configPath, kmsv1DisabledVersion.Major, kmsv1DisabledVersion.Minor),
+			Remediation: remediation + fmt.Sprintf(", or enable the %s feature gate", kmsv1FeatureGate),
+			DocURL:      kmsDocURL,
+			Severity:    SeverityWarning,
+			Item:        "encryption-provider-config",
+			FlagValue:   configPath,
+		})
+
+		return
+	}
+
+	for _, name := range kmsv1Providers(encryptionConfig) {
+		finding := Finding{
+			Check:     findingEncryptionProvider,
+			Node:      node,
+			Component: k8s.APIServerID,
+			Message: fmt.Sprintf("KMS v1 provider %q is deprecated since %d.%d", name,
+				kmsv1DeprecatedVersion.Major, kmsv1DeprecatedVersion.Minor),
+			Remediation: remediation,
+			DocURL:      kmsDocURL,
+			Severity:    SeverityWarning,
+			Item:        name,
+		}
+
+		if disabled {
+			finding.Message = fmt.Sprintf("KMS v1 provider %q is disabled by default since %d.%d", name,
+				kmsv1DisabledVersion.Major, kmsv1DisabledVersion.Minor)
+			finding.Remediation += fmt.Sprintf(", or enable the %s feature gate", kmsv1FeatureGate)
+			finding.Severity = SeverityError
+		}
+
+		e.Findings = append(e.Findings, finding)
+	}
+}
+
+// kmsv1Providers returns the names of the KMS v1 providers in the EncryptionConfiguration.
+func kmsv1Providers(encryptionConfig map[string]any) []string {
+	var names []string
+
+	resources, _ := encryptionConfig["resources"].([]any)
+
+	for _, resource := range resources {
+		resource, _ := resource.(map[string]any)
+		providers, _ := resource["providers"].([]any)
+
+		for _, provider := range providers {
+			provider, _ := provider.(map[string]any)
+
+			kms, ok := provider["kms"].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			// apiVersion defaults to v1
+			if apiVersion, _ := kms["apiVersion"].(string); apiVersion != "" && apiVersion != "v1" {
+				continue
+			}
+
+			name, _ := kms["name"].(string)
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// featureGateEnabled returns true if the feature gate is explicitly enabled with the component flags.
+func featureGateEnabled(cliFlags []string, gate string) bool {
+	for _, featureGate := range strings.Split(flagValue(cliFlags, "feature-gates"), ",") {
+		name, value, _ := strings.Cut(featureGate, "=")
+
+		if strings.TrimSpace(name) == gate {
+			return strings.EqualFold(strings.TrimSpace(value), "true")
+		}
+	}
+
+	return false
+}
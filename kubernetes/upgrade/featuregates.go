@@ -0,0 +1,126 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const featureGatesURL = "https://kubernetes.io/docs/reference/command-line-tools-reference/feature-gates/"
+
+// upstreamFeatureGateChanges are the feature gate changes of the upgrade path scraped from the Kubernetes repository.
+//
+// The data is generated with `go generate` (see kubernetes/internal/gendata) into featuregates_generated.go.
+type upstreamFeatureGateChanges struct {
+	// removed are the feature gates removed in the upgrade version
+	removed []string
+	// locked are the feature gates newly locked to the value in the upgrade version
+	locked map[string]bool
+}
+
+// withUpstreamFeatureGates merges the generated upstream feature gates data into the checks data.
+//
+// The curated data takes precedence, the upstream data only adds the feature gates which are not listed.
+func withUpstreamFeatureGates(checks map[string]componentChecks) map[string]componentChecks {
+	for path, upstream := range upstreamFeatureGates {
+		pathChecks := checks[path]
+
+		for _, name := range upstream.removed {
+			if !slices.Contains(pathChecks.removedFeatureGates, name) {
+				pathChecks.removedFeatureGates = append(pathChecks.removedFeatureGates, name)
+			}
+		}
+
+		for name, value := range upstream.locked {
+			if _, ok := pathChecks.lockedFeatureGates[name]; ok {
+				continue
+			}
+
+			if pathChecks.lockedFeatureGates == nil {
+				pathChecks.lockedFeatureGates = map[string]bool{}
+			}
+
+			pathChecks.lockedFeatureGates[name] = value
+		}
+
+		checks[path] = pathChecks
+	}
+
+	return checks
+}
+
+// PopulateLockedFeatureGates reports the feature gates explicitly set in the component flags to a value
+// which conflicts with the value the gate is locked to in the upgrade version.
+//
+// The component fails to start with such a feature gate, so these findings are blocking.
+func (e *ComponentRemovedItemsError) PopulateLockedFeatureGates(node, component string, cliFlags []string, lockedFeatureGates map[string]bool) {
+	value := flagValue(cliFlags, "feature-gates")
+	if value == "" {
+		return
+	}
+
+	gates := map[string]string{}
+
+	for _, featureGate := range strings.Split(value, ",") {
+		name, gateValue, _ := strings.Cut(featureGate, "=")
+
+		gates[strings.TrimSpace(name)] = strings.TrimSpace(gateValue)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(lockedFeatureGates)) {
+		gateValue, ok := gates[name]
+		if !ok {
+			continue
+		}
+
+		enabled, err := strconv.ParseBool(gateValue)
+		if err != nil || enabled == lockedFeatureGates[name] {
+			continue
+		}
+
+		e.Findings = append(e.Findings, Finding{
+			Check:       findingLockedFeatureGate,
+			Node:        node,
+			Component:   component,
+			Message:     fmt.Sprintf("feature gate %s is set to %s, but it is locked to %t in the upgrade version", name, gateValue, lockedFeatureGates[name]),
+			Remediation: fmt.Sprintf("remove %s from --feature-gates in %s", name, machineConfigArgs(component)),
+			DocURL:      featureGatesURL,
+			Severity:    SeverityError,
+			Item:        name,
+			FlagValue:   value,
+		})
+	}
+}
+
+// PopulateLockedKubeletConfigFeatureGates reports the feature gates set in the kubelet configuration
+// to a value which conflicts with the value the gate is locked to in the upgrade version.
+func (e *ComponentRemovedItemsError) PopulateLockedKubeletConfigFeatureGates(node string, config map[string]any, lockedFeatureGates map[string]bool) {
+	featureGates, _, _ := unstructured.NestedMap(config, "featureGates") //nolint:errcheck
+
+	for _, name := range slices.Sorted(maps.Keys(lockedFeatureGates)) {
+		enabled, ok := featureGates[name].(bool)
+		if !ok || enabled == lockedFeatureGates[name] {
+			continue
+		}
+
+		e.Findings = append(e.Findings, Finding{
+			Check:       findingLockedFeatureGate,
+			Node:        node,
+			Component:   k8s.KubeletID,
+			Message:     fmt.Sprintf("feature gate %s is set to %t in the kubelet configuration, but it is locked to %t in the upgrade version", name, enabled, lockedFeatureGates[name]),
+			Remediation: fmt.Sprintf("remove %s from machine.kubelet.extraConfig.featureGates", name),
+			DocURL:      featureGatesURL,
+			Severity:    SeverityError,
+			Item:        name,
+		})
+	}
+}
@@ -0,0 +1,352 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Code generated by gendata. DO NOT EDIT.
+
+package upgrade
+
+var upstreamFeatureGates = map[string]upstreamFeatureGateChanges{
+	"1.24->1.25": {
+		removed: []string{
+			"CSIServiceAccountToken",
+			"CSIVolumeFSGroupPolicy",
+			"ConfigurableFSGroupPolicy",
+			"EndpointSlice",
+			"EndpointSliceNodeName",
+			"EndpointSliceProxying",
+			"GenericEphemeralVolume",
+			"IngressClassNamespacedParams",
+			"PodDisruptionBudget",
+			"SelectorIndex",
+			"StorageObjectInUseProtection",
+			"TTLAfterFinished",
+			"VolumeSubpath",
+			"WindowsEndpointSliceProxying",
+		},
+		locked: map[string]bool{
+			"CSIInlineVolume":                true,
+			"CSIMigration":                   true,
+			"CSIMigrationAWS":                true,
+			"CSIMigrationAzureDisk":          true,
+			"CSIMigrationGCE":                true,
+			"DaemonSetUpdateSurge":           true,
+			"DisableAcceleratorUsageMetrics": true,
+			"EphemeralContainers":            true,
+			"IdentifyPodOS":                  true,
+			"LocalStorageCapacityIsolation":  true,
+			"NetworkPolicyEndPort":           true,
+			"PodSecurity":                    true,
+			"StatefulSetMinReadySeconds":     true,
+		},
+	},
+	"1.25->1.26": {
+		removed: []string{
+			"CSIMigrationOpenStack",
+			"CSRDuration",
+			"DefaultPodTopologySpread",
+			"DynamicKubeletConfig",
+			"IndexedJob",
+			"NonPreemptingPriority",
+			"PodAffinityNamespaceSelector",
+			"PodOverhead",
+			"PreferNominatedNode",
+			"ServiceLBNodePortControl",
+			"ServiceLoadBalancerClass",
+			"SuspendJob",
+		},
+		locked: map[string]bool{
+			"CPUManager":                        true,
+			"CSIMigrationvSphere":               true,
+			"DelegateFSGroupToCSIDriver":        true,
+			"DevicePlugins":                     true,
+			"DryRun":                            true,
+			"EndpointSliceTerminatingCondition": true,
+			"JobTrackingWithFinalizers":         true,
+			"KubeletCredentialProviders":        true,
+			"MixedProtocolLBService":            true,
+			"ServerSideApply":                   true,
+			"ServiceIPStaticSubrange":           true,
+			"ServiceInternalTrafficPolicy":      true,
+			"WindowsHostProcessContainers":      true,
+		},
+	},
+	"1.26->1.27": {
+		removed: []string{
+			"CSIInlineVolume",
+			"CSIMigration",
+			"CSIMigrationAWS",
+			"CSIMigrationAzureDisk",
+			"ControllerManagerLeaderMigration",
+			"DaemonSetUpdateSurge",
+			"EphemeralContainers",
+			"ExpandCSIVolumes",
+			"ExpandInUsePersistentVolumes",
+			"ExpandPersistentVolumes",
+			"IPv6DualStack",
+			"IdentifyPodOS",
+			"LocalStorageCapacityIsolation",
+			"NetworkPolicyEndPort",
+			"StatefulSetMinReadySeconds",
+		},
+		locked: map[string]bool{
+			"AdvancedAuditing":                          true,
+			"CSIMigrationAzureFile":                     true,
+			"CronJobTimeZone":                           true,
+			"DownwardAPIHugePages":                      true,
+			"GRPCContainerProbe":                        true,
+			"JobMutableNodeSchedulingDirectives":        true,
+			"LegacyServiceAccountTokenNoAutoGeneration": true,
+			"OpenAPIV3":                                 true,
+			"SeccompDefault":                            true,
+			"ServerSideFieldValidation":                 true,
+			"TopologyManager":                           true,
+		},
+	},
+	"1.27->1.28": {
+		removed: []string{
+			"AdvancedAuditing",
+			"CSIMigrationGCE",
+			"CSIStorageCapacity",
+			"DelegateFSGroupToCSIDriver",
+			"DevicePlugins",
+			"DisableAcceleratorUsageMetrics",
+			"DryRun",
+			"EndpointSliceTerminatingCondition",
+			"KubeletCredentialProviders",
+			"MixedProtocolLBService",
+			"NetworkPolicyStatus",
+			"PodHasNetworkCondition",
+			"PodSecurity",
+			"ServiceIPStaticSubrange",
+			"ServiceInternalTrafficPolicy",
+			"UserNamespacesStatelessPodsSupport",
+			"WindowsHostProcessContainers",
+		},
+		locked: map[string]bool{
+			"APISelfSubjectReview":                    true,
+			"ExpandedDNSConfig":                       true,
+			"ExperimentalHostUserNamespaceDefaulting": false,
+			"IPTablesOwnershipCleanup":                true,
+			"KubeletPodResources":                     true,
+			"KubeletPodResourcesGetAllocatable":       true,
+			"LegacyServiceAccountTokenTracking":       true,
+			"MinimizeIPTablesRestore":                 true,
+			"NodeOutOfServiceVolumeDetach":            true,
+			"ProbeTerminationGracePeriod":             true,
+			"ProxyTerminatingEndpoints":               true,
+			"RetroactiveDefaultStorageClass":          true,
+		},
+	},
+	"1.28->1.29": {
+		removed: []string{
+			"CSIMigrationvSphere",
+			"CronJobTimeZone",
+			"DownwardAPIHugePages",
+			"GRPCContainerProbe",
+			"JobMutableNodeSchedulingDirectives",
+			"JobTrackingWithFinalizers",
+			"LegacyServiceAccountTokenNoAutoGeneration",
+			"MultiCIDRRangeAllocator",
+			"OpenAPIV3",
+			"ProbeTerminationGracePeriod",
+			"RetroactiveDefaultStorageClass",
+			"SeccompDefault",
+			"TopologyManager",
+		},
+		locked: map[string]bool{
+			"APIListChunking":                     true,
+			"APIPriorityAndFairness":              true,
+			"CSINodeExpandSecret":                 true,
+			"CustomResourceValidationExpressions": true,
+			"JobReadyPods":                        true,
+			"KMSv2":                               true,
+			"KMSv2KDF":                            true,
+			"ReadWriteOncePod":                    true,
+			"RemainingItemCount":                  true,
+			"ServiceNodePortStaticSubrange":       true,
+		},
+	},
+	"1.29->1.30": {
+		removed: []string{
+			"APISelfSubjectReview",
+			"CSIMigrationAzureFile",
+			"ExpandedDNSConfig",
+			"ExperimentalHostUserNamespaceDefaulting",
+			"IPTablesOwnershipCleanup",
+			"KubeletPodResources",
+			"KubeletPodResourcesGetAllocatable",
+			"LegacyServiceAccountTokenTracking",
+			"MinimizeIPTablesRestore",
+			"ProxyTerminatingEndpoints",
+			"RemoveSelfLink",
+			"SecurityContextDeny",
+		},
+		locked: map[string]bool{
+			"AdmissionWebhookMatchConditions":     true,
+			"AggregatedDiscoveryEndpoint":         true,
+			"CloudDualStackNodeIPs":               true,
+			"ConsistentHTTPGetHandlers":           true,
+			"HPAContainerMetrics":                 true,
+			"LegacyServiceAccountTokenCleanUp":    true,
+			"MinDomainsInPodTopologySpread":       true,
+			"NewVolumeManagerReconstruction":      true,
+			"PodHostIPs":                          true,
+			"PodSchedulingReadiness":              true,
+			"StableLoadBalancerNodeSet":           true,
+			"ValidatingAdmissionPolicy":           true,
+			"ZeroLimitedNominalConcurrencyShares": true,
+		},
+	},
+	"1.30->1.31": {
+		removed: []string{
+			"APIPriorityAndFairness",
+			"CSIMigrationRBD",
+			"CSINodeExpandSecret",
+			"ConsistentHTTPGetHandlers",
+			"CustomResourceValidationExpressions",
+			"DefaultHostNetworkHostPortsInPodTemplates",
+			"InTreePluginAWSUnregister",
+			"InTreePluginAzureDiskUnregister",
+			"InTreePluginAzureFileUnregister",
+			"InTreePluginGCEUnregister",
+			"InTreePluginOpenStackUnregister",
+			"InTreePluginRBDUnregister",
+			"InTreePluginvSphereUnregister",
+			"JobReadyPods",
+			"ReadWriteOncePod",
+			"ServiceNodePortStaticSubrange",
+			"SkipReadOnlyValidationGCE",
+		},
+		locked: map[string]bool{
+			"AppArmor":                                true,
+			"AppArmorFields":                          true,
+			"DevicePluginCDIDevices":                  true,
+			"DisableCloudProviders":                   true,
+			"DisableKubeletCloudCredentialProviders":  true,
+			"ElasticIndexedJob":                       true,
+			"JobPodFailurePolicy":                     true,
+			"KubeProxyDrainingTerminatingNodes":       true,
+			"LogarithmicScaleDown":                    true,
+			"PDBUnhealthyPodEvictionPolicy":           true,
+			"PersistentVolumeLastPhaseTransitionTime": true,
+			"PodDisruptionConditions":                 true,
+			"StatefulSetStartOrdinal":                 true,
+		},
+	},
+	"1.31->1.32": {
+		removed: []string{
+			"CloudDualStackNodeIPs",
+			"CustomCPUCFSQuotaPeriod",
+			"DRAControlPlaneController",
+			"HPAContainerMetrics",
+			"KMSv2",
+			"KMSv2KDF",
+			"LegacyServiceAccountTokenCleanUp",
+			"MinDomainsInPodTopologySpread",
+			"NewVolumeManagerReconstruction",
+			"NodeOutOfServiceVolumeDetach",
+			"PodHostIPs",
+			"RuntimeClassInImageCriApi",
+			"ServerSideApply",
+			"ServerSideFieldValidation",
+			"StableLoadBalancerNodeSet",
+			"ValidatingAdmissionPolicy",
+			"ZeroLimitedNominalConcurrencyShares",
+		},
+		locked: map[string]bool{
+			"AllowServiceLBStatusOnNonLB":              false,
+			"CronJobsScheduledAnnotation":              true,
+			"CustomResourceFieldSelectors":             true,
+			"LoadBalancerIPMode":                       true,
+			"MemoryManager":                            true,
+			"PodIndexLabel":                            true,
+			"RetryGenerateName":                        true,
+			"ServiceAccountTokenJTI":                   true,
+			"ServiceAccountTokenNodeBindingValidation": true,
+			"ServiceAccountTokenPodNodeInfo":           true,
+			"SizeMemoryBackedVolumes":                  true,
+			"StatefulSetAutoDeletePVC":                 true,
+			"StrictCostEnforcementForVAP":              true,
+			"StrictCostEnforcementForWebhooks":         true,
+			"StructuredAuthorizationConfiguration":     true,
+		},
+	},
+	"1.32->1.33": {
+		removed: []string{
+			"APIListChunking",
+			"AdmissionWebhookMatchConditions",
+			"AggregatedDiscoveryEndpoint",
+			"AppArmor",
+			"AppArmorFields",
+			"CPUCFSQuotaPeriod",
+			"CPUManager",
+			"DisableCloudProviders",
+			"DisableKubeletCloudCredentialProviders",
+			"EfficientWatchResumption",
+			"JobPodFailurePolicy",
+			"KubeProxyDrainingTerminatingNodes",
+			"PDBUnhealthyPodEvictionPolicy",
+			"PersistentVolumeLastPhaseTransitionTime",
+			"RemainingItemCount",
+			"RuntimeClassInImageCriAPI",
+			"VolumeCapacityPriority",
+			"WatchBookmark",
+		},
+		locked: map[string]bool{
+			"AnyVolumeDataSource":                    true,
+			"BtreeWatchCache":                        true,
+			"CPUManagerPolicyOptions":                true,
+			"CRDValidationRatcheting":                true,
+			"CSIMigrationPortworx":                   true,
+			"HonorPVReclaimPolicy":                   true,
+			"JobBackoffLimitPerIndex":                true,
+			"JobSuccessPolicy":                       true,
+			"MatchLabelKeysInPodAffinity":            true,
+			"NFTablesProxyMode":                      true,
+			"NodeInclusionPolicyInPodTopologySpread": true,
+			"RecursiveReadOnlyMounts":                true,
+			"ServiceAccountTokenNodeBinding":         true,
+			"ServiceTrafficDistribution":             true,
+			"SidecarContainers":                      true,
+			"TopologyAwareHints":                     true,
+			"WatchFromStorageWithoutResourceVersion": false,
+		},
+	},
+	"1.33->1.34": {
+		removed: []string{
+			"DevicePluginCDIDevices",
+			"ElasticIndexedJob",
+			"LegacySidecarContainers",
+			"PodDisruptionConditions",
+			"StatefulSetStartOrdinal",
+		},
+		locked: map[string]bool{
+			"APIServerTracing":                      true,
+			"AnonymousAuthConfigurableEndpoints":    true,
+			"AuthorizeNodeWithSelectors":            true,
+			"AuthorizeWithSelectors":                true,
+			"ConsistentListFromCache":               true,
+			"JobPodReplacementPolicy":               true,
+			"KubeletCgroupDriverFromCRI":            true,
+			"KubeletTracing":                        true,
+			"MultiCIDRServiceAllocator":             true,
+			"NodeSwap":                              true,
+			"OrderedNamespaceDeletion":              true,
+			"PodLifecycleSleepAction":               true,
+			"PodLifecycleSleepActionAllowZero":      true,
+			"ProbeHostPodSecurityStandards":         true,
+			"RecoverVolumeExpansionFailure":         true,
+			"RelaxedDNSSearchValidation":            true,
+			"RelaxedEnvironmentVariableValidation":  true,
+			"ResilientWatchCacheInitialization":     true,
+			"SchedulerQueueingHints":                true,
+			"SeparateTaintEvictionController":       true,
+			"StreamingCollectionEncodingToJSON":     true,
+			"StreamingCollectionEncodingToProtobuf": true,
+			"StructuredAuthenticationConfiguration": true,
+			"WinDSR":                                true,
+			"WinOverlay":                            true,
+		},
+	},
+}
@@ -0,0 +1,74 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import "fmt"
+
+// Severity is the severity of a finding.
+type Severity int
+
+// Finding severities.
+const (
+	// SeverityError blocks the upgrade by default.
+	SeverityError Severity = iota
+	// SeverityWarning is reported, but doesn't block the upgrade by default.
+	SeverityWarning
+	// SeverityInfo is an informational finding.
+	SeverityInfo
+)
+
+// String implements fmt.Stringer.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Severity) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "error":
+		*s = SeverityError
+	case "warning":
+		*s = SeverityWarning
+	case "info":
+		*s = SeverityInfo
+	default:
+		return fmt.Errorf("unknown severity %q", string(text))
+	}
+
+	return nil
+}
+
+// Finding is a problem found by a check.
+type Finding struct {
+	// Check is the name of the check which reported the finding, it is set by Checks.Run for the custom checks.
+	Check string `json:"check" yaml:"check"`
+	// Node is empty for the cluster-wide findings.
+	Node      string `json:"node,omitempty" yaml:"node,omitempty"`
+	Component string `json:"component,omitempty" yaml:"component,omitempty"`
+	Message   string `json:"message" yaml:"message"`
+	// Remediation is a hint on how to fix the problem.
+	Remediation string `json:"remediation,omitempty" yaml:"remediation,omitempty"`
+	// DocURL links to the documentation describing the problem.
+	DocURL   string   `json:"docURL,omitempty" yaml:"docURL,omitempty"`
+	Severity Severity `json:"severity" yaml:"severity"`
+	// Item is the removed item (flag, feature gate, admission plugin, config field) the finding is about.
+	Item string `json:"item,omitempty" yaml:"item,omitempty"`
+	// FlagValue is the full value of the list flag the item is set in (e.g. --feature-gates).
+	FlagValue string `json:"flagValue,omitempty" yaml:"flagValue,omitempty"`
+}
@@ -0,0 +1,105 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxListedNodes is the number of nodes listed in the summarized output for each group.
+const maxListedNodes = 3
+
+// GroupedFinding is the finding reported for one or more nodes.
+type GroupedFinding struct {
+	Finding `json:",inline" yaml:",inline"`
+
+	// Nodes are the nodes the finding is reported for, empty for the cluster-wide findings.
+	Nodes []string `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+}
+
+// groupedItem is the removed item found on one or more nodes.
+type groupedItem struct {
+	Component string
+	Value     string
+	Nodes     []string
+}
+
+// Grouped returns the findings which only differ by the node as a single finding with the list of nodes.
+//
+// The order of the findings is preserved (by the first occurrence), the Node field of the grouped findings is empty.
+func (r Report) Grouped() []GroupedFinding {
+	return groupFindings(r.Findings, true)
+}
+
+// groupFindings groups the findings by everything but the node, if grouped is false, each finding is kept as is.
+func groupFindings(findings []Finding, grouped bool) []GroupedFinding {
+	result := make([]GroupedFinding, 0, len(findings))
+	index := map[Finding]int{}
+
+	for _, finding := range findings {
+		var nodes []string
+
+		if finding.Node != "" {
+			nodes = []string{finding.Node}
+		}
+
+		if !grouped {
+			result = append(result, GroupedFinding{Finding: finding, Nodes: nodes})
+
+			continue
+		}
+
+		key := finding
+		key.Node = ""
+
+		if i, ok := index[key]; ok {
+			result[i].Nodes = append(result[i].Nodes, nodes...)
+
+			continue
+		}
+
+		index[key] = len(result)
+		result = append(result, GroupedFinding{Finding: key, Nodes: nodes})
+	}
+
+	return result
+}
+
+// groupItems groups the items by the component and value, if grouped is false, each item is kept as is.
+func groupItems(items []ComponentItem, grouped bool) []groupedItem {
+	result := make([]groupedItem, 0, len(items))
+	index := map[ComponentItem]int{}
+
+	for _, item := range items {
+		var nodes []string
+
+		if item.Node != "" {
+			nodes = []string{item.Node}
+		}
+
+		key := ComponentItem{Component: item.Component, Value: item.Value}
+
+		if i, ok := index[key]; ok && grouped {
+			result[i].Nodes = append(result[i].Nodes, nodes...)
+
+			continue
+		}
+
+		index[key] = len(result)
+		result = append(result, groupedItem{Component: item.Component, Value: item.Value, Nodes: nodes})
+	}
+
+	return result
+}
+
+// formatNodes formats the list of nodes, summarizing long lists.
+func formatNodes(nodes []string) string {
+	if len(nodes) <= maxListedNodes {
+		return strings.Join(nodes, ", ")
+	}
+
+	return fmt.Sprintf("%s (+%d more)", strings.Join(nodes[:maxListedNodes], ", "), len(nodes)-maxListedNodes)
+}
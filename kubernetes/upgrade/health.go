@@ -0,0 +1,179 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/siderolabs/talos/pkg/machinery/client"
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+	"github.com/siderolabs/go-kubernetes/kubernetes/internal/healthz"
+)
+
+const kubeControllerManagerHealthEndpoint = "/healthz"
+
+// PopulateAPIServerHealth checks kube-apiserver readiness and liveness endpoints.
+func (e *ComponentRemovedItemsError) PopulateAPIServerHealth(ctx context.Context, clientset kubernetes.Interface) error {
+	for _, endpoint := range []string{"/readyz", "/livez"} {
+		result := clientset.Discovery().RESTClient().Get().AbsPath(endpoint).Param("verbose", "").Do(ctx)
+
+		var statusCode int
+
+		body, err := result.StatusCode(&statusCode).Raw()
+		if err == nil {
+			continue
+		}
+
+		if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+			// health endpoints are not accessible, skip the check
+			return nil
+		}
+
+		if statusCode == 0 {
+			return fmt.Errorf("error checking kube-apiserver %s: %w", endpoint, err)
+		}
+
+		e.Findings = append(e.Findings, Finding{
+			Check:       CheckControlPlaneHealth,
+			Component:   k8s.APIServerID,
+			Message:     fmt.Sprintf("kube-apiserver %s check failed: %s", endpoint, failedHealthChecks(body)),
+			Remediation: "fix the control plane health before upgrading",
+		})
+	}
+
+	return nil
+}
+
+// failedHealthChecks extracts failed checks from the verbose health endpoint output.
+func failedHealthChecks(body []byte) string {
+	failed := healthz.FailedChecks(body)
+	if len(failed) == 0 {
+		return strings.TrimSpace(string(body))
+	}
+
+	return strings.Join(failed, ", ")
+}
+
+// PopulateStaticPodHealth checks that control plane static pods defined on the node are running and ready.
+func (e *ComponentRemovedItemsError) PopulateStaticPodHealth(ctx context.Context, st state.State, node string) error {
+	ctx = client.WithNode(ctx, node)
+
+	statuses, err := safe.StateListAll[*k8s.StaticPodStatus](ctx, st)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range []string{k8s.APIServerID, k8s.ControllerManagerID, k8s.SchedulerID} {
+		staticPod, err := safe.StateGet[*k8s.StaticPod](ctx, st, k8s.NewStaticPod(k8s.NamespaceName, id).Metadata())
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				// not defined on the node
+				continue
+			}
+
+			return err
+		}
+
+		pod, err := staticPodTypedResourceToK8sPodSpec(staticPod)
+		if err != nil {
+			return err
+		}
+
+		var podStatus map[string]any
+
+		for status := range statuses.All() {
+			if strings.HasPrefix(status.Metadata().ID(), metav1.NamespaceSystem+"/"+id+"-") {
+				podStatus = status.TypedSpec().PodStatus
+
+				break
+			}
+		}
+
+		if podStatus == nil {
+			e.Findings = append(e.Findings, Finding{
+				Check:       CheckControlPlaneHealth,
+				Node:        node,
+				Component:   id,
+				Message:     fmt.Sprintf("%s static pod is not running", id),
+				Remediation: "fix the control plane health before upgrading",
+			})
+
+			continue
+		}
+
+		phase, _, _ := unstructured.NestedString(podStatus, "phase") //nolint:errcheck
+
+		if phase != string(v1.PodRunning) {
+			e.Findings = append(e.Findings, Finding{
+				Check:       CheckControlPlaneHealth,
+				Node:        node,
+				Component:   id,
+				Message:     fmt.Sprintf("%s static pod is in phase %q", id, phase),
+				Remediation: "fix the control plane health before upgrading",
+			})
+
+			continue
+		}
+
+		if !podReady(podStatus) {
+			var endpoint string
+
+			if len(pod.Spec.Containers) > 0 {
+				endpoint = healthEndpoint(id, compatibility.VersionFromImageRef(pod.Spec.Containers[0].Image))
+			}
+
+			e.Findings = append(e.Findings, Finding{
+				Check:       CheckControlPlaneHealth,
+				Node:        node,
+				Component:   id,
+				Message:     fmt.Sprintf("%s static pod is not ready (%s health check is failing)", id, endpoint),
+				Remediation: "fix the control plane health before upgrading",
+			})
+		}
+	}
+
+	return nil
+}
+
+// healthEndpoint returns the readiness endpoint of the control plane component for the version.
+func healthEndpoint(id string, version compatibility.Version) string {
+	switch id {
+	case k8s.SchedulerID:
+		return version.KubeSchedulerHealthReadinessEndpoint()
+	case k8s.ControllerManagerID:
+		return kubeControllerManagerHealthEndpoint
+	default:
+		return "/readyz"
+	}
+}
+
+// podReady returns true if the pod status has the Ready condition set to True.
+func podReady(podStatus map[string]any) bool {
+	conditions, _, _ := unstructured.NestedSlice(podStatus, "conditions") //nolint:errcheck
+
+	for _, condition := range conditions {
+		condition, ok := condition.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if condition["type"] == string(v1.PodReady) {
+			return condition["status"] == string(v1.ConditionTrue)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,141 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	kubeProxyID = "kube-proxy"
+	// kubeProxyConfigKey is the key of the KubeProxyConfiguration in the kube-proxy ConfigMap (kubeadm layout).
+	kubeProxyConfigKey = "config.conf"
+)
+
+// nftablesGAVersion is the version nftables proxy mode is generally available.
+var nftablesGAVersion = semver.MustParse("1.33.0")
+
+// PopulateKubeProxy populates the removed flags and feature gates of the kube-proxy DaemonSet and its ConfigMap configuration.
+func (e *ComponentRemovedItemsError) PopulateKubeProxy(ctx context.Context, clientset kubernetes.Interface, removedFlags, removedFeatureGates []string) error {
+	args, config, found, err := kubeProxyConfiguration(ctx, clientset)
+	if err != nil || !found {
+		return err
+	}
+
+	e.PopulateRemovedCLIFlags("", kubeProxyID, args, removedFlags)
+	e.PopulateRemovedFeatureGates("", kubeProxyID, args, removedFeatureGates)
+
+	if config != nil {
+		e.populateConfigFeatureGates("", kubeProxyID, config, removedFeatureGates)
+	}
+
+	return nil
+}
+
+// PopulateKubeProxyMode reports the kube-proxy iptables proxy mode as an informational finding
+// when nftables mode is available in the upgrade version.
+func (e *ComponentRemovedItemsError) PopulateKubeProxyMode(ctx context.Context, clientset kubernetes.Interface, path *Path) error {
+	if path.to.LT(nftablesGAVersion) {
+		return nil
+	}
+
+	args, config, found, err := kubeProxyConfiguration(ctx, clientset)
+	if err != nil || !found {
+		return err
+	}
+
+	mode := flagValue(args, "proxy-mode")
+
+	if mode == "" {
+		mode, _, _ = unstructured.NestedString(config, "mode") //nolint:errcheck
+	}
+
+	if mode != "" && mode != "iptables" {
+		return nil
+	}
+
+	e.Findings = append(e.Findings, Finding{
+		Check:     CheckKubeProxyMode,
+		Component: kubeProxyID,
+		Message: fmt.Sprintf("kube-proxy uses iptables mode, nftables mode is generally available since %d.%d",
+			nftablesGAVersion.Major, nftablesGAVersion.Minor),
+		Remediation: "consider switching kube-proxy to nftables mode (--proxy-mode=nftables)",
+		DocURL:      "https://kubernetes.io/docs/reference/networking/virtual-ips/#proxy-mode-nftables",
+		Severity:    SeverityInfo,
+	})
+
+	return nil
+}
+
+// kubeProxyConfiguration returns the kube-proxy command line from the DaemonSet and the configuration from the ConfigMap.
+//
+// If kube-proxy is not deployed (or it can't be accessed), found is false.
+func kubeProxyConfiguration(ctx context.Context, clientset kubernetes.Interface) (args []string, config map[string]any, found bool, err error) {
+	ds, err := clientset.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(ctx, kubeProxyID, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+			// kube-proxy is disabled or replaced
+			return nil, nil, false, nil
+		}
+
+		return nil, nil, false, fmt.Errorf("error getting kube-proxy DaemonSet: %w", err)
+	}
+
+	for _, container := range ds.Spec.Template.Spec.Containers {
+		if container.Name == kubeProxyID || len(ds.Spec.Template.Spec.Containers) == 1 {
+			args = append(append(args, container.Command...), container.Args...)
+		}
+	}
+
+	cm, err := clientset.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(ctx, kubeProxyID, metav1.GetOptions{})
+
+	switch {
+	case apierrors.IsNotFound(err), apierrors.IsForbidden(err):
+	case err != nil:
+		return nil, nil, false, fmt.Errorf("error getting kube-proxy ConfigMap: %w", err)
+	default:
+		if config, err = kubeProxyConfig(cm); err != nil {
+			return nil, nil, false, err
+		}
+	}
+
+	return args, config, true, nil
+}
+
+func kubeProxyConfig(cm *v1.ConfigMap) (map[string]any, error) {
+	var config map[string]any
+
+	data, ok := cm.Data[kubeProxyConfigKey]
+	if !ok {
+		return config, nil
+	}
+
+	if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+		return nil, fmt.Errorf("error parsing kube-proxy configuration: %w", err)
+	}
+
+	return config, nil
+}
+
+// flagValue returns the value of the flag in the form of --name=value.
+func flagValue(args []string, name string) string {
+	for _, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "--"+name+"="); ok {
+			return value
+		}
+	}
+
+	return ""
+}
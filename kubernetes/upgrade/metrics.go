@@ -0,0 +1,172 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const requestedDeprecatedAPIsMetric = "apiserver_requested_deprecated_apis"
+
+// requestedDeprecatedAPI is a deprecated API version requested by the clients, as reported by the kube-apiserver metrics.
+type requestedDeprecatedAPI struct {
+	Group          string
+	Version        string
+	Resource       string
+	Subresource    string
+	RemovedRelease string
+}
+
+// String implements fmt.Stringer.
+func (api requestedDeprecatedAPI) String() string {
+	resource := api.Resource
+
+	if api.Subresource != "" {
+		resource += "/" + api.Subresource
+	}
+
+	if api.Group == "" {
+		return resource + "." + api.Version
+	}
+
+	return resource + "." + api.Version + "." + api.Group
+}
+
+// PopulateRequestedDeprecatedAPIs populates the deprecated API versions which are actively requested by the clients.
+//
+// The kube-apiserver reports the deprecated API versions requested since its start via the apiserver_requested_deprecated_apis metric.
+// APIs removed in the upgrade version (or earlier) are reported as errors, other deprecated APIs as warnings.
+// The metric doesn't identify the clients (user agents), the audit log should be used to find them.
+//
+// The metrics are scraped from the API server endpoint the config points to, so with multiple
+// API server instances behind a load balancer only one of them is checked.
+func (e *ComponentRemovedItemsError) PopulateRequestedDeprecatedAPIs(ctx context.Context, k8sConfig *rest.Config, path *Path) error {
+	if k8sConfig == nil {
+		return nil
+	}
+
+	clientset, err := kubernetes.NewForConfig(k8sConfig)
+	if err != nil {
+		return fmt.Errorf("error building kubernetes client: %w", err)
+	}
+
+	data, err := clientset.Discovery().RESTClient().Get().AbsPath("/metrics").DoRaw(ctx)
+	if err != nil {
+		if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+			// metrics are not accessible, skip the check
+			return nil
+		}
+
+		return fmt.Errorf("error fetching kube-apiserver metrics: %w", err)
+	}
+
+	apis, err := parseRequestedDeprecatedAPIs(data)
+	if err != nil {
+		return err
+	}
+
+	for _, api := range apis {
+		severity := SeverityWarning
+		message := fmt.Sprintf("deprecated API %s is requested by the clients", api)
+
+		if api.RemovedRelease != "" {
+			message += ", it will be removed in " + api.RemovedRelease
+
+			if removed, err := semver.ParseTolerant(api.RemovedRelease); err == nil &&
+				(removed.Major < path.to.Major || removed.Major == path.to.Major && removed.Minor <= path.to.Minor) {
+				severity = SeverityError
+			}
+		}
+
+		e.Findings = append(e.Findings, Finding{
+			Check:       CheckRequestedDeprecatedAPIs,
+			Component:   k8s.APIServerID,
+			Message:     message,
+			Remediation: "find the clients via the audit log and update them to the supported API version",
+			DocURL:      deprecationGuideURL,
+			Severity:    severity,
+		})
+	}
+
+	return nil
+}
+
+// parseRequestedDeprecatedAPIs parses the apiserver_requested_deprecated_apis metric in the Prometheus text format.
+func parseRequestedDeprecatedAPIs(data []byte) ([]requestedDeprecatedAPI, error) {
+	var apis []requestedDeprecatedAPI
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if !strings.HasPrefix(line, requestedDeprecatedAPIsMetric+"{") {
+			continue
+		}
+
+		labelsEnd := strings.LastIndex(line, "}")
+		if labelsEnd == -1 {
+			return nil, fmt.Errorf("malformed metric line %q", line)
+		}
+
+		value := strings.TrimSpace(line[labelsEnd+1:])
+		if value == "0" {
+			continue
+		}
+
+		labels := parseMetricLabels(line[len(requestedDeprecatedAPIsMetric)+1 : labelsEnd])
+
+		apis = append(apis, requestedDeprecatedAPI{
+			Group:          labels["group"],
+			Version:        labels["version"],
+			Resource:       labels["resource"],
+			Subresource:    labels["subresource"],
+			RemovedRelease: labels["removed_release"],
+		})
+	}
+
+	return apis, scanner.Err()
+}
+
+// parseMetricLabels parses the label set in the form of name="value",name="value".
+func parseMetricLabels(s string) map[string]string {
+	labels := map[string]string{}
+
+	for s != "" {
+		name, rest, ok := strings.Cut(s, "=\"")
+		if !ok {
+			break
+		}
+
+		var value strings.Builder
+
+		i := 0
+
+		for ; i < len(rest) && rest[i] != '"'; i++ {
+			if rest[i] == '\\' && i+1 < len(rest) {
+				i++
+			}
+
+			value.WriteByte(rest[i])
+		}
+
+		labels[strings.TrimSpace(name)] = value.String()
+
+		s = strings.TrimPrefix(rest[min(i+1, len(rest)):], ",")
+	}
+
+	return labels
+}
@@ -0,0 +1,98 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blang/semver/v4"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/siderolabs/talos/pkg/machinery/client"
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// kernelRequirement is the minimum kernel version expected by Kubernetes starting with some version.
+type kernelRequirement struct {
+	since   semver.Version
+	kernel  semver.Version
+	message string
+	docURL  string
+}
+
+// kernelRequirements are checked for the nodes in the order they are defined.
+var kernelRequirements = []kernelRequirement{
+	{
+		since:   semver.MustParse("1.31.0"),
+		kernel:  semver.MustParse("5.8.0"),
+		message: "cgroup v1 support is in maintenance mode since Kubernetes 1.31, cgroup v2 requires Linux kernel 5.8 or later",
+		docURL:  "https://kubernetes.io/docs/concepts/architecture/cgroups/",
+	},
+}
+
+// PopulateNodeOSRequirements validates the operating system of the node against the requirements of the upgrade version.
+//
+// The node information is taken from the Kubernetes Node status, as reported by kubelet.
+func (e *ComponentRemovedItemsError) PopulateNodeOSRequirements(node string, info v1.NodeSystemInfo, path *Path) {
+	if info.OperatingSystem != "" && info.OperatingSystem != "linux" {
+		return
+	}
+
+	kernel, err := semver.ParseTolerant(info.KernelVersion)
+	if err != nil {
+		return
+	}
+
+	// ignore the pre-release part (e.g. 6.6.58-talos), as it is not a pre-release of the kernel
+	kernel = semver.Version{Major: kernel.Major, Minor: kernel.Minor, Patch: kernel.Patch}
+
+	target := semver.Version{Major: path.to.Major, Minor: path.to.Minor}
+
+	for _, requirement := range kernelRequirements {
+		if target.LT(requirement.since) || kernel.GTE(requirement.kernel) {
+			continue
+		}
+
+		e.Findings = append(e.Findings, Finding{
+			Check:       CheckNodeOS,
+			Node:        node,
+			Component:   k8s.KubeletID,
+			Message:     fmt.Sprintf("node kernel %s is older than %s: %s", info.KernelVersion, requirement.kernel, requirement.message),
+			Remediation: fmt.Sprintf("upgrade the node operating system to a version with Linux kernel %s or later before upgrading kubelet", requirement.kernel),
+			DocURL:      requirement.docURL,
+			Severity:    SeverityWarning,
+		})
+	}
+}
+
+// populateNodeOSRequirements checks the node operating system, the Kubernetes node name is taken from the Talos resources.
+func (checks *Checks) populateNodeOSRequirements(ctx context.Context, clientset kubernetes.Interface, report *ComponentRemovedItemsError, node string) error {
+	nodename, err := safe.StateGet[*k8s.Nodename](client.WithNode(ctx, node), checks.state, k8s.NewNodename(k8s.NamespaceName, k8s.NodenameID).Metadata())
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	k8sNode, err := clientset.CoreV1().Nodes().Get(ctx, nodename.TypedSpec().Nodename, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+			return nil
+		}
+
+		return fmt.Errorf("error getting node %q: %w", nodename.TypedSpec().Nodename, err)
+	}
+
+	report.PopulateNodeOSRequirements(node, k8sNode.Status.NodeInfo, checks.path)
+
+	return nil
+}
@@ -0,0 +1,150 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+	"google.golang.org/grpc/metadata"
+)
+
+// NodeConfig is the configuration of the Kubernetes components on a node, e.g. extracted from the machine config.
+type NodeConfig struct {
+	// Args are the component flags (e.g. --feature-gates=...) by component (kube-apiserver, kube-controller-manager, kube-scheduler, kubelet).
+	Args map[string][]string
+	// KubeletConfig is the kubelet configuration (KubeletConfiguration fields).
+	KubeletConfig map[string]any
+	// KubeletCredentialProviderConfig is the kubelet credential provider configuration.
+	KubeletCredentialProviderConfig map[string]any
+	// EncryptionConfig is the kube-apiserver encryption provider configuration (EncryptionConfiguration), if it is not managed by Talos.
+	EncryptionConfig map[string]any
+	// Node is the name (or address) of the node reported in the findings.
+	Node string
+	// ControlPlane is true if the node runs the control plane components.
+	ControlPlane bool
+}
+
+// NewOfflineChecks initializes Checks which validate the component configuration without a live cluster.
+//
+// Only the checks of the component configuration are run (e.g. removed flags and feature gates),
+// the checks which need the Talos or Kubernetes API are skipped.
+func NewOfflineChecks(path *Path, nodes []NodeConfig, logFunc func(string, ...any), opts ...ChecksOption) (*Checks, error) {
+	st := offlineState{
+		State: state.WrapCore(namespaced.NewState(inmem.Build)),
+		nodes: make(map[string]state.State, len(nodes)),
+	}
+
+	var controlPlaneNodes, workerNodes []string
+
+	encryptionConfigs := map[string]map[string]any{}
+
+	for _, node := range nodes {
+		if _, ok := st.nodes[node.Node]; ok {
+			return nil, fmt.Errorf("duplicate node %q", node.Node)
+		}
+
+		nodeState, err := buildOfflineNodeState(node)
+		if err != nil {
+			return nil, fmt.Errorf("error building node %q state: %w", node.Node, err)
+		}
+
+		st.nodes[node.Node] = nodeState
+
+		if node.ControlPlane {
+			controlPlaneNodes = append(controlPlaneNodes, node.Node)
+			encryptionConfigs[node.Node] = node.EncryptionConfig
+		} else {
+			workerNodes = append(workerNodes, node.Node)
+		}
+	}
+
+	checks, err := NewChecks(path, st, nil, controlPlaneNodes, workerNodes, logFunc, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	checks.offline = true
+	checks.encryptionConfigs = encryptionConfigs
+
+	return checks, nil
+}
+
+// buildOfflineNodeState creates the Talos resources the checks read from the node configuration.
+func buildOfflineNodeState(node NodeConfig) (state.State, error) {
+	ctx := context.Background()
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	if node.ControlPlane {
+		for _, id := range []string{k8s.APIServerID, k8s.ControllerManagerID, k8s.SchedulerID} {
+			args, ok := node.Args[id]
+			if !ok {
+				continue
+			}
+
+			staticPod := k8s.NewStaticPod(k8s.NamespaceName, id)
+			staticPod.TypedSpec().Pod = map[string]any{
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{
+							"name":    id,
+							"command": append([]string{"/usr/local/bin/" + id}, args...),
+						},
+					},
+				},
+			}
+
+			if err := st.Create(ctx, staticPod); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	kubeletSpec := k8s.NewKubeletSpec(k8s.NamespaceName, k8s.KubeletID)
+	kubeletSpec.TypedSpec().Args = node.Args[k8s.KubeletID]
+	kubeletSpec.TypedSpec().Config = node.KubeletConfig
+	kubeletSpec.TypedSpec().CredentialProviderConfig = node.KubeletCredentialProviderConfig
+
+	if err := st.Create(ctx, kubeletSpec); err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
+
+// offlineState dispatches the reads to the state of the node set in the context (see client.WithNode).
+type offlineState struct {
+	state.State
+
+	nodes map[string]state.State
+}
+
+func (st offlineState) nodeState(ctx context.Context) state.State {
+	md, _ := metadata.FromOutgoingContext(ctx)
+
+	for node, nodeState := range st.nodes {
+		if slices.Contains(md.Get("node"), node) {
+			return nodeState
+		}
+	}
+
+	return st.State
+}
+
+// Get implements state.State.
+func (st offlineState) Get(ctx context.Context, ptr resource.Pointer, opts ...state.GetOption) (resource.Resource, error) {
+	return st.nodeState(ctx).Get(ctx, ptr, opts...)
+}
+
+// List implements state.State.
+func (st offlineState) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	return st.nodeState(ctx).List(ctx, kind, opts...)
+}
@@ -54,39 +54,150 @@ func (p *Path) String() string {
 	return fmt.Sprintf("%d.%d->%d.%d", p.from.Major, p.from.Minor, p.to.Major, p.to.Minor)
 }
 
+// IsDowngrade returns true if the target version is older than the source one.
+func (p *Path) IsDowngrade() bool {
+	return p.to.LT(p.from)
+}
+
+// IsMinorDowngrade returns true if the target version is an older minor (or major) version than the source one.
+//
+// Patch-level rollbacks (e.g. 1.30.5 -> 1.30.3) are not minor downgrades.
+func (p *Path) IsMinorDowngrade() bool {
+	return p.to.Major < p.from.Major || (p.to.Major == p.from.Major && p.to.Minor < p.from.Minor)
+}
+
+// ValidateOptions configures Path.Validate.
+type ValidateOptions struct {
+	// AllowMinorDowngrade allows to roll back to the previous minor version.
+	AllowMinorDowngrade bool
+}
+
+// ValidateOption modifies ValidateOptions.
+type ValidateOption func(*ValidateOptions)
+
+// WithAllowMinorDowngrade allows to roll back to the previous minor version.
+func WithAllowMinorDowngrade() ValidateOption {
+	return func(o *ValidateOptions) {
+		o.AllowMinorDowngrade = true
+	}
+}
+
+// Validate checks that the upgrade path can be performed.
+//
+// Patch-level rollbacks are always allowed, minor downgrades are rejected unless explicitly allowed,
+// and even then only a rollback to the previous minor version is allowed.
+func (p *Path) Validate(opts ...ValidateOption) error {
+	var options ValidateOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if p.IsMinorDowngrade() {
+		if !options.AllowMinorDowngrade {
+			return fmt.Errorf("downgrade %s is not allowed", p)
+		}
+
+		if p.to.Major != p.from.Major || p.from.Minor-p.to.Minor > 1 {
+			return fmt.Errorf("downgrade %s is not supported, only rollback to the previous minor version is supported", p)
+		}
+
+		return nil
+	}
+
+	if !p.IsSupported() {
+		return fmt.Errorf("upgrade path %s is not supported", p)
+	}
+
+	return nil
+}
+
+// Supported Kubernetes versions range (minor versions).
+const (
+	MinSupportedVersion = "1.19"
+	MaxSupportedVersion = "1.34"
+)
+
+var (
+	minSupportedVersion = semver.MustParse(MinSupportedVersion + ".0")
+	maxSupportedVersion = semver.MustParse(MaxSupportedVersion + ".0")
+)
+
+// SupportedVersions returns the list of supported Kubernetes minor versions (e.g. "1.30"), from the oldest to the newest one.
+func SupportedVersions() []string {
+	versions := make([]string, 0, maxSupportedVersion.Minor-minSupportedVersion.Minor+1)
+
+	for minor := minSupportedVersion.Minor; minor <= maxSupportedVersion.Minor; minor++ {
+		versions = append(versions, fmt.Sprintf("%d.%d", minSupportedVersion.Major, minor))
+	}
+
+	return versions
+}
+
+// isSupportedVersion returns true if the minor version is within the supported range.
+func isSupportedVersion(v semver.Version) bool {
+	return v.Major == minSupportedVersion.Major && v.Minor >= minSupportedVersion.Minor && v.Minor <= maxSupportedVersion.Minor
+}
+
 // IsSupported returns true if the upgrade path is supported.
+//
+// Both versions should be within the supported range, and the upgrade should stay
+// within the same minor version or move to the next one.
 func (p *Path) IsSupported() bool {
-	switch p.String() {
-	case
-		"1.19->1.19",
-		"1.19->1.20",
-		"1.20->1.20",
-		"1.20->1.21",
-		"1.21->1.21",
-		"1.21->1.22",
-		"1.22->1.22",
-		"1.22->1.23",
-		"1.23->1.23",
-		"1.23->1.24",
-		"1.24->1.24",
-		"1.24->1.25",
-		"1.25->1.25",
-		"1.25->1.26",
-		"1.26->1.26",
-		"1.26->1.27",
-		"1.27->1.27",
-		"1.27->1.28",
-		"1.28->1.28",
-		"1.28->1.29",
-		"1.29->1.29",
-		"1.29->1.30",
-		"1.30->1.30",
-		"1.30->1.31",
-		"1.31->1.31",
-		"1.31->1.32",
-		"1.32->1.32":
-		return true
+	if !isSupportedVersion(p.from) || !isSupportedVersion(p.to) {
+		return false
+	}
+
+	return p.to.Minor == p.from.Minor || p.to.Minor == p.from.Minor+1
+}
+
+// PlanPath returns the sequence of upgrade paths (hops) from one version to another, each hop upgrading to the next minor version.
+//
+// Intermediate versions are returned as the first (.0) patch release of the minor version, callers might prefer the latest
+// patch release of each intermediate minor version instead.
+// An error is returned if the target version is older than the source one, or if any of the hops is not supported.
+func PlanPath(fromVersion, toVersion string) ([]*Path, error) {
+	path, err := NewPath(fromVersion, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if path.from.Major != path.to.Major {
+		return nil, fmt.Errorf("upgrade across major versions is not supported: %s", path)
+	}
+
+	if path.to.Minor < path.from.Minor {
+		return nil, fmt.Errorf("upgrade path %s is a downgrade", path)
+	}
+
+	var hops []*Path
+
+	current := path.fromVersion
+
+	for minor := path.from.Minor + 1; minor < path.to.Minor; minor++ {
+		next := fmt.Sprintf("%d.%d.0", path.to.Major, minor)
+
+		hop, err := NewPath(current, next)
+		if err != nil {
+			return nil, err
+		}
+
+		hops = append(hops, hop)
+		current = next
+	}
+
+	hop, err := NewPath(current, path.toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	hops = append(hops, hop)
+
+	for _, hop := range hops {
+		if !hop.IsSupported() {
+			return nil, fmt.Errorf("unsupported upgrade path %s", hop)
+		}
 	}
 
-	return false
+	return hops, nil
 }
@@ -23,4 +23,171 @@ func TestPath(t *testing.T) {
 	assert.Equal(t, "1.19->1.20", p.String())
 
 	assert.True(t, p.IsSupported())
+
+	p, err = upgrade.NewPath("1.33.2", "1.34.0")
+	require.NoError(t, err)
+
+	assert.True(t, p.IsSupported())
+
+	p, err = upgrade.NewPath("1.34.0", "1.35.0")
+	require.NoError(t, err)
+
+	assert.False(t, p.IsSupported())
+
+	p, err = upgrade.NewPath("1.18.3", "1.19.0")
+	require.NoError(t, err)
+
+	assert.False(t, p.IsSupported())
+
+	p, err = upgrade.NewPath("1.30.0", "1.32.0")
+	require.NoError(t, err)
+
+	assert.False(t, p.IsSupported())
+
+	p, err = upgrade.NewPath("1.30.5", "1.30.1")
+	require.NoError(t, err)
+
+	assert.True(t, p.IsSupported())
+
+	p, err = upgrade.NewPath("1.31.0", "1.30.0")
+	require.NoError(t, err)
+
+	assert.False(t, p.IsSupported())
+}
+
+func TestSupportedVersions(t *testing.T) {
+	versions := upgrade.SupportedVersions()
+
+	assert.Equal(t, upgrade.MinSupportedVersion, versions[0])
+	assert.Equal(t, upgrade.MaxSupportedVersion, versions[len(versions)-1])
+	assert.Contains(t, versions, "1.30")
+	assert.Len(t, versions, 16)
+
+	for _, from := range versions {
+		p, err := upgrade.NewPath(from+".0", from+".1")
+		require.NoError(t, err)
+
+		assert.True(t, p.IsSupported())
+	}
+}
+
+func TestPlanPath(t *testing.T) {
+	for _, test := range []struct {
+		from, to string
+
+		expected      []string
+		expectedError string
+	}{
+		{
+			from:     "1.30.1",
+			to:       "1.30.5",
+			expected: []string{"1.30.1->1.30.5"},
+		},
+		{
+			from:     "1.30.1",
+			to:       "1.31.2",
+			expected: []string{"1.30.1->1.31.2"},
+		},
+		{
+			from:     "v1.28.3",
+			to:       "v1.31.2",
+			expected: []string{"1.28.3->1.29.0", "1.29.0->1.30.0", "1.30.0->1.31.2"},
+		},
+		{
+			from:          "1.31.0",
+			to:            "1.29.0",
+			expectedError: "upgrade path 1.31->1.29 is a downgrade",
+		},
+		{
+			from:          "1.33.0",
+			to:            "1.35.0",
+			expectedError: "unsupported upgrade path 1.34->1.35",
+		},
+		{
+			from:          "1.33.0",
+			to:            "2.0.0",
+			expectedError: "upgrade across major versions is not supported: 1.33->2.0",
+		},
+	} {
+		t.Run(test.from+"->"+test.to, func(t *testing.T) {
+			hops, err := upgrade.PlanPath(test.from, test.to)
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			actual := make([]string, 0, len(hops))
+
+			for _, hop := range hops {
+				actual = append(actual, hop.FromVersion()+"->"+hop.ToVersion())
+			}
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestPathDowngrade(t *testing.T) {
+	for _, test := range []struct {
+		from, to string
+		opts     []upgrade.ValidateOption
+
+		expectedDowngrade      bool
+		expectedMinorDowngrade bool
+		expectedError          string
+	}{
+		{
+			from: "1.30.1",
+			to:   "1.31.0",
+		},
+		{
+			from:              "1.30.5",
+			to:                "1.30.3",
+			expectedDowngrade: true,
+		},
+		{
+			from:                   "1.31.0",
+			to:                     "1.30.5",
+			expectedDowngrade:      true,
+			expectedMinorDowngrade: true,
+			expectedError:          "downgrade 1.31->1.30 is not allowed",
+		},
+		{
+			from:                   "1.31.0",
+			to:                     "1.30.5",
+			opts:                   []upgrade.ValidateOption{upgrade.WithAllowMinorDowngrade()},
+			expectedDowngrade:      true,
+			expectedMinorDowngrade: true,
+		},
+		{
+			from:                   "1.31.0",
+			to:                     "1.29.5",
+			opts:                   []upgrade.ValidateOption{upgrade.WithAllowMinorDowngrade()},
+			expectedDowngrade:      true,
+			expectedMinorDowngrade: true,
+			expectedError:          "downgrade 1.31->1.29 is not supported, only rollback to the previous minor version is supported",
+		},
+		{
+			from:          "1.30.0",
+			to:            "1.32.0",
+			expectedError: "upgrade path 1.30->1.32 is not supported",
+		},
+	} {
+		t.Run(test.from+"->"+test.to, func(t *testing.T) {
+			p, err := upgrade.NewPath(test.from, test.to)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedDowngrade, p.IsDowngrade())
+			assert.Equal(t, test.expectedMinorDowngrade, p.IsMinorDowngrade())
+
+			if test.expectedError != "" {
+				assert.EqualError(t, p.Validate(test.opts...), test.expectedError)
+			} else {
+				assert.NoError(t, p.Validate(test.opts...))
+			}
+		})
+	}
 }
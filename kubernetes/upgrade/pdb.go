@@ -0,0 +1,123 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PopulateDrainBlockers populates the PodDisruptionBudgets which would block node drains during the upgrade.
+//
+// A PodDisruptionBudget blocks the drain if it allows no disruptions while selecting some pods,
+// e.g. if the selected pods are not healthy, or the budget requires all replicas to be available.
+// Blockers are reported as warnings.
+func (e *ComponentRemovedItemsError) PopulateDrainBlockers(ctx context.Context, clientset kubernetes.Interface) error {
+	pdbs, err := clientset.PolicyV1().PodDisruptionBudgets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("error listing pod disruption budgets: %w", err)
+	}
+
+	for _, pdb := range pdbs.Items {
+		if pdb.Status.DisruptionsAllowed > 0 || pdb.Status.ExpectedPods == 0 {
+			continue
+		}
+
+		workloads, err := pdbWorkloads(ctx, clientset, &pdb)
+		if err != nil {
+			return err
+		}
+
+		reason := "allows no disruptions"
+
+		if pdb.Status.CurrentHealthy == 0 {
+			reason = "selects no healthy pods"
+		}
+
+		message := fmt.Sprintf("PodDisruptionBudget %s/%s %s, it blocks node drains", pdb.Namespace, pdb.Name, reason)
+
+		if len(workloads) > 0 {
+			message += fmt.Sprintf(" (workloads: %s)", strings.Join(workloads, ", "))
+		}
+
+		e.Findings = append(e.Findings, Finding{
+			Check:       CheckDrainBlockers,
+			Message:     message,
+			Remediation: "scale up the workloads, fix unhealthy pods or relax the PodDisruptionBudget before upgrading",
+			DocURL:      "https://kubernetes.io/docs/tasks/run-application/configure-pdb/",
+			Severity:    SeverityWarning,
+		})
+	}
+
+	return nil
+}
+
+// pdbWorkloads returns the sorted list of workloads (controllers) of the pods selected by the PodDisruptionBudget.
+func pdbWorkloads(ctx context.Context, clientset kubernetes.Interface, pdb *policyv1.PodDisruptionBudget) ([]string, error) {
+	var workloads []string
+
+	if pdb.Spec.Selector == nil {
+		return workloads, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing PodDisruptionBudget %s/%s selector: %w", pdb.Namespace, pdb.Name, err)
+	}
+
+	pods, err := clientset.CoreV1().Pods(pdb.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("error listing pods: %w", err)
+	}
+
+	for _, pod := range pods.Items {
+		workload, err := podWorkload(ctx, clientset, &pod)
+		if err != nil {
+			return nil, err
+		}
+
+		workloads = append(workloads, workload)
+	}
+
+	slices.Sort(workloads)
+
+	return slices.Compact(workloads), nil
+}
+
+// podWorkload returns the workload (controller) of the pod as Kind/name, resolving ReplicaSets to their Deployments.
+func podWorkload(ctx context.Context, clientset kubernetes.Interface, pod *v1.Pod) (string, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "Pod/" + pod.Name, nil
+	}
+
+	if owner.Kind == "ReplicaSet" {
+		rs, err := clientset.AppsV1().ReplicaSets(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return "", fmt.Errorf("error getting ReplicaSet: %w", err)
+		default:
+			if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil {
+				owner = rsOwner
+			}
+		}
+	}
+
+	return owner.Kind + "/" + owner.Name, nil
+}
@@ -0,0 +1,112 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/siderolabs/gen/channel"
+)
+
+// CheckEventType is the type of the CheckEvent.
+type CheckEventType int
+
+// Check event types.
+const (
+	// CheckEventStarted is sent before the check runs.
+	CheckEventStarted CheckEventType = iota
+	// CheckEventNode is sent when the check starts checking a node, Node is set.
+	CheckEventNode
+	// CheckEventFinished is sent when the check is done, Duration and Findings are set, Err is set if the check failed.
+	CheckEventFinished
+)
+
+// CheckEvent describes the progress of the checks.
+type CheckEvent struct {
+	Err error
+	// Check is the check identifier (built-in Check* constants or custom check name).
+	Check string
+	// Description is a human-readable description of the check.
+	Description string
+	Node        string
+	Duration    time.Duration
+	// Findings is the number of findings reported by the check.
+	Findings int
+	Type     CheckEventType
+}
+
+// WithProgress sends the progress of the checks to the channel.
+//
+// The channel should be drained while the checks are running, Run aborts if the context is canceled while sending.
+func WithProgress(eventCh chan<- CheckEvent) ChecksOption {
+	return func(o *ChecksOptions) {
+		o.ProgressCh = eventCh
+	}
+}
+
+// progress sends the event to the progress channel, if configured.
+func (checks *Checks) progress(ctx context.Context, event CheckEvent) error {
+	if checks.options.ProgressCh == nil {
+		return nil
+	}
+
+	if !channel.SendWithContext(ctx, checks.options.ProgressCh, event) {
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// runCheck runs the check function reporting the progress.
+func (checks *Checks) runCheck(ctx context.Context, name, description string, report *ComponentRemovedItemsError, fn func(ctx context.Context) error) error {
+	checks.log("%s", description)
+
+	if err := checks.progress(ctx, CheckEvent{Type: CheckEventStarted, Check: name, Description: description}); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	before := report.count()
+
+	checkCtx, cancel := withTimeout(ctx, checks.options.CheckTimeout)
+	err := fn(checkCtx)
+
+	if timedOut(ctx, checkCtx, err) {
+		report.Findings = append(report.Findings, Finding{
+			Check:       name,
+			Message:     fmt.Sprintf("check did not complete in %s: %s", checks.options.CheckTimeout, err),
+			Remediation: "check the cluster health, increase the check timeout or skip the check",
+		})
+
+		err = nil
+	}
+
+	cancel()
+
+	if progressErr := checks.progress(ctx, CheckEvent{
+		Type:        CheckEventFinished,
+		Check:       name,
+		Description: description,
+		Duration:    time.Since(start),
+		Findings:    report.count() - before,
+		Err:         err,
+	}); progressErr != nil && err == nil {
+		err = progressErr
+	}
+
+	return err
+}
+
+// nodeProgress reports that the check started checking the node.
+func (checks *Checks) nodeProgress(ctx context.Context, check, node string) error {
+	return checks.progress(ctx, CheckEvent{Type: CheckEventNode, Check: check, Node: node})
+}
+
+// count returns the number of the reported items and findings.
+func (e *ComponentRemovedItemsError) count() int {
+	return len(e.AdmissionFlags) + len(e.FeatureGates) + len(e.CLIFlags) + len(e.ConfigFields) + len(e.APIResources) + len(e.Findings)
+}
@@ -0,0 +1,127 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/siderolabs/talos/pkg/machinery/client"
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+	"github.com/siderolabs/talos/pkg/machinery/resources/perf"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// minAvailableMemory is the memory (in bytes) expected to be available on the control plane nodes
+// to pull and start the new control plane component images side by side with the running ones.
+const minAvailableMemory = 512 * 1024 * 1024
+
+// nodePressureConditions are the node conditions which prevent pulling the new images and starting the new pods.
+var nodePressureConditions = []v1.NodeConditionType{
+	v1.NodeDiskPressure,
+	v1.NodeMemoryPressure,
+}
+
+// PopulateNodeReadiness checks that the Kubernetes nodes are Ready, not cordoned and not under resource pressure.
+//
+// Free disk space is checked via the kubelet DiskPressure condition, which is reported when the image filesystem
+// is close to the eviction threshold.
+func (e *ComponentRemovedItemsError) PopulateNodeReadiness(ctx context.Context, clientset kubernetes.Interface) error {
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+			return nil
+		}
+
+		return fmt.Errorf("error listing nodes: %w", err)
+	}
+
+	for _, node := range nodes.Items {
+		conditions := map[v1.NodeConditionType]v1.NodeCondition{}
+
+		for _, condition := range node.Status.Conditions {
+			conditions[condition.Type] = condition
+		}
+
+		if ready, ok := conditions[v1.NodeReady]; !ok || ready.Status != v1.ConditionTrue {
+			message := fmt.Sprintf("node %s is not Ready", node.Name)
+
+			if ok && ready.Message != "" {
+				message += ": " + ready.Message
+			}
+
+			e.Findings = append(e.Findings, Finding{
+				Check:       CheckNodeReadiness,
+				Node:        node.Name,
+				Component:   k8s.KubeletID,
+				Message:     message,
+				Remediation: "fix the node or remove it from the cluster before upgrading",
+				Severity:    SeverityError,
+			})
+		}
+
+		for _, conditionType := range nodePressureConditions {
+			if condition, ok := conditions[conditionType]; ok && condition.Status == v1.ConditionTrue {
+				e.Findings = append(e.Findings, Finding{
+					Check:       CheckNodeReadiness,
+					Node:        node.Name,
+					Component:   k8s.KubeletID,
+					Message:     fmt.Sprintf("node %s reports %s, new component images might fail to be pulled or started", node.Name, conditionType),
+					Remediation: "free up the node resources before upgrading",
+					Severity:    SeverityError,
+				})
+			}
+		}
+
+		if node.Spec.Unschedulable {
+			e.Findings = append(e.Findings, Finding{
+				Check:       CheckNodeReadiness,
+				Node:        node.Name,
+				Component:   k8s.KubeletID,
+				Message:     fmt.Sprintf("node %s is cordoned", node.Name),
+				Remediation: fmt.Sprintf("uncordon the node with `kubectl uncordon %s` if it is not cordoned intentionally", node.Name),
+				Severity:    SeverityWarning,
+			})
+		}
+	}
+
+	return nil
+}
+
+// PopulateNodeCapacity checks that the control plane node has enough available memory for the upgrade.
+//
+// The memory stats are taken from the Talos resources.
+func (e *ComponentRemovedItemsError) PopulateNodeCapacity(ctx context.Context, st state.State, node string) error {
+	memory, err := safe.StateGet[*perf.Memory](client.WithNode(ctx, node), st, perf.NewMemory().Metadata())
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	// memory stats are in KiB, as in /proc/meminfo
+	available := memory.TypedSpec().MemAvailable * 1024
+
+	if memory.TypedSpec().MemTotal == 0 || available >= minAvailableMemory {
+		return nil
+	}
+
+	e.Findings = append(e.Findings, Finding{
+		Check:       CheckNodeCapacity,
+		Node:        node,
+		Message:     fmt.Sprintf("control plane node has %d MiB of memory available, at least %d MiB is recommended for the upgrade", available>>20, minAvailableMemory>>20),
+		Remediation: "free up the node memory or add more memory to the node before upgrading",
+		Severity:    SeverityWarning,
+	})
+
+	return nil
+}
@@ -0,0 +1,166 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+	"sigs.k8s.io/yaml"
+)
+
+// RemediationPatch is a Talos machine config strategic merge patch fixing the findings on a node.
+type RemediationPatch struct {
+	// Node is the node to apply the patch to, empty for the cluster-wide findings (e.g. kube-proxy),
+	// in that case the patch should be applied to all control plane nodes.
+	Node string `json:"node,omitempty" yaml:"node,omitempty"`
+	// Patch is the strategic merge patch contents.
+	Patch map[string]any `json:"patch" yaml:"patch"`
+	// Findings are the findings fixed by the patch.
+	Findings []Finding `json:"findings" yaml:"findings"`
+}
+
+// YAML returns the patch contents as YAML.
+func (p RemediationPatch) YAML() ([]byte, error) {
+	return yaml.Marshal(p.Patch)
+}
+
+// machineConfigArgsPath returns the machine config path of the component extra arguments.
+func machineConfigArgsPath(component string) []string {
+	switch component {
+	case k8s.APIServerID:
+		return []string{"cluster", "apiServer", "extraArgs"}
+	case k8s.ControllerManagerID:
+		return []string{"cluster", "controllerManager", "extraArgs"}
+	case k8s.SchedulerID:
+		return []string{"cluster", "scheduler", "extraArgs"}
+	case k8s.KubeletID:
+		return []string{"machine", "kubelet", "extraArgs"}
+	case kubeProxyID:
+		return []string{"cluster", "proxy", "extraArgs"}
+	default:
+		return nil
+	}
+}
+
+// isFeatureGateFinding returns true if the finding is fixed by removing the feature gate.
+func isFeatureGateFinding(finding Finding) bool {
+	return finding.Check == findingRemovedFeatureGate || finding.Check == findingLockedFeatureGate
+}
+
+// patchDelete returns the strategic merge patch directive to delete the key.
+func patchDelete() map[string]any {
+	return map[string]any{"$patch": "delete"}
+}
+
+// GenerateRemediationPatches generates Talos machine config patches removing the removed flags, removed or locked feature gates,
+// admission plugins and kubelet configuration fields reported by the findings (see ComponentRemovedItemsError.Report).
+//
+// Patches are grouped by node. Findings which can't be fixed by a machine config patch are ignored.
+func GenerateRemediationPatches(findings []Finding) []RemediationPatch {
+	patches := map[string]*RemediationPatch{}
+
+	// list flags (--feature-gates) might be affected by multiple findings, so they are collected first
+	type listFlag struct {
+		node, component, flag, value string
+	}
+
+	listFlags := map[listFlag][]string{}
+
+	for _, finding := range findings {
+		argsPath := machineConfigArgsPath(finding.Component)
+
+		var path []string
+
+		switch {
+		case finding.Item == "" || argsPath == nil:
+			continue
+		case finding.Check == findingRemovedFlag:
+			path = append(slices.Clone(argsPath), finding.Item)
+		case finding.Check == findingRemovedConfigField && finding.Component == k8s.KubeletID:
+			path = append([]string{"machine", "kubelet", "extraConfig"}, strings.Split(finding.Item, ".")...)
+		case isFeatureGateFinding(finding) && finding.FlagValue == "" && finding.Component == k8s.KubeletID:
+			path = []string{"machine", "kubelet", "extraConfig", "featureGates", finding.Item}
+		case isFeatureGateFinding(finding) && finding.FlagValue != "":
+			key := listFlag{node: finding.Node, component: finding.Component, flag: "feature-gates", value: finding.FlagValue}
+			listFlags[key] = append(listFlags[key], finding.Item)
+		case finding.Check == findingRemovedAdmissionPlugin && finding.FlagValue != "":
+			key := listFlag{node: finding.Node, component: finding.Component, flag: "enable-admission-plugins", value: finding.FlagValue}
+			listFlags[key] = append(listFlags[key], finding.Item)
+		default:
+			continue
+		}
+
+		patch := nodePatch(patches, finding.Node)
+		patch.Findings = append(patch.Findings, finding)
+
+		if path != nil {
+			setPatchValue(patch.Patch, path, patchDelete())
+		}
+	}
+
+	for key, items := range listFlags {
+		var remaining []string
+
+		for _, entry := range strings.Split(key.value, ",") {
+			name, _, _ := strings.Cut(entry, "=")
+
+			if !slices.Contains(items, name) {
+				remaining = append(remaining, entry)
+			}
+		}
+
+		var value any = patchDelete()
+
+		if len(remaining) > 0 {
+			value = strings.Join(remaining, ",")
+		}
+
+		setPatchValue(nodePatch(patches, key.node).Patch, append(slices.Clone(machineConfigArgsPath(key.component)), key.flag), value)
+	}
+
+	result := make([]RemediationPatch, 0, len(patches))
+
+	for _, patch := range patches {
+		result = append(result, *patch)
+	}
+
+	slices.SortFunc(result, func(a, b RemediationPatch) int {
+		return cmp.Compare(a.Node, b.Node)
+	})
+
+	return result
+}
+
+func nodePatch(patches map[string]*RemediationPatch, node string) *RemediationPatch {
+	patch, ok := patches[node]
+	if !ok {
+		patch = &RemediationPatch{
+			Node:  node,
+			Patch: map[string]any{},
+		}
+
+		patches[node] = patch
+	}
+
+	return patch
+}
+
+// setPatchValue sets the value in the nested patch map creating intermediate maps.
+func setPatchValue(patch map[string]any, path []string, value any) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := patch[key].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			patch[key] = next
+		}
+
+		patch = next
+	}
+
+	patch[path[len(path)-1]] = value
+}
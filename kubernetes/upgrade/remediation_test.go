@@ -0,0 +1,86 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/upgrade"
+)
+
+func TestGenerateRemediationPatches(t *testing.T) {
+	removedItems := upgrade.ComponentRemovedItemsError{
+		AdmissionFlags: []upgrade.ComponentItem{
+			{Node: "10.5.0.2", Component: "kube-apiserver", Value: "PodSecurityPolicy", FlagValue: "PodSecurityPolicy"},
+		},
+		FeatureGates: []upgrade.ComponentItem{
+			{Node: "10.5.0.2", Component: "kube-apiserver", Value: "CSIMigration", FlagValue: "CSIMigration=true,KMSv2=true,Foo=false"},
+			{Node: "10.5.0.2", Component: "kube-apiserver", Value: "KMSv2", FlagValue: "CSIMigration=true,KMSv2=true,Foo=false"},
+			{Node: "10.5.0.3", Component: "kubelet", Value: "ExpandCSIVolumes"},
+			{Component: "kube-proxy", Value: "CPUManager", FlagValue: "CPUManager=true"},
+		},
+		CLIFlags: []upgrade.ComponentItem{
+			{Node: "10.5.0.3", Component: "kubelet", Value: "container-runtime"},
+		},
+		ConfigFields: []upgrade.ComponentItem{
+			{Node: "10.5.0.3", Component: "kubelet", Value: "logging.options.text"},
+		},
+	}
+
+	report := removedItems.Report()
+
+	// findings which can't be fixed by a patch are ignored
+	report.Findings = append(report.Findings, upgrade.Finding{Check: "version-skew", Node: "10.5.0.3", Message: "kubelet is too old"})
+
+	patches := upgrade.GenerateRemediationPatches(report.Findings)
+	require.Len(t, patches, 3)
+
+	assert.Equal(t, "", patches[0].Node)
+	assert.Equal(t, map[string]any{
+		"cluster": map[string]any{
+			"proxy": map[string]any{
+				"extraArgs": map[string]any{
+					"feature-gates": map[string]any{"$patch": "delete"},
+				},
+			},
+		},
+	}, patches[0].Patch)
+
+	assert.Equal(t, "10.5.0.2", patches[1].Node)
+	assert.Len(t, patches[1].Findings, 3)
+	assert.Equal(t, map[string]any{
+		"cluster": map[string]any{
+			"apiServer": map[string]any{
+				"extraArgs": map[string]any{
+					"enable-admission-plugins": map[string]any{"$patch": "delete"},
+					"feature-gates":            "Foo=false",
+				},
+			},
+		},
+	}, patches[1].Patch)
+
+	assert.Equal(t, "10.5.0.3", patches[2].Node)
+
+	out, err := patches[2].YAML()
+	require.NoError(t, err)
+
+	assert.Equal(t, `machine:
+  kubelet:
+    extraArgs:
+      container-runtime:
+        $patch: delete
+    extraConfig:
+      featureGates:
+        ExpandCSIVolumes:
+          $patch: delete
+      logging:
+        options:
+          text:
+            $patch: delete
+`, string(out))
+}
@@ -0,0 +1,182 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+)
+
+// Checks of the findings reported for the component items.
+const (
+	findingRemovedAdmissionPlugin = "removed-admission-plugin"
+	findingRemovedFeatureGate     = "removed-feature-gate"
+	findingRemovedFlag            = "removed-flag"
+	findingRemovedConfigField     = "removed-config-field"
+	findingChangedAdmissionPlugin = "changed-admission-plugin"
+	findingCredentialProvider     = "kubelet-credential-provider"
+	findingEncryptionProvider     = "encryption-provider"
+	findingLockedFeatureGate      = "locked-feature-gate"
+)
+
+// Documentation links for the built-in findings.
+const (
+	deprecationGuideURL    = "https://kubernetes.io/docs/reference/using-api/deprecation-guide/"
+	removedFeatureGatesURL = "https://kubernetes.io/docs/reference/command-line-tools-reference/feature-gates-removed/"
+	admissionPluginsURL    = "https://kubernetes.io/docs/reference/access-authn-authz/admission-controllers/"
+)
+
+// knownRemediations are the suggested fixes for specific removed items, which need more than just removing them.
+var knownRemediations = map[string]string{
+	"PodSecurityPolicy":                  "migrate PodSecurityPolicy objects to Pod Security Admission",
+	"podsecuritypolicies.v1beta1.policy": "migrate PodSecurityPolicy objects to Pod Security Admission",
+	"SecurityContextDeny":                "remove SecurityContextDeny from --enable-admission-plugins, use Pod Security Admission or a policy engine instead",
+}
+
+// machineConfigArgs returns the machine config location of the component extra arguments.
+func machineConfigArgs(component string) string {
+	if path := machineConfigArgsPath(component); path != nil {
+		return strings.Join(path, ".")
+	}
+
+	return component + " arguments"
+}
+
+// remediation returns the known remediation for the item, or the fallback.
+func remediation(item, fallback string) string {
+	if known, ok := knownRemediations[item]; ok {
+		return known
+	}
+
+	return fallback
+}
+
+// Report is a machine-readable result of the upgrade checks.
+//
+// Report can be marshaled to JSON or YAML.
+type Report struct {
+	// Path is the upgrade path, e.g. 1.30->1.31.
+	Path     string    `json:"path,omitempty" yaml:"path,omitempty"`
+	Findings []Finding `json:"findings" yaml:"findings"`
+}
+
+// HasErrors returns true if the report contains findings which block the upgrade.
+func (r Report) HasErrors() bool {
+	return slices.ContainsFunc(r.Findings, func(f Finding) bool { return f.Severity == SeverityError })
+}
+
+// Report converts the removed items to the findings.
+func (e ComponentRemovedItemsError) Report() Report {
+	findings := []Finding{}
+
+	for _, item := range e.AdmissionFlags {
+		findings = append(findings, Finding{
+			Check:       findingRemovedAdmissionPlugin,
+			Item:        item.Value,
+			FlagValue:   item.FlagValue,
+			Node:        item.Node,
+			Component:   item.Component,
+			Message:     fmt.Sprintf("admission plugin %s is removed", item.Value),
+			Remediation: remediation(item.Value, fmt.Sprintf("remove %s from --enable-admission-plugins in %s", item.Value, machineConfigArgs(item.Component))),
+			DocURL:      admissionPluginsURL,
+		})
+	}
+
+	for _, item := range e.FeatureGates {
+		findings = append(findings, Finding{
+			Check:       findingRemovedFeatureGate,
+			Item:        item.Value,
+			FlagValue:   item.FlagValue,
+			Node:        item.Node,
+			Component:   item.Component,
+			Message:     fmt.Sprintf("feature gate %s is removed", item.Value),
+			Remediation: remediation(item.Value, fmt.Sprintf("remove %s from --feature-gates in %s", item.Value, featureGatesLocation(item))),
+			DocURL:      removedFeatureGatesURL,
+		})
+	}
+
+	for _, item := range e.CLIFlags {
+		findings = append(findings, Finding{
+			Check:       findingRemovedFlag,
+			Item:        item.Value,
+			FlagValue:   item.FlagValue,
+			Node:        item.Node,
+			Component:   item.Component,
+			Message:     fmt.Sprintf("flag --%s is removed", item.Value),
+			Remediation: remediation(item.Value, fmt.Sprintf("remove --%s from %s in the machine config", item.Value, machineConfigArgs(item.Component))),
+		})
+	}
+
+	for _, item := range e.ConfigFields {
+		findings = append(findings, Finding{
+			Check:       findingRemovedConfigField,
+			Item:        item.Value,
+			FlagValue:   item.FlagValue,
+			Node:        item.Node,
+			Component:   item.Component,
+			Message:     fmt.Sprintf("configuration field %s is removed", item.Value),
+			Remediation: remediation(item.Value, fmt.Sprintf("remove %s from machine.kubelet.extraConfig in the machine config", item.Value)),
+		})
+	}
+
+	for _, resource := range slices.Sorted(maps.Keys(e.APIResources)) {
+		findings = append(findings, Finding{
+			Check:       CheckRemovedAPIResources,
+			Component:   k8s.APIServerID,
+			Message:     fmt.Sprintf("%s is removed, %d object(s) found%s", resource, e.APIResources[resource], formatSamples(e.APIResourceSamples[resource])),
+			Remediation: remediation(resource, "migrate the manifests and clients to the supported API version"),
+			DocURL:      deprecationGuideURL,
+		})
+	}
+
+	findings = append(findings, e.Findings...)
+
+	return Report{Findings: findings}
+}
+
+// featureGatesLocation returns the machine config location the feature gate is set in.
+//
+// Feature gates might be set both via the flag and the component configuration (kubelet, kube-proxy).
+func featureGatesLocation(item ComponentItem) string {
+	location := machineConfigArgs(item.Component)
+
+	if item.Component == k8s.KubeletID {
+		location += " or machine.kubelet.extraConfig.featureGates"
+	}
+
+	return location
+}
+
+// writeRemediations renders the suggested fixes for the findings, skipping duplicates.
+func writeRemediations(w io.Writer, findings []Finding, grouped bool) {
+	var fixes []Finding
+
+	for _, finding := range findings {
+		if finding.Remediation == "" {
+			continue
+		}
+
+		fix := Finding{Node: finding.Node, Component: finding.Component, Remediation: finding.Remediation}
+
+		if !slices.Contains(fixes, fix) {
+			fixes = append(fixes, fix)
+		}
+	}
+
+	if len(fixes) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nNODE\tCOMPONENT\tSUGGESTED FIX\n") //nolint:errcheck
+
+	for _, fix := range groupFindings(fixes, grouped) {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", formatNodes(fix.Nodes), fix.Component, fix.Remediation) //nolint:errcheck
+	}
+}
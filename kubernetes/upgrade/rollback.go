@@ -0,0 +1,51 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import "fmt"
+
+// rollbackCheck describes what breaks when rolling back to the previous minor version.
+type rollbackCheck struct {
+	message     string
+	remediation string
+}
+
+// rollbackChecks are reported for every minor version rollback, %[1]s is the source and %[2]s is the target version.
+var rollbackChecks = []rollbackCheck{
+	{
+		message:     "objects stored using API versions introduced in %[1]s can't be read by kube-apiserver %[2]s",
+		remediation: "migrate or remove objects using API versions not served by %[2]s before the rollback",
+	},
+	{
+		message:     "flags, config fields and feature gates introduced in %[1]s are rejected by %[2]s components",
+		remediation: "remove flags, config fields and feature gates not known to %[2]s from the machine config",
+	},
+	{
+		message:     "features enabled by default in %[1]s are disabled in %[2]s, objects relying on them might stop working",
+		remediation: "verify that the workloads don't depend on features not enabled in %[2]s",
+	},
+}
+
+// PopulateRollback reports what might break when rolling back to the previous minor version.
+//
+// Nothing is reported for upgrades and patch-level rollbacks.
+func (e *ComponentRemovedItemsError) PopulateRollback(path *Path) {
+	if !path.IsMinorDowngrade() {
+		return
+	}
+
+	from := fmt.Sprintf("%d.%d", path.from.Major, path.from.Minor)
+	to := fmt.Sprintf("%d.%d", path.to.Major, path.to.Minor)
+
+	for _, check := range rollbackChecks {
+		e.Findings = append(e.Findings, Finding{
+			Check:       CheckRollback,
+			Message:     fmt.Sprintf(check.message, from, to),
+			Remediation: fmt.Sprintf(check.remediation, from, to),
+			DocURL:      versionSkewDocURL,
+			Severity:    SeverityWarning,
+		})
+	}
+}
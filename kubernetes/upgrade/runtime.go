@@ -0,0 +1,120 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const talosSupportMatrixURL = "https://www.talos.dev/latest/introduction/support-matrix/"
+
+// runtimeRequirement is the minimum container runtime version expected by Kubernetes starting with some version.
+type runtimeRequirement struct {
+	since   semver.Version
+	runtime string
+	version semver.Version
+	message string
+	docURL  string
+}
+
+// runtimeRequirements are checked for the nodes in the order they are defined.
+var runtimeRequirements = []runtimeRequirement{
+	{
+		since:   semver.MustParse("1.26.0"),
+		runtime: "containerd",
+		version: semver.MustParse("1.6.0"),
+		message: "kubelet requires CRI v1 API since Kubernetes 1.26, which is supported by containerd 1.6 or later",
+		docURL:  "https://kubernetes.io/blog/2022/11/18/upcoming-changes-in-kubernetes-1-26/#cri-api-removal",
+	},
+}
+
+// talosMaxKubernetesVersions is the latest Kubernetes version supported by each Talos version.
+var talosMaxKubernetesVersions = map[string]semver.Version{
+	"1.5":  semver.MustParse("1.28.0"),
+	"1.6":  semver.MustParse("1.29.0"),
+	"1.7":  semver.MustParse("1.30.0"),
+	"1.8":  semver.MustParse("1.31.0"),
+	"1.9":  semver.MustParse("1.32.0"),
+	"1.10": semver.MustParse("1.33.0"),
+	"1.11": semver.MustParse("1.34.0"),
+}
+
+// talosOSImageRe matches the Talos version in the node OS image, e.g. Talos (v1.8.3).
+var talosOSImageRe = regexp.MustCompile(`^Talos \(v(\d+)\.(\d+)\.[^)]*\)$`)
+
+// PopulateNodeRuntime validates the container runtime and Talos versions of the nodes against the upgrade version.
+//
+// The versions are taken from the Kubernetes Node status, as reported by kubelet.
+// kubelet versions are validated by the version skew check.
+func (e *ComponentRemovedItemsError) PopulateNodeRuntime(ctx context.Context, clientset kubernetes.Interface, path *Path) error {
+	target := semver.Version{Major: path.to.Major, Minor: path.to.Minor}
+
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("error listing nodes: %w", err)
+	}
+
+	for _, node := range nodes.Items {
+		info := node.Status.NodeInfo
+
+		if matches := talosOSImageRe.FindStringSubmatch(info.OSImage); matches != nil {
+			talosVersion := matches[1] + "." + matches[2]
+
+			if maxVersion, ok := talosMaxKubernetesVersions[talosVersion]; ok && target.GT(maxVersion) {
+				e.Findings = append(e.Findings, Finding{
+					Check:   CheckNodeRuntime,
+					Node:    node.Name,
+					Message: fmt.Sprintf("%s supports Kubernetes up to %d.%d", info.OSImage, maxVersion.Major, maxVersion.Minor),
+					Remediation: fmt.Sprintf("upgrade Talos on the node to a version supporting Kubernetes %d.%d before upgrading Kubernetes",
+						target.Major, target.Minor),
+					DocURL:   talosSupportMatrixURL,
+					Severity: SeverityWarning,
+				})
+			}
+		}
+
+		runtime, version, ok := strings.Cut(info.ContainerRuntimeVersion, "://")
+		if !ok {
+			continue
+		}
+
+		runtimeVersion, err := semver.ParseTolerant(version)
+		if err != nil {
+			continue
+		}
+
+		runtimeVersion = semver.Version{Major: runtimeVersion.Major, Minor: runtimeVersion.Minor, Patch: runtimeVersion.Patch}
+
+		for _, requirement := range runtimeRequirements {
+			if runtime != requirement.runtime || target.LT(requirement.since) || runtimeVersion.GTE(requirement.version) {
+				continue
+			}
+
+			e.Findings = append(e.Findings, Finding{
+				Check:       CheckNodeRuntime,
+				Node:        node.Name,
+				Component:   requirement.runtime,
+				Message:     fmt.Sprintf("node container runtime %s is older than %s: %s", info.ContainerRuntimeVersion, requirement.version, requirement.message),
+				Remediation: fmt.Sprintf("upgrade the node operating system to a version with %s %s or later before upgrading kubelet", requirement.runtime, requirement.version),
+				DocURL:      requirement.docURL,
+				Severity:    SeverityError,
+			})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/siderolabs/talos/pkg/machinery/resources/k8s"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/siderolabs/go-kubernetes/kubernetes/compatibility"
+)
+
+const versionSkewDocURL = "https://kubernetes.io/releases/version-skew-policy/"
+
+// componentVersion is a version of a running component instance.
+type componentVersion struct {
+	node    string
+	version semver.Version
+}
+
+// PopulateVersionSkew validates the version skew policy for the upgrade version against the running components.
+//
+// The following rules are checked against the upgrade version of kube-apiserver:
+//   - kube-apiserver instances are at most one minor version older (so that they are within one minor version of each other during the upgrade);
+//   - kube-controller-manager and kube-scheduler are at most one minor version older;
+//   - kubelet is at most three (two before 1.28) minor versions older;
+//   - no component is newer than the upgrade version.
+//
+// Control plane component versions are taken from the image tags of the pods in the kube-system namespace.
+func (e *ComponentRemovedItemsError) PopulateVersionSkew(ctx context.Context, clientset kubernetes.Interface, path *Path) error {
+	target := semver.Version{Major: path.to.Major, Minor: path.to.Minor}
+
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("error listing nodes: %w", err)
+	}
+
+	for _, node := range nodes.Items {
+		version, err := semver.ParseTolerant(node.Status.NodeInfo.KubeletVersion)
+		if err != nil {
+			continue
+		}
+
+		e.checkSkew(componentVersion{node: node.Name, version: version}, k8s.KubeletID, target, compatibility.ValidateKubeletSkew)
+	}
+
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("error listing pods: %w", err)
+	}
+
+	controlPlane := map[string][]componentVersion{}
+
+	for _, pod := range pods.Items {
+		id := pod.Labels["k8s-app"]
+		if id == "" {
+			// kubeadm labels
+			id = pod.Labels["component"]
+		}
+
+		switch id {
+		case k8s.APIServerID, k8s.ControllerManagerID, k8s.SchedulerID:
+		default:
+			continue
+		}
+
+		for _, container := range pod.Spec.Containers {
+			if container.Name != id {
+				continue
+			}
+
+			version, ok := imageVersion(container.Image)
+			if !ok {
+				continue
+			}
+
+			controlPlane[id] = append(controlPlane[id], componentVersion{node: pod.Spec.NodeName, version: version})
+		}
+	}
+
+	for _, component := range []struct {
+		validate func(apiServer, version compatibility.Version) error
+		id       string
+	}{
+		{id: k8s.APIServerID, validate: compatibility.ValidateAPIServerSkew},
+		{id: k8s.ControllerManagerID, validate: compatibility.ValidateControllerManagerSkew},
+		{id: k8s.SchedulerID, validate: compatibility.ValidateSchedulerSkew},
+	} {
+		for _, instance := range controlPlane[component.id] {
+			e.checkSkew(instance, component.id, target, component.validate)
+		}
+	}
+
+	return nil
+}
+
+// checkSkew reports the component instance if it doesn't satisfy the skew policy against the target version.
+func (e *ComponentRemovedItemsError) checkSkew(
+	instance componentVersion,
+	component string,
+	target semver.Version,
+	validate func(apiServer, version compatibility.Version) error,
+) {
+	var skewErr *compatibility.SkewError
+
+	if !errors.As(validate(compatibility.Version(target), compatibility.Version(instance.version)), &skewErr) {
+		return
+	}
+
+	if skewErr.Newer {
+		e.Findings = append(e.Findings, Finding{
+			Check:       CheckVersionSkew,
+			Node:        instance.node,
+			Component:   component,
+			Message:     fmt.Sprintf("%s %s is newer than the upgrade version %d.%d", component, instance.version, target.Major, target.Minor),
+			Remediation: "downgrading components is not supported",
+			DocURL:      versionSkewDocURL,
+		})
+
+		return
+	}
+
+	e.Findings = append(e.Findings, Finding{
+		Check:     CheckVersionSkew,
+		Node:      instance.node,
+		Component: component,
+		Message: fmt.Sprintf("%s %s is more than %d minor version(s) older than the upgrade version %d.%d",
+			component, instance.version, skewErr.MaxSkew, target.Major, target.Minor),
+		Remediation: fmt.Sprintf("upgrade %s to at least %d.%d first", component, target.Major, max(int64(target.Minor)-int64(skewErr.MaxSkew), 0)),
+		DocURL:      versionSkewDocURL,
+	})
+}
+
+// imageVersion returns the version from the image tag.
+func imageVersion(image string) (semver.Version, bool) {
+	image, _, _ = strings.Cut(image, "@")
+
+	idx := strings.LastIndex(image, ":")
+	if idx == -1 || strings.Contains(image[idx:], "/") {
+		return semver.Version{}, false
+	}
+
+	version, err := semver.ParseTolerant(image[idx+1:])
+	if err != nil {
+		return semver.Version{}, false
+	}
+
+	return version, true
+}
@@ -0,0 +1,75 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Default timeouts of the checks.
+const (
+	DefaultCheckTimeout = 5 * time.Minute
+	DefaultNodeTimeout  = time.Minute
+)
+
+// WithCheckTimeout sets the timeout of each check (default is DefaultCheckTimeout), zero disables the timeout.
+//
+// A check which doesn't complete in time is reported as a finding, and the other checks still run.
+func WithCheckTimeout(timeout time.Duration) ChecksOption {
+	return func(o *ChecksOptions) {
+		o.CheckTimeout = timeout
+	}
+}
+
+// WithNodeTimeout sets the timeout of checking each node (default is DefaultNodeTimeout), zero disables the timeout.
+//
+// A node which can't be checked in time is reported as a finding, and the other nodes are still checked.
+func WithNodeTimeout(timeout time.Duration) ChecksOption {
+	return func(o *ChecksOptions) {
+		o.NodeTimeout = timeout
+	}
+}
+
+// withTimeout returns the context with the timeout, if the timeout is set.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// timedOut returns true if the error is caused by the timeout of the inner context, and not by the parent one.
+func timedOut(ctx, innerCtx context.Context, err error) bool {
+	return err != nil && ctx.Err() == nil && innerCtx.Err() != nil
+}
+
+// checkNode runs the check for the node with the node timeout.
+//
+// If the node can't be checked in time, it is reported as a finding instead of failing the check.
+func (checks *Checks) checkNode(ctx context.Context, check, node string, report *ComponentRemovedItemsError, fn func(ctx context.Context) error) error {
+	if err := checks.nodeProgress(ctx, check, node); err != nil {
+		return err
+	}
+
+	nodeCtx, cancel := withTimeout(ctx, checks.options.NodeTimeout)
+	defer cancel()
+
+	err := fn(nodeCtx)
+	if timedOut(ctx, nodeCtx, err) {
+		report.Findings = append(report.Findings, Finding{
+			Check:       check,
+			Node:        node,
+			Message:     fmt.Sprintf("node could not be checked in %s: %s", checks.options.NodeTimeout, err),
+			Remediation: "check the node health and connectivity, or increase the node timeout",
+		})
+
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,199 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/blang/semver/v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+const csiMigrationDocURL = "https://kubernetes.io/docs/concepts/storage/volumes/#csi-migration"
+
+// removedVolumePlugin is an in-tree volume plugin removed from Kubernetes.
+type removedVolumePlugin struct {
+	// name is the name of the volume source field, e.g. awsElasticBlockStore
+	name    string
+	removed semver.Version
+	// csiDriver is the CSI driver the volumes are migrated to, empty if there is no CSI migration
+	csiDriver string
+}
+
+var removedVolumePlugins = []removedVolumePlugin{
+	{name: "flocker", removed: semver.MustParse("1.25.0")},
+	{name: "quobyte", removed: semver.MustParse("1.25.0")},
+	{name: "storageos", removed: semver.MustParse("1.25.0")},
+	{name: "cinder", removed: semver.MustParse("1.26.0"), csiDriver: "cinder.csi.openstack.org"},
+	{name: "glusterfs", removed: semver.MustParse("1.26.0")},
+	{name: "awsElasticBlockStore", removed: semver.MustParse("1.27.0"), csiDriver: "ebs.csi.aws.com"},
+	{name: "azureDisk", removed: semver.MustParse("1.27.0"), csiDriver: "disk.csi.azure.com"},
+	{name: "gcePersistentDisk", removed: semver.MustParse("1.28.0"), csiDriver: "pd.csi.storage.gke.io"},
+	{name: "cephfs", removed: semver.MustParse("1.31.0")},
+	{name: "rbd", removed: semver.MustParse("1.31.0")},
+}
+
+// PopulateRemovedVolumePlugins reports PersistentVolumes and pods using the in-tree volume plugins removed in the upgrade version.
+//
+// Only the plugins removed after the current version and up to the upgrade version are reported,
+// the plugins removed earlier are already unusable in the cluster.
+// Volumes of the plugins with CSI migration keep working if the CSI driver is installed, so they are only
+// reported if the CSI driver is missing. Volumes of the plugins without CSI migration can't be mounted after the upgrade.
+func (e *ComponentRemovedItemsError) PopulateRemovedVolumePlugins(ctx context.Context, clientset kubernetes.Interface, path *Path) error {
+	current := semver.Version{Major: path.from.Major, Minor: path.from.Minor}
+	target := semver.Version{Major: path.to.Major, Minor: path.to.Minor}
+
+	removed := map[string]removedVolumePlugin{}
+
+	for _, plugin := range removedVolumePlugins {
+		if plugin.removed.LE(current) || plugin.removed.GT(target) {
+			continue
+		}
+
+		if plugin.csiDriver != "" {
+			_, err := clientset.StorageV1().CSIDrivers().Get(ctx, plugin.csiDriver, metav1.GetOptions{})
+
+			switch {
+			case err == nil:
+				continue
+			case apierrors.IsNotFound(err):
+			case apierrors.IsForbidden(err):
+				return nil
+			default:
+				return fmt.Errorf("error getting CSI driver %q: %w", plugin.csiDriver, err)
+			}
+		}
+
+		removed[plugin.name] = plugin
+	}
+
+	if len(removed) == 0 {
+		return nil
+	}
+
+	pvs, err := clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("error listing persistent volumes: %w", err)
+	}
+
+	for _, pv := range pvs.Items {
+		subject := "PersistentVolume " + pv.Name
+
+		if pv.Spec.ClaimRef != nil {
+			subject += fmt.Sprintf(" (claim %s/%s)", pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name)
+		}
+
+		names, err := removedVolumePluginNames(&pv.Spec.PersistentVolumeSource, removed)
+		if err != nil {
+			return err
+		}
+
+		e.populateVolumePlugins(subject, names, removed)
+	}
+
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("error listing pods: %w", err)
+	}
+
+	seen := map[string]struct{}{}
+
+	for _, pod := range pods.Items {
+		var names []string
+
+		for _, volume := range pod.Spec.Volumes {
+			volumeNames, err := removedVolumePluginNames(&volume.VolumeSource, removed)
+			if err != nil {
+				return err
+			}
+
+			names = append(names, volumeNames...)
+		}
+
+		if len(names) == 0 {
+			continue
+		}
+
+		// the workload is only resolved for the affected pods, as it might require a request per pod
+		workload, err := podWorkload(ctx, clientset, &pod)
+		if err != nil {
+			if !apierrors.IsForbidden(err) {
+				return err
+			}
+
+			workload = "Pod/" + pod.Name
+		}
+
+		subject := fmt.Sprintf("%s in namespace %s", workload, pod.Namespace)
+
+		if _, ok := seen[subject]; ok {
+			continue
+		}
+
+		seen[subject] = struct{}{}
+
+		slices.Sort(names)
+
+		e.populateVolumePlugins(subject, slices.Compact(names), removed)
+	}
+
+	return nil
+}
+
+// removedVolumePluginNames returns the sorted names of the removed volume plugins used by the volume source.
+func removedVolumePluginNames(source any, removed map[string]removedVolumePlugin) ([]string, error) {
+	fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(source)
+	if err != nil {
+		return nil, fmt.Errorf("error converting volume source: %w", err)
+	}
+
+	var names []string
+
+	for name := range fields {
+		if _, ok := removed[name]; ok {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// populateVolumePlugins reports the removed volume plugins used by the subject.
+func (e *ComponentRemovedItemsError) populateVolumePlugins(subject string, names []string, removed map[string]removedVolumePlugin) {
+	for _, name := range names {
+		plugin := removed[name]
+
+		finding := Finding{
+			Check:    CheckRemovedVolumePlugins,
+			Message:  fmt.Sprintf("%s uses in-tree volume plugin %s removed in %d.%d", subject, name, plugin.removed.Major, plugin.removed.Minor),
+			DocURL:   csiMigrationDocURL,
+			Severity: SeverityError,
+			Item:     name,
+		}
+
+		if plugin.csiDriver != "" {
+			finding.Remediation = fmt.Sprintf("install the %s CSI driver before upgrading, the volumes are migrated to it", plugin.csiDriver)
+		} else {
+			finding.Remediation = "migrate the data to a volume provisioned by a CSI driver before upgrading"
+		}
+
+		e.Findings = append(e.Findings, finding)
+	}
+}
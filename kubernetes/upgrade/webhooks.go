@@ -0,0 +1,164 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const admissionWebhooksURL = "https://kubernetes.io/docs/reference/access-authn-authz/extensible-admission-controllers/#failure-policy"
+
+// admissionWebhook is a common view of validating and mutating webhooks.
+type admissionWebhook struct {
+	configuration string
+	name          string
+	clientConfig  admissionregistrationv1.WebhookClientConfig
+	failurePolicy *admissionregistrationv1.FailurePolicyType
+	rules         []admissionregistrationv1.RuleWithOperations
+}
+
+// PopulateWebhooks audits the admission webhooks which might block the control plane components restarts during the upgrade.
+//
+// The following webhooks are reported:
+//   - webhooks backed by a service without ready endpoints (an error if the failure policy is Fail, a warning otherwise);
+//   - webhooks with Fail failure policy intercepting core API group resources (a warning).
+//
+// Webhooks configured with a URL are not checked for availability.
+func (e *ComponentRemovedItemsError) PopulateWebhooks(ctx context.Context, clientset kubernetes.Interface) error {
+	webhooks, err := listWebhooks(ctx, clientset)
+	if err != nil {
+		if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		failurePolicyFail := webhook.failurePolicy == nil || *webhook.failurePolicy == admissionregistrationv1.Fail
+
+		if svc := webhook.clientConfig.Service; svc != nil {
+			ready, err := serviceHasReadyEndpoints(ctx, clientset, svc.Namespace, svc.Name)
+			if err != nil {
+				if apierrors.IsForbidden(err) {
+					return nil
+				}
+
+				return err
+			}
+
+			if !ready {
+				severity := SeverityWarning
+
+				if failurePolicyFail {
+					severity = SeverityError
+				}
+
+				e.Findings = append(e.Findings, Finding{
+					Check:       CheckAdmissionWebhooks,
+					Message:     fmt.Sprintf("%s webhook %s: service %s/%s has no ready endpoints", webhook.configuration, webhook.name, svc.Namespace, svc.Name),
+					Remediation: "fix the webhook service or remove the webhook configuration before upgrading",
+					DocURL:      admissionWebhooksURL,
+					Severity:    severity,
+				})
+
+				// no need to report the failure policy as well
+				continue
+			}
+		}
+
+		if failurePolicyFail && interceptsCoreResources(webhook.rules) {
+			e.Findings = append(e.Findings, Finding{
+				Check:       CheckAdmissionWebhooks,
+				Message:     fmt.Sprintf("%s webhook %s has failure policy Fail for core API resources", webhook.configuration, webhook.name),
+				Remediation: "consider failure policy Ignore or excluding kube-system namespace, an unavailable webhook blocks control plane components restarts",
+				DocURL:      admissionWebhooksURL,
+				Severity:    SeverityWarning,
+			})
+		}
+	}
+
+	return nil
+}
+
+func listWebhooks(ctx context.Context, clientset kubernetes.Interface) ([]admissionWebhook, error) {
+	var webhooks []admissionWebhook
+
+	validating, err := clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing validating webhook configurations: %w", err)
+	}
+
+	for _, configuration := range validating.Items {
+		for _, webhook := range configuration.Webhooks {
+			webhooks = append(webhooks, admissionWebhook{
+				configuration: "ValidatingWebhookConfiguration " + configuration.Name,
+				name:          webhook.Name,
+				clientConfig:  webhook.ClientConfig,
+				failurePolicy: webhook.FailurePolicy,
+				rules:         webhook.Rules,
+			})
+		}
+	}
+
+	mutating, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing mutating webhook configurations: %w", err)
+	}
+
+	for _, configuration := range mutating.Items {
+		for _, webhook := range configuration.Webhooks {
+			webhooks = append(webhooks, admissionWebhook{
+				configuration: "MutatingWebhookConfiguration " + configuration.Name,
+				name:          webhook.Name,
+				clientConfig:  webhook.ClientConfig,
+				failurePolicy: webhook.FailurePolicy,
+				rules:         webhook.Rules,
+			})
+		}
+	}
+
+	return webhooks, nil
+}
+
+// serviceHasReadyEndpoints returns true if the service has at least one ready endpoint.
+func serviceHasReadyEndpoints(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (bool, error) {
+	endpointSlices, err := clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + name,
+	})
+	if err != nil {
+		return false, fmt.Errorf("error listing endpoint slices: %w", err)
+	}
+
+	for _, slice := range endpointSlices.Items {
+		for _, endpoint := range slice.Endpoints {
+			// nil means ready
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// interceptsCoreResources returns true if any of the rules matches core API group resources.
+func interceptsCoreResources(rules []admissionregistrationv1.RuleWithOperations) bool {
+	for _, rule := range rules {
+		if slices.Contains(rule.APIGroups, "") || slices.Contains(rule.APIGroups, "*") {
+			return true
+		}
+	}
+
+	return false
+}